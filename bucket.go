@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
+	"strconv"
+	"time"
+)
+
+// bucket prints the bucket of each timestamp given as argument or, without
+// arguments, read from r, one per line.
+func bucket(w io.Writer, r io.Reader, o BucketOptions, args []string) error {
+	if o.size == "" {
+		return usageError("--size is required")
+	}
+	size, err := ut.ParseBucketSize(o.size)
+	if err != nil {
+		return err
+	}
+
+	epoch, precision, err := o.options.EpochPrecision()
+	if err != nil {
+		return err
+	}
+
+	origin := size.DefaultOrigin()
+	if o.origin != "" {
+		if origin, err = epoch.Parse(o.origin, precision); err != nil {
+			return err
+		}
+		// calendar buckets follow the date of the origin in the target zone
+		if origin, err = transform(origin, o.options); err != nil {
+			return err
+		}
+	}
+
+	table, err := tableOutput(o.options)
+	if err != nil {
+		return err
+	}
+	var rows []tableRow
+
+	format, _ := o.options.Format()
+	emit := func(value string) error {
+		t, err := epoch.Parse(value, precision)
+		if err != nil {
+			return err
+		}
+		if t, err = transform(t, o.options); err != nil {
+			return err
+		}
+
+		b, err := size.Bucket(t, origin)
+		if err != nil {
+			return err
+		}
+		if table {
+			rows = append(rows, tableRow{input: value, t: b.Start, end: b.End, index: strconv.FormatInt(b.Index, 10)})
+			return nil
+		}
+
+		var line string
+		if o.dates {
+			line = ut.Format(b.Start, format) + "\t" + ut.Format(b.End, format)
+		} else {
+			line = epoch.Format(b.Start, precision) + "\t" + epoch.Format(b.End, precision)
+		}
+		if _, err := fmt.Fprintln(w, line+"\t"+strconv.FormatInt(b.Index, 10)); err != nil {
+			return ioError(err)
+		}
+
+		return nil
+	}
+
+	if err := eachValue(r, args, emit); err != nil || !table {
+		return err
+	}
+
+	// a row for the bucket of each value
+	return printTable(w, rows, o.options, time.Now())
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"github.com/lsmoura/ut-cli/ut"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestBucket(t *testing.T) {
+	tests := []struct {
+		options  Options
+		args     []string
+		input    string
+		expected string
+	}{
+		{Options{}, []string{"-s", "15m", "1680717044", "1680717900"}, "", "1680716700\t1680717600\t1867463\n1680717600\t1680718500\t1867464\n"},
+		{Options{}, []string{"--size", "15m"}, "1680717044\n\n1680717900\n", "1680716700\t1680717600\t1867463\n1680717600\t1680718500\t1867464\n"},
+		{Options{precision: "ms"}, []string{"-s", "1h", "-O", "1800000", "1680717044000"}, "", "1680715800000\t1680719400000\t466865\n"},
+		{Options{offset: "America/New_York"}, []string{"-D", "-s", "week", "1680717044"}, "", "2023-04-03 00:00:00 -0400 EDT\t2023-04-10 00:00:00 -0400 EDT\t2778\n"},
+		// the origin is read in the target zone: 1680717044 is a Wednesday in New York, a Thursday in Tokyo
+		{Options{offset: "Asia/Tokyo", format: "%Y-%m-%d"}, []string{"-D", "-s", "week", "-O", "1680717044", "1680717044"}, "", "2023-04-06\t2023-04-13\t0\n"},
+	}
+
+	for _, test := range tests {
+		o := BucketOptions{options: test.options}
+		args, err := o.Parse(append([]string{"bucket"}, test.args...)...)
+		require.NoError(t, err)
+
+		var buf strings.Builder
+		require.NoError(t, bucket(&buf, strings.NewReader(test.input), o, args))
+		assert.Equal(t, test.expected, buf.String(), test.args)
+	}
+}
+
+func TestBucketErrors(t *testing.T) {
+	err := bucket(&strings.Builder{}, strings.NewReader(""), BucketOptions{}, []string{"1"})
+	code, _ := describeError(err)
+	assert.Equal(t, exitUsage, code)
+
+	err = bucket(&strings.Builder{}, strings.NewReader(""), BucketOptions{size: "fortnight"}, []string{"1"})
+	assert.ErrorIs(t, err, ut.ErrInvalidBucketSize)
+	code, _ = describeError(err)
+	assert.Equal(t, exitUsage, code)
+
+	err = bucket(&strings.Builder{}, strings.NewReader("12\nabc\n"), BucketOptions{size: "1h"}, nil)
+	assert.ErrorIs(t, err, ut.ErrInvalidTimestamp)
+}
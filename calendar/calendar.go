@@ -0,0 +1,123 @@
+// Package calendar converts instants to calendars other than the Gregorian
+// one of the time package: Julian day numbers, the proleptic Julian calendar,
+// the tabular Hijri calendar and the eras of the Japanese calendar.
+//
+// Calendar dates are computed from the date of the time in its own location,
+// like the methods of time.Time; Julian dates are computed from the instant.
+package calendar
+
+import (
+	"math/big"
+	"time"
+)
+
+// unixJDN is the Julian day number of 1970-01-01.
+const unixJDN = 2440588
+
+// floorDiv divides rounding towards negative infinity, so the formulas below
+// work for dates before their epochs.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// JulianDayNumber returns the Julian day number of the date of t: the number
+// of days since Monday, January 1, 4713 BC in the proleptic Julian calendar.
+func JulianDayNumber(t time.Time) int64 {
+	y, m, d := t.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return floorDiv(midnight.Unix(), 86400) + unixJDN
+}
+
+// JulianDate returns the astronomical Julian date of the instant t, counted
+// in days, with fractions, since noon UTC of the first Julian day.
+func JulianDate(t time.Time) *big.Rat {
+	return days(t, 2*unixJDN-1, 2)
+}
+
+// ModifiedJulianDate returns the Modified Julian date of the instant t,
+// counted in days, with fractions, since 1858-11-17 UTC.
+func ModifiedJulianDate(t time.Time) *big.Rat {
+	return days(t, 40587, 1)
+}
+
+// days returns the days elapsed since 1970-01-01 UTC plus num/den days.
+func days(t time.Time, num int64, den int64) *big.Rat {
+	nanos := new(big.Int).Mul(big.NewInt(t.Unix()), big.NewInt(int64(time.Second)))
+	nanos.Add(nanos, big.NewInt(int64(t.Nanosecond())))
+
+	r := new(big.Rat).SetFrac(nanos, big.NewInt(86400*int64(time.Second)))
+	return r.Add(r, big.NewRat(num, den))
+}
+
+// Julian returns the date of t in the proleptic Julian calendar, in use in
+// Europe before the Gregorian reform of 1582. Years before 1 AD are
+// astronomical: 0 is 1 BC.
+func Julian(t time.Time) (year int, month time.Month, day int) {
+	c := JulianDayNumber(t) + 32082
+	d := floorDiv(4*c+3, 1461)
+	e := c - floorDiv(1461*d, 4)
+	m := floorDiv(5*e+2, 153)
+
+	day = int(e - floorDiv(153*m+2, 5) + 1)
+	month = time.Month(m + 3 - 12*floorDiv(m, 10))
+	year = int(d - 4800 + floorDiv(m, 10))
+	return year, month, day
+}
+
+// Hijri returns the date of t in the tabular Islamic calendar, with the
+// civil epoch of July 16, 622 (Julian) and 11 leap years in each cycle of 30.
+// Religious observance follows the sighting of the moon and may differ from
+// it by a day or two.
+func Hijri(t time.Time) (year int, month int, day int) {
+	l := JulianDayNumber(t) - 1948440 + 10632
+	n := floorDiv(l-1, 10631)
+	l = l - 10631*n + 354
+	j := floorDiv(10985-l, 5316)*floorDiv(50*l, 17719) + floorDiv(l, 5670)*floorDiv(43*l, 15238)
+	l = l - floorDiv(30-j, 15)*floorDiv(17719*j, 50) - floorDiv(j, 16)*floorDiv(15238*j, 43) + 29
+	m := floorDiv(24*l, 709)
+
+	day = int(l - floorDiv(709*m, 24))
+	month = int(m)
+	year = int(30*n + j - 30)
+	return year, month, day
+}
+
+// era is an era of the Japanese calendar, starting on the given Gregorian date.
+type era struct {
+	name  string
+	year  int
+	month time.Month
+	day   int
+}
+
+// eras lists the Japanese eras since the adoption of the Gregorian calendar,
+// most recent first.
+var eras = []era{
+	{"Reiwa", 2019, time.May, 1},
+	{"Heisei", 1989, time.January, 8},
+	{"Showa", 1926, time.December, 25},
+	{"Taisho", 1912, time.July, 30},
+	{"Meiji", 1868, time.October, 23},
+}
+
+// JapaneseEra returns the era of the Japanese calendar and the year within
+// that era for the date of t. Japan adopted the Gregorian calendar on Meiji 6
+// (1873); ok is false for earlier dates.
+func JapaneseEra(t time.Time) (name string, year int, ok bool) {
+	y, m, d := t.Date()
+	if y < 1873 {
+		return "", 0, false
+	}
+
+	for _, e := range eras {
+		if y > e.year || (y == e.year && (m > e.month || (m == e.month && d >= e.day))) {
+			return e.name, y - e.year + 1, true
+		}
+	}
+
+	return "", 0, false
+}
@@ -0,0 +1,102 @@
+package calendar
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestJulianDayNumber(t *testing.T) {
+	tests := []struct {
+		time     time.Time
+		expected int64
+	}{
+		{time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC), 2460040},
+		{time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), 2451545},
+		{time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), 2440588},
+		{time.Date(1858, 11, 17, 0, 0, 0, 0, time.UTC), 2400001},
+		{time.Date(1582, 10, 15, 0, 0, 0, 0, time.UTC), 2299161},
+		{time.Date(-4713, 11, 24, 0, 0, 0, 0, time.UTC), 0},
+		{time.Date(-4713, 11, 23, 0, 0, 0, 0, time.UTC), -1},
+		// the date in the location counts, not the instant
+		{time.Date(2023, 4, 6, 2, 50, 44, 0, time.FixedZone("JST", 9*3600)), 2460041},
+	}
+
+	for _, test := range tests {
+		assert.Equalf(t, test.expected, JulianDayNumber(test.time), "JDN of %s", test.time)
+	}
+}
+
+func TestJulianDate(t *testing.T) {
+	assert.Equal(t, "2451545.0", JulianDate(time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)).FloatString(1))
+	assert.Equal(t, "2440587.5", JulianDate(time.Unix(0, 0)).FloatString(1))
+	assert.Equal(t, "0.0", JulianDate(time.Date(-4713, 11, 24, 12, 0, 0, 0, time.UTC)).FloatString(1))
+	assert.Equal(t, "0.00", ModifiedJulianDate(time.Date(1858, 11, 17, 0, 0, 0, 0, time.UTC)).FloatString(2))
+	assert.Equal(t, "60039.75", ModifiedJulianDate(time.Date(2023, 4, 5, 18, 0, 0, 0, time.UTC)).FloatString(2))
+	assert.Equal(t, big.NewRat(1, 86400*int64(time.Second)), new(big.Rat).Sub(
+		ModifiedJulianDate(time.Date(2023, 4, 5, 18, 0, 0, 1, time.UTC)),
+		ModifiedJulianDate(time.Date(2023, 4, 5, 18, 0, 0, 0, time.UTC)),
+	), "fractions are exact to the nanosecond")
+}
+
+func TestJulian(t *testing.T) {
+	tests := []struct {
+		time  time.Time
+		year  int
+		month time.Month
+		day   int
+	}{
+		{time.Date(1582, 10, 15, 0, 0, 0, 0, time.UTC), 1582, time.October, 5},
+		{time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC), 2023, time.March, 23},
+		{time.Date(0, 12, 30, 0, 0, 0, 0, time.UTC), 1, time.January, 1},
+		{time.Date(-4713, 11, 24, 0, 0, 0, 0, time.UTC), -4712, time.January, 1},
+		{time.Date(-10000, 3, 1, 0, 0, 0, 0, time.UTC), -10000, time.May, 17},
+	}
+
+	for _, test := range tests {
+		year, month, day := Julian(test.time)
+		assert.Equalf(t, []int{test.year, int(test.month), test.day}, []int{year, int(month), day}, "Julian date of %s", test.time)
+	}
+}
+
+func TestHijri(t *testing.T) {
+	tests := []struct {
+		time             time.Time
+		year, month, day int
+	}{
+		{time.Date(622, 7, 19, 0, 0, 0, 0, time.UTC), 1, 1, 1},
+		{time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), 1420, 9, 24},
+		{time.Date(2023, 3, 23, 0, 0, 0, 0, time.UTC), 1444, 9, 1},
+		{time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC), 1444, 9, 14},
+	}
+
+	for _, test := range tests {
+		year, month, day := Hijri(test.time)
+		assert.Equalf(t, []int{test.year, test.month, test.day}, []int{year, month, day}, "Hijri date of %s", test.time)
+	}
+}
+
+func TestJapaneseEra(t *testing.T) {
+	tests := []struct {
+		time time.Time
+		name string
+		year int
+		ok   bool
+	}{
+		{time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC), "Reiwa", 5, true},
+		{time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC), "Reiwa", 1, true},
+		{time.Date(2019, 4, 30, 0, 0, 0, 0, time.UTC), "Heisei", 31, true},
+		{time.Date(1989, 1, 7, 0, 0, 0, 0, time.UTC), "Showa", 64, true},
+		{time.Date(1912, 7, 30, 0, 0, 0, 0, time.UTC), "Taisho", 1, true},
+		{time.Date(1873, 1, 1, 0, 0, 0, 0, time.UTC), "Meiji", 6, true},
+		{time.Date(1872, 12, 31, 0, 0, 0, 0, time.UTC), "", 0, false},
+	}
+
+	for _, test := range tests {
+		name, year, ok := JapaneseEra(test.time)
+		assert.Equalf(t, test.ok, ok, "era of %s", test.time)
+		assert.Equalf(t, test.name, name, "era of %s", test.time)
+		assert.Equalf(t, test.year, year, "era of %s", test.time)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// clipboardProvider is a command line tool reading and writing the system
+// clipboard, usable when its display variable, if any, is set.
+type clipboardProvider struct {
+	copy  []string
+	paste []string
+	env   string
+}
+
+// clipboardProviders are tried in order.
+var clipboardProviders = []clipboardProvider{
+	{copy: []string{"wl-copy"}, paste: []string{"wl-paste", "--no-newline"}, env: "WAYLAND_DISPLAY"},
+	{copy: []string{"xclip", "-selection", "clipboard"}, paste: []string{"xclip", "-selection", "clipboard", "-o"}, env: "DISPLAY"},
+	{copy: []string{"xsel", "--clipboard", "--input"}, paste: []string{"xsel", "--clipboard", "--output"}, env: "DISPLAY"},
+	{copy: []string{"pbcopy"}, paste: []string{"pbpaste"}},
+}
+
+// clipboard copies to and pastes from the system clipboard through the
+// first available provider. Copies fall back to the OSC 52 escape sequence,
+// which asks the terminal to set its clipboard, and always use it over SSH,
+// where the providers would reach the clipboard of the remote machine.
+type clipboard struct {
+	getenv   func(string) string
+	lookPath func(string) (string, error)
+	run      func(args []string, stdin io.Reader) error
+	output   func(args []string) ([]byte, error)
+	terminal func() (io.WriteCloser, error)
+}
+
+// systemClipboard returns the clipboard of the environment ut runs in.
+func systemClipboard() *clipboard {
+	return &clipboard{
+		getenv:   os.Getenv,
+		lookPath: exec.LookPath,
+		// xclip and wl-copy fork a child serving the clipboard, which keeps
+		// the stdout it inherits: waiting for the end of a captured output
+		// would last until another program takes the clipboard.
+		run: func(args []string, stdin io.Reader) error {
+			cmd := exec.Command(args[0], args[1:]...)
+			cmd.Stdin = stdin
+			cmd.Stderr = os.Stderr
+			return cmd.Run()
+		},
+		output: func(args []string) ([]byte, error) {
+			cmd := exec.Command(args[0], args[1:]...)
+			cmd.Stderr = os.Stderr
+			return cmd.Output()
+		},
+		terminal: func() (io.WriteCloser, error) {
+			return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+		},
+	}
+}
+
+// command returns the copy or paste command of the first usable provider.
+func (c *clipboard) command(paste bool) ([]string, bool) {
+	for _, p := range clipboardProviders {
+		if p.env != "" && c.getenv(p.env) == "" {
+			continue
+		}
+		args := p.copy
+		if paste {
+			args = p.paste
+		}
+		if _, err := c.lookPath(args[0]); err == nil {
+			return args, true
+		}
+	}
+
+	return nil, false
+}
+
+func (c *clipboard) overSSH() bool {
+	return c.getenv("SSH_TTY") != "" || c.getenv("SSH_CONNECTION") != ""
+}
+
+// copy puts text on the clipboard.
+func (c *clipboard) copy(text string) error {
+	if args, ok := c.command(false); ok && !c.overSSH() {
+		if err := c.run(args, strings.NewReader(text)); err != nil {
+			return ioError(fmt.Errorf("%s: %w", args[0], err))
+		}
+		return nil
+	}
+
+	tty, err := c.terminal()
+	if err != nil {
+		return ioError(fmt.Errorf("no clipboard: install wl-clipboard, xclip or xsel, or use a terminal supporting OSC 52 (%w)", err))
+	}
+	defer tty.Close()
+
+	_, err = io.WriteString(tty, osc52(text, c.getenv("TMUX") != ""))
+	return ioError(err)
+}
+
+// paste returns the text on the clipboard.
+func (c *clipboard) paste() (string, error) {
+	args, ok := c.command(true)
+	if !ok {
+		return "", ioError(fmt.Errorf("no clipboard to paste from: install wl-clipboard, xclip or xsel"))
+	}
+
+	out, err := c.output(args)
+	if err != nil {
+		return "", ioError(fmt.Errorf("%s: %w", args[0], err))
+	}
+	return string(out), nil
+}
+
+// osc52 returns the escape sequence setting the clipboard of the terminal to
+// text. Inside tmux it is wrapped to pass through to the outer terminal.
+func osc52(text string, tmux bool) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	if !tmux {
+		return seq
+	}
+
+	return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
+
+// copyOutput runs fn, writing its output to w and, when enabled, copying it
+// to the clipboard without the trailing newline.
+func copyOutput(w io.Writer, enabled bool, c *clipboard, fn func(io.Writer) error) error {
+	if !enabled {
+		return fn(w)
+	}
+
+	var buf bytes.Buffer
+	if err := fn(&buf); err != nil {
+		return err
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return ioError(err)
+	}
+
+	return c.copy(strings.TrimSuffix(buf.String(), "\n"))
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClipboard is a clipboard whose environment, installed tools and
+// terminal are given, recording the commands it runs.
+type fakeClipboard struct {
+	env      map[string]string
+	tools    []string
+	content  string
+	ran      [][]string
+	terminal bytes.Buffer
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+func (f *fakeClipboard) clipboard() *clipboard {
+	return &clipboard{
+		getenv: func(name string) string { return f.env[name] },
+		lookPath: func(name string) (string, error) {
+			for _, tool := range f.tools {
+				if tool == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", exec.ErrNotFound
+		},
+		run: func(args []string, stdin io.Reader) error {
+			f.ran = append(f.ran, args)
+			data, err := io.ReadAll(stdin)
+			f.content = string(data)
+			return err
+		},
+		output: func(args []string) ([]byte, error) {
+			f.ran = append(f.ran, args)
+			return []byte(f.content), nil
+		},
+		terminal: func() (io.WriteCloser, error) {
+			return nopCloser{&f.terminal}, nil
+		},
+	}
+}
+
+func TestClipboardCopy(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		tools   []string
+		command []string
+		osc     string
+	}{
+		{"wayland", map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, []string{"wl-copy", "xclip"}, []string{"wl-copy"}, ""},
+		{"x11", map[string]string{"DISPLAY": ":0"}, []string{"wl-copy", "xsel"}, []string{"xsel", "--clipboard", "--input"}, ""},
+		{"macos", nil, []string{"pbcopy"}, []string{"pbcopy"}, ""},
+		{"no provider", map[string]string{"DISPLAY": ":0"}, nil, nil, "\x1b]52;c;MTY4MDcxNzA0NA==\a"},
+		{"ssh", map[string]string{"SSH_TTY": "/dev/pts/1"}, []string{"pbcopy"}, nil, "\x1b]52;c;MTY4MDcxNzA0NA==\a"},
+		{"tmux", map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0"}, nil, nil, "\x1bPtmux;\x1b\x1b]52;c;MTY4MDcxNzA0NA==\a\x1b\\"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeClipboard{env: tt.env, tools: tt.tools}
+			require.NoError(t, f.clipboard().copy("1680717044"))
+
+			if tt.command != nil {
+				assert.Equal(t, [][]string{tt.command}, f.ran)
+				assert.Equal(t, "1680717044", f.content)
+			} else {
+				assert.Empty(t, f.ran)
+			}
+			assert.Equal(t, tt.osc, f.terminal.String())
+		})
+	}
+
+	c := (&fakeClipboard{}).clipboard()
+	c.terminal = func() (io.WriteCloser, error) { return nil, errors.New("no terminal") }
+	code, _ := describeError(c.copy("1680717044"))
+	assert.Equal(t, exitIO, code)
+}
+
+func TestSystemClipboardForkingProvider(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+
+	// like xclip, the provider forks a child serving the clipboard, which
+	// keeps its stdout open
+	dir := t.TempDir()
+	copied := filepath.Join(dir, "copied")
+	script := "#!/bin/sh\n/bin/cat > " + copied + "\n/bin/sleep 5 2>/dev/null &\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "xclip"), []byte(script), 0755))
+	t.Setenv("PATH", dir)
+	t.Setenv("DISPLAY", ":0")
+	for _, name := range []string{"WAYLAND_DISPLAY", "SSH_TTY", "SSH_CONNECTION"} {
+		t.Setenv(name, "")
+	}
+
+	start := time.Now()
+	require.NoError(t, systemClipboard().copy("1680717044"))
+	assert.Less(t, time.Since(start), 2*time.Second, "copy waits for the forked child")
+
+	data, err := os.ReadFile(copied)
+	require.NoError(t, err)
+	assert.Equal(t, "1680717044", string(data))
+}
+
+func TestParseClipboard(t *testing.T) {
+	f := &fakeClipboard{env: map[string]string{"DISPLAY": ":0"}, tools: []string{"xclip"}, content: " 1680717044\n"}
+
+	var buf strings.Builder
+	require.NoError(t, parseClipboard(&buf, nil, ParseOptions{options: Options{utc: true}, paste: true, copy: true}, nil, f.clipboard()))
+	assert.Equal(t, "2023-04-05 17:50:44 +0000 UTC\n", buf.String())
+	assert.Equal(t, "2023-04-05 17:50:44 +0000 UTC", f.content)
+	assert.Equal(t, [][]string{{"xclip", "-selection", "clipboard", "-o"}, {"xclip", "-selection", "clipboard"}}, f.ran)
+
+	code, _ := describeError(parseClipboard(&buf, nil, ParseOptions{paste: true}, []string{"1"}, f.clipboard()))
+	assert.Equal(t, exitUsage, code)
+
+	code, _ = describeError(parseClipboard(&buf, nil, ParseOptions{paste: true}, nil, (&fakeClipboard{}).clipboard()))
+	assert.Equal(t, exitIO, code, "nothing to paste with")
+}
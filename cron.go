@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
+	"strings"
+	"time"
+)
+
+// cronTimes returns the next (or previous) fire times of the cron expression
+// after the base time, evaluated on the wall clock of the configured zone.
+func cronTimes(o CronOptions, args []string, now time.Time) ([]time.Time, error) {
+	if len(args) == 0 {
+		return nil, usageError("missing cron expression")
+	}
+	if o.count < 1 {
+		return nil, usageError("--count must be positive")
+	}
+
+	// unquoted expressions come as several arguments
+	schedule, err := ut.ParseCron(strings.Join(args, " "))
+	if err != nil {
+		return nil, err
+	}
+
+	layout, _ := o.options.Format()
+	t, err := ut.ParseBase(o.base, layout, now)
+	if err != nil {
+		return nil, err
+	}
+	if t, err = transform(t, o.options); err != nil {
+		return nil, err
+	}
+
+	times := make([]time.Time, 0, o.count)
+	for len(times) < o.count {
+		if o.previous {
+			t, err = schedule.Prev(t)
+		} else {
+			t, err = schedule.Next(t)
+		}
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+
+	return times, nil
+}
+
+func cron(w io.Writer, o CronOptions, args []string, now time.Time) error {
+	times, err := cronTimes(o, args, now)
+	if err != nil {
+		return err
+	}
+
+	epoch, precision, err := o.options.EpochPrecision()
+	if err != nil {
+		return err
+	}
+
+	if table, err := tableOutput(o.options); err != nil {
+		return err
+	} else if table {
+		rows := make([]tableRow, len(times))
+		for i, t := range times {
+			rows[i] = tableRow{t: t}
+		}
+		return printTable(w, rows, o.options, now)
+	}
+
+	format, _ := o.options.Format()
+	for _, t := range times {
+		line := epoch.Format(t, precision)
+		if o.verbose {
+			line += "  " + ut.Format(t, format)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return ioError(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/lsmoura/ut-cli/ut"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCron(t *testing.T) {
+	now := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	var buf strings.Builder
+	o := CronOptions{options: Options{utc: true}}
+	_, err := o.Parse("cron", "-n", "3", "-v")
+	require.NoError(t, err)
+	require.NoError(t, cron(&buf, o, []string{"0", "9", "*", "*", "1-5"}, now))
+	assert.Equal(t, "1680771600  2023-04-06 09:00:00 +0000 UTC\n"+
+		"1680858000  2023-04-07 09:00:00 +0000 UTC\n"+
+		"1681117200  2023-04-10 09:00:00 +0000 UTC\n", buf.String())
+
+	buf.Reset()
+	o = CronOptions{options: Options{offset: "Asia/Tokyo", precision: "ms"}}
+	_, err = o.Parse("cron", "--previous", "--count", "2", "--base", "2023-04-05T00:00:00Z")
+	require.NoError(t, err)
+	require.NoError(t, cron(&buf, o, []string{"@daily"}, now))
+	assert.Equal(t, "1680620400000\n1680534000000\n", buf.String())
+}
+
+func TestCronErrors(t *testing.T) {
+	now := time.Now()
+	o := CronOptions{}
+	_, err := o.Parse("cron")
+	require.NoError(t, err)
+
+	_, err = cronTimes(o, nil, now)
+	code, _ := describeError(err)
+	assert.Equal(t, exitUsage, code)
+
+	_, err = cronTimes(o, []string{"* * *"}, now)
+	assert.ErrorIs(t, err, ut.ErrInvalidCron)
+	code, _ = describeError(err)
+	assert.Equal(t, exitInvalidInput, code)
+
+	o.count = 0
+	_, err = cronTimes(o, []string{"@daily"}, now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// csvField is a field of a CSV record: its text as read, with its quotes if
+// any, and its value.
+type csvField struct {
+	raw    string
+	value  string
+	quoted bool
+}
+
+// csvReader reads CSV records one at a time, keeping the text of their
+// fields so unchanged fields are written back as they were.
+type csvReader struct {
+	r     *bufio.Reader
+	delim byte
+	line  int // line of the last record read
+	next  int // line of the next record
+}
+
+func newCSVReader(r io.Reader, delim byte) *csvReader {
+	return &csvReader{r: bufio.NewReader(r), delim: delim, next: 1}
+}
+
+// read returns the fields of the next record and its line terminator, or
+// io.EOF at the end of the input. Quoted fields may span several lines.
+func (c *csvReader) read() ([]csvField, string, error) {
+	text, err := c.r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, "", ioError(err)
+	}
+	if text == "" {
+		return nil, "", io.EOF
+	}
+	c.line = c.next
+	c.next++
+
+	var fields []csvField
+	var field csvField
+	var value strings.Builder
+	start, inQuotes := 0, false
+	for i := 0; ; i++ {
+		if i == len(text) {
+			if !inQuotes {
+				break
+			}
+			// a quoted field with a line break
+			more, err := c.r.ReadString('\n')
+			if err != nil && err != io.EOF {
+				return nil, "", ioError(err)
+			}
+			if more == "" {
+				return nil, "", &cliError{code: exitInvalidInput, err: fmt.Errorf("line %d: unterminated quoted field: %q", c.line, text[start:])}
+			}
+			text += more
+			c.next++
+		}
+
+		ch := text[i]
+		switch {
+		case inQuotes && ch == '"' && i+1 < len(text) && text[i+1] == '"':
+			value.WriteByte('"')
+			i++
+		case inQuotes && ch == '"':
+			inQuotes = false
+		case inQuotes:
+			value.WriteByte(ch)
+		case ch == '"' && i == start:
+			inQuotes, field.quoted = true, true
+		case ch == c.delim:
+			field.raw, field.value = text[start:i], value.String()
+			fields = append(fields, field)
+			field = csvField{}
+			value.Reset()
+			start = i + 1
+		default:
+			value.WriteByte(ch)
+		}
+	}
+
+	eol := ""
+	for _, terminator := range []string{"\r\n", "\n"} {
+		if strings.HasSuffix(text, terminator) {
+			eol = terminator
+			break
+		}
+	}
+	raw := text[start : len(text)-len(eol)]
+	field.raw, field.value = raw, strings.TrimSuffix(value.String(), eol)
+	fields = append(fields, field)
+
+	return fields, eol, nil
+}
+
+// csvQuote returns the text of a field with the given value, quoted when the
+// field it replaces was, or when the value needs it.
+func csvQuote(value string, quoted bool, delim byte) string {
+	if !quoted && !strings.ContainsAny(value, string(delim)+"\"\r\n") {
+		return value
+	}
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
+
+// csvColumns resolves the --columns list to 0-based indexes, by name in the
+// header or by 1-based index.
+func csvColumns(list string, header []csvField) ([]int, error) {
+	var columns []int
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+
+		found := false
+		for i, f := range header {
+			if f.value == name {
+				columns, found = append(columns, i), true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		index, err := strconv.Atoi(name)
+		if err != nil || index < 1 {
+			if header == nil {
+				return nil, usageError("unknown column %q: files without a header need column indexes, starting at 1", name)
+			}
+			return nil, usageError("unknown column %q", name)
+		}
+		columns = append(columns, index-1)
+	}
+
+	return columns, nil
+}
+
+// convertCSV converts the selected columns of CSV or TSV records, one record
+// at a time, replacing them or appending the converted values as new columns.
+func convertCSV(w io.Writer, r io.Reader, o CSVOptions, args []string) error {
+	if o.columns == "" {
+		return usageError("missing --columns")
+	}
+	if len(args) > 1 {
+		return usageError("too many arguments")
+	}
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return ioError(err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	delim := o.delimiter
+	if o.tsv {
+		delim = "\t"
+	}
+	if len(delim) != 1 || delim == `"` || delim == "\r" || delim == "\n" {
+		return usageError("invalid delimiter %q: expected a single character", delim)
+	}
+
+	converter, err := newTimeConverter(o.options, o.toEpoch)
+	if err != nil {
+		return err
+	}
+
+	suffix := o.suffix
+	if !o.suffixOption.Seen() && o.toEpoch {
+		suffix = "_epoch"
+	}
+
+	reader := newCSVReader(r, delim[0])
+	out := bufio.NewWriter(w)
+	var columns []int
+	for row := 0; ; row++ {
+		fields, eol, err := reader.read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(fields) == 1 && fields[0].raw == "" {
+			// blank lines are not records
+			if _, err := fmt.Fprint(out, eol); err != nil {
+				return ioError(err)
+			}
+			row--
+			continue
+		}
+
+		if row == 0 {
+			var header []csvField
+			if !o.noHeader {
+				header = fields
+			}
+			if columns, err = csvColumns(o.columns, header); err != nil {
+				return err
+			}
+		}
+
+		var appended []string
+		for _, column := range columns {
+			var field csvField
+			if column < len(fields) {
+				field = fields[column]
+			}
+
+			var value string
+			if row == 0 && !o.noHeader {
+				value = field.value + suffix
+			} else if value, err = converter.convert(field.value); err != nil {
+				return fmt.Errorf("line %d: %w", reader.line, err)
+			}
+
+			switch {
+			case o.append:
+				appended = append(appended, csvQuote(value, field.quoted, delim[0]))
+			case row > 0 || o.noHeader:
+				if column < len(fields) {
+					fields[column].raw = csvQuote(value, field.quoted, delim[0])
+				}
+			}
+		}
+
+		raw := make([]string, 0, len(fields)+len(appended))
+		for _, f := range fields {
+			raw = append(raw, f.raw)
+		}
+		raw = append(raw, appended...)
+		if _, err := fmt.Fprint(out, strings.Join(raw, delim)+eol); err != nil {
+			return ioError(err)
+		}
+	}
+
+	return ioError(out.Flush())
+}
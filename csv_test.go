@@ -0,0 +1,134 @@
+package main
+
+import (
+	"github.com/lsmoura/ut-cli/ut"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCSVReader(t *testing.T) {
+	r := newCSVReader(strings.NewReader("a,\"b \"\"c\"\"\",\"d\ne\"\r\n,x\"y\nlast"), ',')
+
+	fields, eol, err := r.read()
+	require.NoError(t, err)
+	assert.Equal(t, "\r\n", eol)
+	assert.Equal(t, 1, r.line)
+	assert.Equal(t, []csvField{
+		{raw: "a", value: "a"},
+		{raw: `"b ""c"""`, value: `b "c"`, quoted: true},
+		{raw: "\"d\ne\"", value: "d\ne", quoted: true},
+	}, fields)
+
+	fields, eol, err = r.read()
+	require.NoError(t, err)
+	assert.Equal(t, "\n", eol)
+	assert.Equal(t, 3, r.line)
+	assert.Equal(t, []csvField{{}, {raw: `x"y`, value: `x"y`}}, fields)
+
+	fields, eol, err = r.read()
+	require.NoError(t, err)
+	assert.Equal(t, "", eol)
+	assert.Equal(t, []csvField{{raw: "last", value: "last"}}, fields)
+
+	_, _, err = r.read()
+	assert.Equal(t, io.EOF, err)
+
+	_, _, err = newCSVReader(strings.NewReader("a,\"b\n"), ',').read()
+	code, _ := describeError(err)
+	assert.Equal(t, exitInvalidInput, code)
+}
+
+func TestConvertCSV(t *testing.T) {
+	input := "id,\"created_at\",note,updated_at\r\n" +
+		"1,\"1680717044\",\"a, b\",1680717044000\r\n" +
+		"\r\n" +
+		"2,,\"multi\nline\",1680717044123\r\n"
+
+	tests := []struct {
+		name    string
+		options Options
+		args    []string
+		want    string
+	}{
+		{
+			"replace",
+			Options{utc: true},
+			[]string{"--columns", "created_at,4"},
+			"id,\"created_at\",note,updated_at\r\n" +
+				"1,\"2023-04-05T17:50:44Z\",\"a, b\",2023-04-05T17:50:44Z\r\n" +
+				"\r\n" +
+				"2,,\"multi\nline\",2023-04-05T17:50:44Z\r\n",
+		},
+		{
+			"append",
+			Options{offset: "+02:00", format: "%Y-%m-%d %H:%M:%S.%f"},
+			[]string{"-a", "-c", "updated_at"},
+			"id,\"created_at\",note,updated_at,updated_at_human\r\n" +
+				"1,\"1680717044\",\"a, b\",1680717044000,2023-04-05 19:50:44.000000\r\n" +
+				"\r\n" +
+				"2,,\"multi\nline\",1680717044123,2023-04-05 19:50:44.123000\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			o := CSVOptions{options: tt.options}
+			args, err := o.Parse(append([]string{"csv"}, tt.args...)...)
+			require.NoError(t, err)
+			require.NoError(t, convertCSV(&buf, strings.NewReader(input), o, args))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestConvertCSVToEpoch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "export.tsv")
+	require.NoError(t, os.WriteFile(path, []byte("1\t2023-04-05 17:50:44\n2\t\"2023-04-05 17:50:45\"\n"), 0o600))
+
+	var buf strings.Builder
+	o := CSVOptions{options: Options{precision: "ms", format: "2006-01-02 15:04:05"}}
+	args, err := o.Parse("csv", "-t", "-N", "-E", "-a", "-c", "2", path)
+	require.NoError(t, err)
+	require.NoError(t, convertCSV(&buf, strings.NewReader(""), o, args))
+	assert.Equal(t, "1\t2023-04-05 17:50:44\t1680717044000\n2\t\"2023-04-05 17:50:45\"\t\"1680717045000\"\n", buf.String())
+}
+
+func TestConvertCSVErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		input string
+		code  int
+	}{
+		{"no columns", []string{"csv"}, "a\n1\n", exitUsage},
+		{"unknown column", []string{"csv", "-c", "b"}, "a\n1\n", exitUsage},
+		{"name without header", []string{"csv", "-N", "-c", "a"}, "a\n1\n", exitUsage},
+		{"delimiter", []string{"csv", "-c", "1", "-d", ";;"}, "a\n1\n", exitUsage},
+		{"invalid epoch", []string{"csv", "-c", "a"}, "a\n1\nfoo\n", exitInvalidInput},
+		{"keyword to epoch", []string{"csv", "-E", "-c", "a"}, "a\nnow\n", exitInvalidInput},
+		{"missing file", []string{"csv", "-c", "a", "missing.csv"}, "", exitIO},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := CSVOptions{options: Options{utc: true}}
+			args, err := o.Parse(tt.args...)
+			require.NoError(t, err)
+			err = convertCSV(&strings.Builder{}, strings.NewReader(tt.input), o, args)
+			code, _ := describeError(err)
+			assert.Equal(t, tt.code, code, err)
+		})
+	}
+
+	o := CSVOptions{options: Options{utc: true}}
+	args, err := o.Parse("csv", "-c", "a")
+	require.NoError(t, err)
+	err = convertCSV(&strings.Builder{}, strings.NewReader("a\n1\nfoo\n"), o, args)
+	assert.ErrorIs(t, err, ut.ErrInvalidTimestamp)
+	assert.ErrorContains(t, err, "line 3:")
+}
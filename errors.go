@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"github.com/pborman/getopt/v2"
+)
+
+// Exit codes of the ut binary. They are part of the public interface and
+// listed in the help output and the readme.
+const (
+	exitOK           = 0
+	exitFailure      = 1 // unexpected error
+	exitUsage        = 2 // invalid command line: unknown subcommand, option or option value
+	exitInvalidInput = 3 // malformed timestamp, delta or date
+	exitUnknownZone  = 4 // unknown time zone or offset
+	exitOutOfRange   = 5 // value cannot be represented
+	exitIO           = 6 // error reading input or writing output
+)
+
+var exitCodeDescriptions = []struct {
+	code        int
+	description string
+}{
+	{exitOK, "success"},
+	{exitFailure, "unexpected error"},
+	{exitUsage, "usage error (unknown subcommand, option or option value)"},
+	{exitInvalidInput, "invalid input (malformed timestamp, delta or date)"},
+	{exitUnknownZone, "unknown time zone or offset"},
+	{exitOutOfRange, "value out of range"},
+	{exitIO, "input/output error"},
+}
+
+// cliError attaches an exit code and an optional hint to an error.
+type cliError struct {
+	code int
+	err  error
+	hint string
+}
+
+func (e *cliError) Error() string {
+	if e.hint != "" {
+		return fmt.Sprintf("%s (%s)", e.err, e.hint)
+	}
+	return e.err.Error()
+}
+
+func (e *cliError) Unwrap() error {
+	return e.err
+}
+
+func usageError(format string, args ...any) error {
+	return &cliError{code: exitUsage, err: fmt.Errorf(format, args...)}
+}
+
+func ioError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &cliError{code: exitIO, err: err}
+}
+
+// unknownValueError reports an unrecognized token, suggesting the closest
+// candidate when there is one.
+func unknownValueError(code int, what string, value string, candidates []string) error {
+	err := &cliError{code: code, err: fmt.Errorf("%s: %q", what, value)}
+	if s, ok := suggest(value, candidates); ok {
+		err.hint = fmt.Sprintf("did you mean %q?", s)
+	}
+	return err
+}
+
+// describeError classifies err, returning the exit code and the message to
+// show to the user.
+func describeError(err error) (int, string) {
+	var getoptErr *getopt.Error
+	if errors.As(err, &getoptErr) {
+		if getoptErr.ErrorCode == getopt.Invalid && getoptErr.Err != nil {
+			_, msg := describeError(getoptErr.Err)
+			return exitUsage, fmt.Sprintf("invalid value for %s: %s", getoptErr.Name, msg)
+		}
+		return exitUsage, err.Error()
+	}
+
+	var cErr *cliError
+	if errors.As(err, &cErr) {
+		return cErr.code, err.Error()
+	}
+
+	code := exitFailure
+	switch {
+	case errors.Is(err, ut.ErrUnknownZone):
+		code = exitUnknownZone
+	case errors.Is(err, ut.ErrOutOfRange):
+		code = exitOutOfRange
+	case errors.Is(err, ut.ErrUnknownPrecision), errors.Is(err, ut.ErrUnknownTruncate), errors.Is(err, ut.ErrUnknownEpoch), errors.Is(err, ut.ErrUnknownIDKind), errors.Is(err, ut.ErrUnknownCalendar), errors.Is(err, ut.ErrInvalidBucketSize), errors.Is(err, ut.ErrUnknownLogFormat), errors.Is(err, ut.ErrUnknownDialect):
+		code = exitUsage
+	case errors.Is(err, ut.ErrInvalidTimestamp), errors.Is(err, ut.ErrInvalidDelta), errors.Is(err, ut.ErrInvalidBase), errors.Is(err, ut.ErrInvalidID), errors.Is(err, ut.ErrInvalidLeapSeconds), errors.Is(err, ut.ErrInvalidHolidays), errors.Is(err, ut.ErrInvalidFormatPresets), errors.Is(err, ut.ErrInvalidCron), errors.Is(err, ut.ErrInvalidInterval), errors.Is(err, ut.ErrUnconvertibleFormat):
+		code = exitInvalidInput
+	}
+
+	msg := err.Error()
+	var valueErr *ut.ValueError
+	if errors.As(err, &valueErr) {
+		var candidates []string
+		switch valueErr.Err {
+		case ut.ErrUnknownPrecision:
+			candidates = ut.PrecisionNames()
+		case ut.ErrUnknownTruncate:
+			candidates = ut.TruncateNames()
+		case ut.ErrUnknownEpoch:
+			candidates = ut.EpochNames()
+		case ut.ErrUnknownIDKind:
+			candidates = ut.IDKindNames()
+		case ut.ErrUnknownCalendar:
+			candidates = ut.CalendarNames()
+		case ut.ErrUnknownZone:
+			candidates = ut.ZoneNames()
+		case ut.ErrUnknownLogFormat:
+			candidates = ut.LogFormatNames()
+		case ut.ErrUnknownDialect:
+			candidates = ut.DialectNames()
+		}
+		if s, ok := suggest(valueErr.Value, candidates); ok {
+			msg = fmt.Sprintf("%s (did you mean %q?)", msg, s)
+		}
+	}
+
+	return code, msg
+}
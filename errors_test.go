@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func TestDescribeError(t *testing.T) {
+	tests := []struct {
+		args []string
+		code int
+		msg  string
+	}{
+		{[]string{"ut"}, exitUsage, "missing subcommand"},
+		{[]string{"ut", "gen"}, exitUsage, `unknown subcommand: "gen" (did you mean "generate"?)`},
+		{[]string{"ut", "--nope", "parse"}, exitUsage, "unknown option: --nope"},
+		{[]string{"ut", "parse", "abc"}, exitInvalidInput, `invalid timestamp: "abc"`},
+		{[]string{"ut", "parse", "99999999999999999999"}, exitOutOfRange, `value out of range: "99999999999999999999" (supported years are -999999999 to 999999999)`},
+		{[]string{"ut", "-p", "milis", "parse", "1"}, exitUsage, `unknown precision: "milis" (did you mean "milli"?)`},
+		{[]string{"ut", "-o", "Asia/Tokio", "parse", "1"}, exitUnknownZone, `unknown time zone: "Asia/Tokio" (did you mean "Asia/Tokyo"?)`},
+		{[]string{"ut", "generate", "-t", "hours"}, exitUsage, `invalid value for -t: unknown truncate option: "hours" (did you mean "hour"?)`},
+		{[]string{"ut", "generate", "-d", "3weeks"}, exitInvalidInput, `invalid delta: "3weeks"`},
+		{[]string{"ut", "generate", "extra"}, exitUsage, `unknown argument: "extra"`},
+	}
+
+	for _, test := range tests {
+		err := run(test.args...)
+		if !assert.Errorf(t, err, "expected error for %q", test.args) {
+			continue
+		}
+
+		code, msg := describeError(err)
+		assert.Equalf(t, test.code, code, "exit code for %q", test.args)
+		assert.Equalf(t, test.msg, msg, "message for %q", test.args)
+	}
+}
+
+func TestDescribeErrorIO(t *testing.T) {
+	code, _ := describeError(ioError(io.ErrUnexpectedEOF))
+	assert.Equal(t, exitIO, code)
+
+	code, _ = describeError(errors.New("boom"))
+	assert.Equal(t, exitFailure, code)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// lineTime extracts and parses the timestamp of lines of text, for sort and
+// filter.
+type lineTime struct {
+	fields FieldOptions
+
+	re       *regexp.Regexp
+	path     jsonPath
+	selected bool // a column, a regex or a path was given
+	layout   string
+
+	inputFormat    ut.LogFormat
+	hasInputFormat bool
+	ref            time.Time // completes times of the input format without a year or a zone
+
+	epoch     ut.Epoch
+	precision ut.Precision
+	infer     bool // infer the precision of each epoch from its digits
+}
+
+func newLineTime(o Options, fields FieldOptions) (*lineTime, error) {
+	lt := &lineTime{fields: fields}
+
+	selectors := 0
+	if (fields.columnOption != nil && fields.columnOption.Seen()) || fields.delimiter != "" {
+		selectors++
+	}
+	if fields.regex != "" {
+		selectors++
+		re, err := regexp.Compile(fields.regex)
+		if err != nil {
+			return nil, usageError("invalid regular expression %q: %s", fields.regex, err)
+		}
+		lt.re = re
+	}
+	if fields.jsonPath != "" {
+		selectors++
+		path, err := parseJSONPath(fields.jsonPath)
+		if err != nil {
+			return nil, err
+		}
+		lt.path = path
+	}
+	if selectors > 1 {
+		return nil, usageError("--column/--delimiter, --regex and --json-path are mutually exclusive")
+	}
+	lt.selected = selectors > 0
+	if fields.column < 1 {
+		return nil, usageError("invalid column %d: columns start at 1", fields.column)
+	}
+
+	var err error
+	if lt.epoch, lt.precision, err = o.EpochPrecision(); err != nil {
+		return nil, err
+	}
+	precisionName, _ := o.Precision()
+	lt.infer = precisionName == ""
+	lt.layout, _ = o.Format()
+	if lt.inputFormat, lt.hasInputFormat, err = o.InputFormat(); err != nil {
+		return nil, err
+	}
+	if lt.ref, err = transform(time.Now(), o); err != nil {
+		return nil, err
+	}
+
+	return lt, nil
+}
+
+// field returns the text of the timestamp of a line.
+func (lt *lineTime) field(line string) (string, bool) {
+	switch {
+	case lt.re != nil:
+		matches := lt.re.FindStringSubmatch(line)
+		if len(matches) == 0 {
+			return "", false
+		}
+		if len(matches) > 1 {
+			return matches[1], true
+		}
+		return matches[0], true
+	case lt.path != nil:
+		decoder := json.NewDecoder(bytes.NewReader([]byte(line)))
+		decoder.UseNumber()
+		var document any
+		if err := decoder.Decode(&document); err != nil {
+			return "", false
+		}
+		v, ok := lt.path.lookup(document)
+		if !ok {
+			return "", false
+		}
+		switch v := v.(type) {
+		case json.Number:
+			return v.String(), true
+		case string:
+			return v, true
+		}
+		return "", false
+	}
+
+	var fields []string
+	if lt.fields.delimiter == "" {
+		fields = strings.Fields(line)
+	} else {
+		fields = strings.Split(line, lt.fields.delimiter)
+	}
+	if lt.fields.column > len(fields) {
+		return "", false
+	}
+
+	return strings.TrimSpace(fields[lt.fields.column-1]), true
+}
+
+// parse returns the timestamp of a line: an epoch, in the configured
+// precision or the one its digits suggest, or a date in the configured
+// format, RFC 3339 by default. With an input format, the timestamp is the
+// first one in that format, in the whole line unless a field was selected.
+func (lt *lineTime) parse(line string) (time.Time, error) {
+	if lt.hasInputFormat && !lt.selected {
+		if _, t, ok := lt.inputFormat.Find(line, lt.ref); ok {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("%w: no %s timestamp found in line %q", ut.ErrInvalidTimestamp, lt.inputFormat.Name, line)
+	}
+
+	value, ok := lt.field(line)
+	if !ok || value == "" {
+		return time.Time{}, fmt.Errorf("%w: no timestamp found in line %q", ut.ErrInvalidTimestamp, line)
+	}
+	if lt.hasInputFormat {
+		return lt.inputFormat.Parse(value, lt.ref)
+	}
+
+	if epochMatch.MatchString(value) {
+		precision := lt.precision
+		if lt.infer {
+			precision = ut.InferPrecision(value)
+		}
+		return lt.epoch.Parse(value, precision)
+	}
+
+	layout := lt.layout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	switch value {
+	case "now", "today", "yesterday", "tomorrow":
+	default:
+		if t, err := ut.ParseBase(value, layout, time.Time{}); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q in line %q (expected an epoch or layout %q)", ut.ErrInvalidTimestamp, value, line, layout)
+}
+
+// timeConverter converts values between epochs and formatted dates, for csv
+// and json.
+type timeConverter struct {
+	options Options
+	toEpoch bool
+
+	epoch     ut.Epoch
+	precision ut.Precision
+	infer     bool // infer the precision of each epoch from its digits
+	layout    string
+
+	inputFormat    ut.LogFormat
+	hasInputFormat bool
+	ref            time.Time
+}
+
+func newTimeConverter(o Options, toEpoch bool) (*timeConverter, error) {
+	c := &timeConverter{options: o, toEpoch: toEpoch}
+
+	var err error
+	if c.epoch, c.precision, err = o.EpochPrecision(); err != nil {
+		return nil, err
+	}
+	precisionName, _ := o.Precision()
+	c.infer = precisionName == "" && !toEpoch
+	if c.layout, _ = o.Format(); c.layout == "" {
+		c.layout = time.RFC3339
+	}
+	if c.inputFormat, c.hasInputFormat, err = o.InputFormat(); err != nil {
+		return nil, err
+	}
+	if c.ref, err = transform(time.Now(), o); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// convert converts a value: an epoch to a date in the configured format and
+// zone, or a date to an epoch when converting to epochs. Empty values are
+// kept empty.
+func (c *timeConverter) convert(value string) (string, error) {
+	return c.convertWith(value, c.precision, c.infer)
+}
+
+// convertWith converts a value with the given precision, or the one the
+// digits of epochs suggest when infer is set.
+func (c *timeConverter) convertWith(value string, precision ut.Precision, infer bool) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	if c.toEpoch {
+		parse := func(value string) (time.Time, error) {
+			switch value {
+			case "now", "today", "yesterday", "tomorrow":
+				return time.Time{}, fmt.Errorf("%w: %q (expected layout %q)", ut.ErrInvalidTimestamp, value, c.layout)
+			}
+			return ut.ParseBase(value, c.layout, time.Time{})
+		}
+		if c.hasInputFormat {
+			parse = func(value string) (time.Time, error) { return c.inputFormat.Parse(value, c.ref) }
+		}
+		t, err := parse(value)
+		if err != nil {
+			return "", err
+		}
+		return c.epoch.Format(t, precision), nil
+	}
+
+	if infer {
+		precision = ut.InferPrecision(value)
+	}
+	t, err := c.epoch.Parse(value, precision)
+	if err != nil {
+		return "", err
+	}
+	if t, err = transform(t, c.options); err != nil {
+		return "", err
+	}
+
+	return ut.Format(t, c.layout), nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"github.com/lsmoura/ut-cli/ut"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestLineTime(t *testing.T) {
+	want := time.Unix(1680717044, 0)
+	tests := []struct {
+		name   string
+		fields FieldOptions
+		line   string
+	}{
+		{"first column", FieldOptions{column: 1}, "1680717044 GET /"},
+		{"inferred precision", FieldOptions{column: 1}, "1680717044000  GET /"},
+		{"date", FieldOptions{column: 1}, "2023-04-05T17:50:44Z GET /"},
+		{"delimiter", FieldOptions{column: 2, delimiter: ","}, "GET, 1680717044 ,/"},
+		{"regex group", FieldOptions{column: 1, regex: `ts=(\d+)`}, "GET / ts=1680717044000000"},
+		{"regex match", FieldOptions{column: 1, regex: `\d{10}`}, "GET /1680717044"},
+		{"json number", FieldOptions{column: 1, jsonPath: ".req.ts"}, `{"req": {"ts": 1680717044}}`},
+		{"json string", FieldOptions{column: 1, jsonPath: ".ts[0]"}, `{"ts": ["2023-04-05T17:50:44Z"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lt, err := newLineTime(Options{}, tt.fields)
+			require.NoError(t, err)
+			got, err := lt.parse(tt.line)
+			require.NoError(t, err)
+			assert.True(t, want.Equal(got), got)
+		})
+	}
+
+	lt, err := newLineTime(Options{precision: "ms"}, FieldOptions{column: 1})
+	require.NoError(t, err)
+	got, err := lt.parse("1680717044")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1680717044), got.UnixMilli())
+
+	for _, line := range []string{"GET /", "now", `{"ts": 1}`} {
+		_, err := lt.parse(line)
+		assert.Error(t, err, line)
+	}
+	lt, err = newLineTime(Options{}, FieldOptions{column: 1, jsonPath: ".ts"})
+	require.NoError(t, err)
+	_, err = lt.parse(`{"ts": true}`)
+	assert.ErrorIs(t, err, ut.ErrInvalidTimestamp)
+
+	_, err = newLineTime(Options{}, FieldOptions{column: 1, regex: "(", jsonPath: ".ts"})
+	code, _ := describeError(err)
+	assert.Equal(t, exitUsage, code)
+	_, err = newLineTime(Options{}, FieldOptions{column: 0})
+	code, _ = describeError(err)
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestLineTimeInputFormat(t *testing.T) {
+	want := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	lt, err := newLineTime(Options{inputFormat: "syslog"}, FieldOptions{column: 1})
+	require.NoError(t, err)
+	lt.ref = time.Date(2023, 4, 6, 0, 0, 0, 0, time.UTC)
+	got, err := lt.parse("Apr  5 17:50:44 host sshd[42]: Accepted key")
+	require.NoError(t, err)
+	assert.True(t, want.Equal(got), got)
+	_, err = lt.parse("host sshd[42]: Accepted key")
+	assert.ErrorIs(t, err, ut.ErrInvalidTimestamp)
+
+	lt, err = newLineTime(Options{inputFormat: "clf"}, FieldOptions{column: 1, regex: `\[(.*?)\]`})
+	require.NoError(t, err)
+	got, err = lt.parse(`127.0.0.1 - [05/Apr/2023:17:50:44 +0000] "GET / HTTP/1.1" 200`)
+	require.NoError(t, err)
+	assert.True(t, want.Equal(got), got)
+
+	_, err = newLineTime(Options{inputFormat: "apache"}, FieldOptions{column: 1})
+	code, _ := describeError(err)
+	assert.Equal(t, exitUsage, code)
+}
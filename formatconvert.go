@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
+	"strings"
+)
+
+// convertFormats translates the format strings given as arguments, or read
+// from r one per line, from one dialect to another.
+func convertFormats(w io.Writer, r io.Reader, o FormatConvertOptions, args []string) error {
+	if o.from == "" {
+		return usageError("missing --from")
+	}
+	if o.to == "" {
+		return usageError("missing --to")
+	}
+	from, err := ut.LookupDialect(o.from)
+	if err != nil {
+		return err
+	}
+	to, err := ut.LookupDialect(o.to)
+	if err != nil {
+		return err
+	}
+
+	convert := func(format string) error {
+		converted, err := ut.ConvertFormat(format, from, to)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, converted)
+		return ioError(err)
+	}
+
+	if len(args) > 0 {
+		for _, format := range args {
+			if err := convert(format); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		format := strings.TrimRight(scanner.Text(), "\r")
+		if format == "" {
+			continue
+		}
+		if err := convert(format); err != nil {
+			return err
+		}
+	}
+
+	return ioError(scanner.Err())
+}
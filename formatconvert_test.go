@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"github.com/lsmoura/ut-cli/ut"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestConvertFormats(t *testing.T) {
+	var buf bytes.Buffer
+	o := FormatConvertOptions{from: "strftime", to: "go"}
+	require.NoError(t, convertFormats(&buf, nil, o, []string{"%Y-%m-%d %H:%M:%S", "%d/%b/%Y"}))
+	assert.Equal(t, "2006-01-02 15:04:05\n02/Jan/2006\n", buf.String())
+
+	buf.Reset()
+	o = FormatConvertOptions{from: "java", to: "dayjs"}
+	require.NoError(t, convertFormats(&buf, strings.NewReader("yyyy-MM-dd'T'HH:mm\n\nEEE h:mm a\n"), o, nil))
+	assert.Equal(t, "YYYY-MM-DD[T]HH:mm\nddd h:mm A\n", buf.String())
+}
+
+func TestConvertFormatsErrors(t *testing.T) {
+	tests := []struct {
+		options FormatConvertOptions
+		code    int
+	}{
+		{FormatConvertOptions{to: "go"}, exitUsage},
+		{FormatConvertOptions{from: "go"}, exitUsage},
+		{FormatConvertOptions{from: "momnet", to: "go"}, exitUsage},
+		{FormatConvertOptions{from: "moment", to: "go"}, exitInvalidInput},
+	}
+	for _, tt := range tests {
+		err := convertFormats(&bytes.Buffer{}, nil, tt.options, []string{"x"})
+		code, _ := describeError(err)
+		assert.Equal(t, tt.code, code, tt.options)
+	}
+
+	_, msg := describeError(convertFormats(&bytes.Buffer{}, nil, FormatConvertOptions{from: "momnet", to: "go"}, []string{"x"}))
+	assert.Contains(t, msg, `did you mean "moment"?`)
+}
+
+func TestLoadFormatDialect(t *testing.T) {
+	t.Setenv(formatEnvVar, "")
+
+	options := Options{format: "yyyy-MM-dd'T'HH:mm:ss", formatDialect: "java"}
+	require.NoError(t, options.loadFormatDialect())
+	format, _ := options.Format()
+	assert.Equal(t, "2006-01-02T15:04:05", format)
+
+	options = Options{format: "YYYY [week] W", formatDialect: "moment"}
+	require.NoError(t, options.loadFormatDialect())
+	format, _ = options.Format()
+	assert.Equal(t, "%Y week %-V", format)
+
+	assert.ErrorIs(t, (&Options{format: "yyyy SS", formatDialect: "java"}).loadFormatDialect(), ut.ErrUnconvertibleFormat)
+	assert.ErrorIs(t, (&Options{format: "yyyy", formatDialect: "perl"}).loadFormatDialect(), ut.ErrUnknownDialect)
+	code, _ := describeError((&Options{formatDialect: "java"}).loadFormatDialect())
+	assert.Equal(t, exitUsage, code)
+}
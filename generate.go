@@ -2,142 +2,115 @@ package main
 
 import (
 	"fmt"
-	"github.com/lsmoura/ut-cli/strftime"
+	"github.com/lsmoura/ut-cli/ut"
 	"io"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 )
 
-var deltaMatch = regexp.MustCompile(`^([+-]?)(\d+)(days?|d|years?|y|hours?|h|min|minutes?|seconds?|s)$`)
+// generateTime resolves the base, truncation, deltas, business-day alignment
+// and zone of the generate options, relative to now.
+func generateTime(o GenerateOptions, now time.Time) (time.Time, error) {
+	layout, _ := o.options.Format()
+	t, err := ut.ParseBase(o.base, layout, now)
+	if err != nil {
+		return t, err
+	}
 
-func applyDelta(t time.Time, delta string) (time.Time, error) {
-	matches := deltaMatch.FindStringSubmatch(delta)
-	if len(matches) == 0 {
-		return t, fmt.Errorf("invalid delta: %s", delta)
+	// weekends and holidays are those of the target zone
+	if t, err = transform(t, o.options); err != nil {
+		return t, err
 	}
 
-	value, err := strconv.Atoi(matches[2])
+	t, err = o.truncate.Truncate(t)
 	if err != nil {
 		return t, err
 	}
-	if matches[1] == "-" {
-		value = -value
-	}
 
-	switch matches[3] {
-	case "year", "years", "y":
-		return t.AddDate(value, 0, 0), nil
-	case "day", "days", "d":
-		return t.AddDate(0, 0, value), nil
-	case "hour", "hours", "h":
-		return t.Add(time.Duration(value) * time.Hour), nil
-	case "min", "minute", "minutes":
-		return t.Add(time.Duration(value) * time.Minute), nil
-	case "second", "seconds", "s":
-		return t.Add(time.Duration(value) * time.Second), nil
-	default:
-		return t, fmt.Errorf("unknown delta: %s", delta)
+	for _, delta := range o.delta {
+		t, err = holidayTable.ApplyDelta(t, delta)
+		if err != nil {
+			return t, err
+		}
 	}
-}
 
-// timeFormatFromPercent converts a time format string with percent values to a go time format string
-func timeFormatFromPercent(format string) string {
-	pieces := strftime.StrTimeTokens(format)
-
-	var goFormat string
-	for _, piece := range pieces {
-		switch piece {
-		case "%Y":
-			goFormat += "2006"
-		case "%m":
-			goFormat += "01"
-		case "%d":
-			goFormat += "02"
-		case "%H":
-			goFormat += "15"
-		case "%M":
-			goFormat += "04"
-		case "%S":
-			goFormat += "05"
-		case "%z":
-			goFormat += "Z07:00"
-		case "%Z":
-			goFormat += "MST"
-		case "%%":
-			goFormat += "%"
-		default:
-			goFormat += piece
+	if o.nextBusinessDay {
+		if t, err = holidayTable.NextBusinessDay(t); err != nil {
+			return t, err
 		}
 	}
 
-	return goFormat
+	return t, nil
 }
 
-func generate(w io.Writer, o GenerateOptions) (err error) {
-	var now time.Time
-
-	switch o.base {
-	case "now", "today", "":
-		now = time.Now()
-	case "yesterday":
-		now = time.Now().AddDate(0, 0, -1)
-	case "tomorrow":
-		now = time.Now().AddDate(0, 0, 1)
-	default:
-		layout := o.options.format
-		if layout == "" {
-			layout = time.RFC3339
-		}
-		if strings.Contains(layout, "%") {
-			layout = timeFormatFromPercent(layout)
-		}
-		t, err := time.Parse(layout, o.base)
-		if err != nil {
-			return err
-		}
-		now = t
+// parseDateDeltas parses a date in any form accepted by --base, optionally
+// followed by deltas separated by spaces, like "yesterday -3h".
+func parseDateDeltas(value string, layout string, now time.Time) (time.Time, error) {
+	t, err := ut.ParseBase(value, layout, now)
+	if err == nil {
+		return t, nil
 	}
 
-	if now.IsZero() {
-		now = time.Now()
+	fields := strings.Fields(value)
+	if len(fields) < 2 {
+		return time.Time{}, err
 	}
 
-	now, err = o.truncate.Truncate(now)
-	if err != nil {
-		return err
+	t, baseErr := ut.ParseBase(fields[0], layout, now)
+	if baseErr != nil {
+		return time.Time{}, err
 	}
-
-	for _, delta := range o.delta {
-		now, err = applyDelta(now, delta)
-		if err != nil {
-			return err
+	for _, delta := range fields[1:] {
+		if t, err = holidayTable.ApplyDelta(t, delta); err != nil {
+			return time.Time{}, err
 		}
 	}
 
-	now, err = transform(now, o.options)
+	return t, nil
+}
+
+func generate(w io.Writer, o GenerateOptions) (err error) {
+	ref := time.Now()
+	now, err := generateTime(o, ref)
 	if err != nil {
 		return err
 	}
 
-	var n int64
-	switch o.options.precision {
-	case "millisecond", "ms":
-		n = now.UnixNano() / 1000000
-	case "microsecond", "us":
-		n = now.UnixNano() / 1000
-	case "nanosecond", "ns":
-		n = now.UnixNano()
-	case "second", "s", "":
-		n = now.Unix()
-	default:
-		return fmt.Errorf("unknown precision: %s", o.options.precision)
+	if tmpl, ok, err := o.options.Template(); err != nil {
+		return err
+	} else if ok {
+		return writeTemplate(w, tmpl, o.base, now, ref)
 	}
 
-	if _, err := fmt.Fprintf(w, "%d\n", n); err != nil {
+	if table, err := tableOutput(o.options); err != nil {
+		return err
+	} else if table {
+		return generateTable(w, o, now, ref)
+	}
+
+	epoch, precision, err := o.options.EpochPrecision()
+	if err != nil {
 		return err
 	}
 
+	if _, err := fmt.Fprintln(w, epoch.Format(now, precision)); err != nil {
+		return ioError(err)
+	}
+
 	return nil
 }
+
+// generateTable prints the generated time as a table, with a row for each
+// --zone.
+func generateTable(w io.Writer, o GenerateOptions, t time.Time, ref time.Time) error {
+	rows := []tableRow{{input: o.base, t: t}}
+	for _, zone := range o.zones {
+		zt, err := inZone(t, false, zone)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, tableRow{input: o.base, zone: zone, t: zt})
+	}
+
+	return printTable(w, rows, o.options, ref)
+}
@@ -10,30 +10,6 @@ import (
 	"time"
 )
 
-func TestApplyDelta(t *testing.T) {
-	tests := []struct {
-		base     time.Time
-		delta    string
-		expected time.Time
-	}{
-		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "1year", time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)},
-		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "2year", time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)},
-		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "-2years", time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)},
-		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "-17y", time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)},
-		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "3day", time.Date(2017, 1, 5, 3, 4, 5, 0, time.UTC)},
-		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "12hour", time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC)},
-		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "1hour", time.Date(2017, 1, 2, 4, 4, 5, 0, time.UTC)},
-		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "1min", time.Date(2017, 1, 2, 3, 5, 5, 0, time.UTC)},
-		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "1s", time.Date(2017, 1, 2, 3, 4, 6, 0, time.UTC)},
-	}
-
-	for _, test := range tests {
-		actual, err := applyDelta(test.base, test.delta)
-		assert.NoError(t, err)
-		assert.Equal(t, test.expected, actual)
-	}
-}
-
 func TestGenerate(t *testing.T) {
 	now := time.Now()
 
@@ -73,3 +49,46 @@ func TestGenerate(t *testing.T) {
 		assert.Equal(t, expectedUnix, actual)
 	}
 }
+
+func TestGenerateEpochFamilies(t *testing.T) {
+	tests := []struct {
+		expected string
+		options  GenerateOptions
+	}{
+		{"133251906440000000", GenerateOptions{options: Options{epoch: "ldap"}, base: "2023-04-05T17:50:44Z"}},
+		{"3889705844", GenerateOptions{options: Options{epoch: "ntp"}, base: "2023-04-05T17:50:44Z"}},
+		{"1364752262000", GenerateOptions{options: Options{epoch: "gps", precision: "ms"}, base: "2023-04-05T17:50:44Z"}},
+		{"45021.5", GenerateOptions{options: Options{epoch: "excel"}, base: "2023-04-05T12:00:00Z"}},
+		{"1680717081", GenerateOptions{options: Options{epoch: "tai"}, base: "2023-04-05T17:50:44Z"}},
+		{"1483228800", GenerateOptions{options: Options{format: "%Y-%m-%d %H:%M:%S"}, base: "2016-12-31 23:59:60"}},
+		{"1483228800", GenerateOptions{options: Options{format: "%Y-%m-%d %H:%M:%S"}, base: "2017-01-01 00:00:00"}},
+	}
+
+	for _, test := range tests {
+		var buf strings.Builder
+		require.NoError(t, generate(&buf, test.options))
+		assert.Equal(t, test.expected, strings.Trim(buf.String(), "\n"))
+	}
+}
+
+func TestGenerateBusinessDays(t *testing.T) {
+	tests := []struct {
+		expected time.Time
+		options  GenerateOptions
+	}{
+		// 2023-04-08 is a Saturday
+		{time.Date(2023, 4, 10, 0, 0, 0, 0, time.UTC), GenerateOptions{base: "2023-04-08T10:00:00Z", nextBusinessDay: true}},
+		{time.Date(2023, 4, 7, 10, 0, 0, 0, time.UTC), GenerateOptions{base: "2023-04-07T10:00:00Z", nextBusinessDay: true}},
+		{time.Date(2023, 4, 12, 10, 0, 0, 0, time.UTC), GenerateOptions{base: "2023-04-07T10:00:00Z", delta: []string{"+3bd"}}},
+		{time.Date(2023, 4, 10, 0, 0, 0, 0, time.UTC), GenerateOptions{base: "2023-04-07T10:00:00Z", delta: []string{"1d"}, nextBusinessDay: true}},
+		// Saturday 08:00 in Tokyo, still Friday in UTC
+		{time.Date(2023, 4, 9, 23, 0, 0, 0, time.UTC), GenerateOptions{options: Options{offset: "Asia/Tokyo"}, base: "2023-04-07T23:00:00Z", delta: []string{"+1bd"}}},
+		{time.Date(2023, 4, 9, 15, 0, 0, 0, time.UTC), GenerateOptions{options: Options{offset: "Asia/Tokyo"}, base: "2023-04-07T23:00:00Z", nextBusinessDay: true}},
+	}
+
+	for _, test := range tests {
+		actual, err := generateTime(test.options, time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected.Unix(), actual.Unix())
+	}
+}
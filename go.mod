@@ -5,12 +5,12 @@ go 1.20
 require (
 	github.com/pborman/getopt/v2 v2.1.0
 	github.com/stretchr/testify v1.8.3
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace github.com/stretchr/testify => github.com/stretchr/testify v1.8.3
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
+	"time"
+)
+
+// decodeIDs prints the instant embedded in each of the given IDs, through the
+// same zone and format handling as parse.
+func decodeIDs(w io.Writer, args []string, o IDOptions) error {
+	if len(args) == 0 {
+		return usageError("no ID to decode")
+	}
+
+	kind, err := ut.LookupIDKind(o.kind)
+	if err != nil {
+		return err
+	}
+
+	strFormat, _ := o.options.Format()
+	for _, arg := range args {
+		t, detected, err := ut.DecodeID(arg, kind)
+		if err != nil {
+			return err
+		}
+
+		t, err = transform(t, o.options)
+		if err != nil {
+			return err
+		}
+
+		line := ut.Format(t, strFormat)
+		if o.verbose {
+			line = fmt.Sprintf("%s\t%s", detected, line)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return ioError(err)
+		}
+	}
+
+	return nil
+}
+
+// generateID prints the lowest (or highest, with --max) ID of the given kind
+// for the base time.
+func generateID(w io.Writer, args []string, o IDOptions, now time.Time) error {
+	if len(args) > 0 {
+		return usageError("unknown argument: %q", args[0])
+	}
+
+	kind, err := ut.LookupIDKind(o.kind)
+	if err != nil {
+		return err
+	}
+	if kind == ut.IDAuto {
+		return usageError("--kind is required to generate an ID")
+	}
+
+	layout, _ := o.options.Format()
+	t, err := ut.ParseBase(o.base, layout, now)
+	if err != nil {
+		return err
+	}
+	for _, delta := range o.delta {
+		if t, err = holidayTable.ApplyDelta(t, delta); err != nil {
+			return err
+		}
+	}
+
+	id, err := ut.GenerateID(kind, t, o.upper)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(w, id); err != nil {
+		return ioError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeIDs(t *testing.T) {
+	var buf strings.Builder
+	o := IDOptions{options: Options{utc: true}}
+	require.NoError(t, decodeIDs(&buf, []string{"1445078208190291968", "507f1f77bcf86cd799439011"}, o))
+	assert.Equal(t, "2021-10-04 17:27:47.744 +0000 UTC\n2012-10-17 21:13:27 +0000 UTC\n", buf.String())
+
+	buf.Reset()
+	o = IDOptions{options: Options{offset: "Asia/Tokyo", format: "%Y-%m-%d %H:%M"}, kind: "discord", verbose: true}
+	require.NoError(t, decodeIDs(&buf, []string{"175928847299117063"}, o))
+	assert.Equal(t, "discord\t2016-04-30 20:18\n", buf.String())
+
+	assert.Error(t, decodeIDs(&buf, nil, IDOptions{}))
+	assert.Error(t, decodeIDs(&buf, []string{"nope"}, IDOptions{}))
+}
+
+func TestGenerateID(t *testing.T) {
+	now := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	tests := []struct {
+		options  IDOptions
+		expected string
+	}{
+		{IDOptions{kind: "objectid"}, "642db4f40000000000000000"},
+		{IDOptions{kind: "objectid", upper: true}, "642db4f4ffffffffffffffff"},
+		{IDOptions{kind: "ulid", base: "2023-04-05T00:00:00Z"}, "01GX7BD4000000000000000000"},
+		{IDOptions{kind: "objectid", base: "2023-04-05T00:00:00Z", delta: []string{"1d"}}, "642e0b800000000000000000"},
+	}
+
+	for _, test := range tests {
+		var buf strings.Builder
+		require.NoError(t, generateID(&buf, nil, test.options, now))
+		assert.Equal(t, test.expected+"\n", buf.String())
+	}
+
+	var buf strings.Builder
+	assert.Error(t, generateID(&buf, nil, IDOptions{}, now))
+	assert.Error(t, generateID(&buf, []string{"extra"}, IDOptions{kind: "ulid"}, now))
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
+	"time"
+)
+
+// intervalOccurrences parses the ISO 8601 interval of the interval
+// subcommand and expands its repetitions.
+func intervalOccurrences(o IntervalOptions, args []string, now time.Time) ([]ut.Interval, error) {
+	if len(args) == 0 {
+		return nil, usageError("missing interval")
+	}
+	if len(args) > 1 {
+		return nil, usageError("too many arguments")
+	}
+	if o.count < 0 {
+		return nil, usageError("--count cannot be negative")
+	}
+
+	layout, _ := o.options.Format()
+	interval, err := ut.ParseInterval(args[0], layout, now)
+	if err != nil {
+		return nil, err
+	}
+	if interval.Repeat < 0 && o.count == 0 {
+		return nil, usageError("--count is required for intervals repeating forever")
+	}
+
+	occurrences, err := interval.Occurrences(o.count)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range occurrences {
+		if occurrences[i].Start, err = transform(occurrences[i].Start, o.options); err != nil {
+			return nil, err
+		}
+		if occurrences[i].End, err = transform(occurrences[i].End, o.options); err != nil {
+			return nil, err
+		}
+	}
+
+	return occurrences, nil
+}
+
+// printInterval prints the bounds of each occurrence of the interval, one
+// occurrence per line, as epochs, formatted dates or ISO 8601 intervals.
+func printInterval(w io.Writer, o IntervalOptions, args []string, now time.Time) error {
+	occurrences, err := intervalOccurrences(o, args, now)
+	if err != nil {
+		return err
+	}
+
+	epoch, precision, err := o.options.EpochPrecision()
+	if err != nil {
+		return err
+	}
+	format, _ := o.options.Format()
+
+	if table, err := tableOutput(o.options); err != nil {
+		return err
+	} else if table {
+		// a row for each occurrence
+		rows := make([]tableRow, len(occurrences))
+		for i, occurrence := range occurrences {
+			rows[i] = tableRow{input: occurrence.String(), t: occurrence.Start, end: occurrence.End}
+		}
+		return printTable(w, rows, o.options, now)
+	}
+
+	for _, occurrence := range occurrences {
+		var line string
+		switch {
+		case o.iso:
+			line = occurrence.String()
+		case o.dates:
+			line = ut.Format(occurrence.Start, format) + "\t" + ut.Format(occurrence.End, format)
+		default:
+			line = epoch.Format(occurrence.Start, precision) + "\t" + epoch.Format(occurrence.End, precision)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return ioError(err)
+		}
+	}
+
+	return nil
+}
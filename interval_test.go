@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrintInterval(t *testing.T) {
+	now := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	tests := []struct {
+		options  Options
+		args     []string
+		interval string
+		expected string
+	}{
+		{Options{}, nil, "R3/2023-01-01T00:00Z/P1D", "1672531200\t1672617600\n1672617600\t1672704000\n1672704000\t1672790400\n"},
+		{Options{precision: "ms"}, []string{"-n", "1"}, "R/2023-01-01T00:00Z/PT1H", "1672531200000\t1672534800000\n"},
+		{Options{offset: "+02:00"}, []string{"--iso"}, "2023-01-01T00:00Z/PT1H", "2023-01-01T02:00:00+02:00/2023-01-01T03:00:00+02:00\n"},
+		{Options{utc: true, format: "%Y-%m-%d"}, []string{"-D"}, "2023-01-01/P1W", "2023-01-01\t2023-01-08\n"},
+		{Options{utc: true}, []string{"--iso"}, "now/PT1H", "2023-04-05T17:50:44Z/2023-04-05T18:50:44Z\n"},
+	}
+
+	for _, test := range tests {
+		o := IntervalOptions{options: test.options}
+		_, err := o.Parse(append([]string{"interval"}, test.args...)...)
+		require.NoError(t, err)
+
+		var buf strings.Builder
+		require.NoError(t, printInterval(&buf, o, []string{test.interval}, now), test.interval)
+		assert.Equal(t, test.expected, buf.String(), test.interval)
+	}
+}
+
+func TestPrintIntervalErrors(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		args []string
+		code int
+	}{
+		{nil, exitUsage},
+		{[]string{"2023-01-01/P1D", "extra"}, exitUsage},
+		{[]string{"R/2023-01-01/P1D"}, exitUsage},
+		{[]string{"2023-01-01/P1X"}, exitInvalidInput},
+	}
+
+	for _, test := range tests {
+		err := printInterval(&strings.Builder{}, IntervalOptions{}, test.args, now)
+		code, _ := describeError(err)
+		assert.Equal(t, test.code, code, test.args)
+	}
+}
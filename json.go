@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// jsonTarget is a --path of the json subcommand and its precision.
+type jsonTarget struct {
+	path      jsonPath
+	precision ut.Precision
+	infer     bool
+}
+
+// jsonEdit replaces the text of a document between start and end.
+type jsonEdit struct {
+	start, end int
+	text       string
+}
+
+// parseJSONTarget parses a --path, optionally followed by a precision, like
+// ".events[].ts:ms", or ":auto" to infer it from the digits of each value.
+func parseJSONTarget(value string, c *timeConverter) (jsonTarget, error) {
+	target := jsonTarget{precision: c.precision, infer: c.infer}
+
+	if i := strings.LastIndexByte(value, ':'); i >= 0 && !strings.ContainsAny(value[i+1:], ".[]\"") {
+		name := value[i+1:]
+		if name == "auto" {
+			target.infer = !c.toEpoch
+		} else {
+			precision, err := ut.ParsePrecision(name)
+			if err != nil {
+				return target, err
+			}
+			target.precision, target.infer = precision, false
+		}
+		value = value[:i]
+	}
+
+	var err error
+	target.path, err = parseJSONPath(value)
+	return target, err
+}
+
+// jsonString returns the JSON form of s, without escaping HTML.
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// convertJSONValue converts the text of a selected value: epochs, as numbers
+// or strings, to dates, or date strings to epochs. Nulls are kept.
+func convertJSONValue(raw string, target jsonTarget, c *timeConverter) (string, bool, error) {
+	if raw == "null" {
+		return "", false, nil
+	}
+
+	value := raw
+	if strings.HasPrefix(raw, `"`) {
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return "", false, err
+		}
+	} else if c.toEpoch || !epochMatch.MatchString(raw) {
+		return "", false, &ut.ValueError{Err: ut.ErrInvalidTimestamp, Value: raw}
+	}
+
+	converted, err := c.convertWith(value, target.precision, target.infer)
+	if err != nil || converted == "" {
+		return "", false, err
+	}
+	if c.toEpoch {
+		return converted, true, nil
+	}
+
+	return jsonString(converted), true, nil
+}
+
+// convertJSONDocument converts the selected values of a document, replacing
+// them or adding them next to the original ones, with the suffix appended to
+// their key. The rest of the text is kept as it is.
+func convertJSONDocument(doc []byte, targets []jsonTarget, suffix string, c *timeConverter) ([]byte, error) {
+	var edits []jsonEdit
+	var visitErr error
+	err := scanJSON(doc, func(v jsonValue) {
+		if visitErr != nil {
+			return
+		}
+		for _, target := range targets {
+			if !target.path.matches(v.location) {
+				continue
+			}
+
+			text, ok, err := convertJSONValue(string(doc[v.start:v.end]), target, c)
+			if err != nil {
+				visitErr = fmt.Errorf("%s: %w", v.location, err)
+				return
+			}
+			if !ok {
+				return
+			}
+
+			if suffix == "" {
+				edits = append(edits, jsonEdit{start: v.start, end: v.end, text: text})
+				return
+			}
+			if !v.member {
+				visitErr = usageError("%s: --add-suffix needs object members, not array elements", v.location)
+				return
+			}
+
+			indent := v.indent
+			if !strings.Contains(indent, "\n") && v.colon != ":" {
+				indent = " "
+			}
+			key := jsonString(v.location[len(v.location)-1].key + suffix)
+			edits = append(edits, jsonEdit{start: v.end, end: v.end, text: "," + indent + key + v.colon + text})
+			return
+		}
+	})
+	if err != nil {
+		return nil, &cliError{code: exitInvalidInput, err: err}
+	}
+	if visitErr != nil {
+		return nil, visitErr
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var out bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		out.Write(doc[last:e.start])
+		out.WriteString(e.text)
+		last = e.end
+	}
+	out.Write(doc[last:])
+
+	return out.Bytes(), nil
+}
+
+// convertJSON converts the selected values of a JSON document or of a
+// stream of documents, like NDJSON, one document at a time.
+func convertJSON(w io.Writer, r io.Reader, o JSONOptions, args []string) error {
+	if len(o.paths) == 0 {
+		return usageError("missing --path")
+	}
+	if len(args) > 1 {
+		return usageError("too many arguments")
+	}
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return ioError(err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	c, err := newTimeConverter(o.options, o.toEpoch)
+	if err != nil {
+		return err
+	}
+	var targets []jsonTarget
+	for _, path := range o.paths {
+		target, err := parseJSONTarget(strings.TrimSpace(path), c)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, target)
+	}
+
+	decoder := json.NewDecoder(r)
+	out := bufio.NewWriter(w)
+	for n := 1; ; n++ {
+		var doc json.RawMessage
+		if err := decoder.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) || err == io.ErrUnexpectedEOF {
+				return &cliError{code: exitInvalidInput, err: fmt.Errorf("document %d: %w", n, err)}
+			}
+			return ioError(err)
+		}
+
+		converted, err := convertJSONDocument(doc, targets, o.addSuffix, c)
+		if err != nil {
+			return fmt.Errorf("document %d: %w", n, err)
+		}
+		if _, err := fmt.Fprintf(out, "%s\n", converted); err != nil {
+			return ioError(err)
+		}
+	}
+
+	return ioError(out.Flush())
+}
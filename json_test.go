@@ -0,0 +1,103 @@
+package main
+
+import (
+	"github.com/lsmoura/ut-cli/ut"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestConvertJSON(t *testing.T) {
+	input := `{"id": 1, "events": [{"ts": 1680717044000, "n": "<a>"}, {"ts": "1680717045"}, {"ts": null}], "at": 1680717044}
+{"events":[{"ts":1680717046123}],"at":1680717044000}
+`
+
+	tests := []struct {
+		name    string
+		options Options
+		args    []string
+		want    string
+	}{
+		{
+			"replace",
+			Options{utc: true},
+			[]string{"--path", ".events[].ts"},
+			`{"id": 1, "events": [{"ts": "2023-04-05T17:50:44Z", "n": "<a>"}, {"ts": "2023-04-05T17:50:45Z"}, {"ts": null}], "at": 1680717044}
+{"events":[{"ts":"2023-04-05T17:50:46Z"}],"at":1680717044000}
+`,
+		},
+		{
+			"suffix and precisions",
+			Options{utc: true, precision: "ms", format: "%H:%M:%S"},
+			[]string{"-P", ".events[].ts:auto,.at", "--add-suffix", "_human"},
+			`{"id": 1, "events": [{"ts": 1680717044000, "ts_human": "17:50:44", "n": "<a>"}, {"ts": "1680717045", "ts_human": "17:50:45"}, {"ts": null}], "at": 1680717044, "at_human": "10:51:57"}
+{"events":[{"ts":1680717046123,"ts_human":"17:50:46"}],"at":1680717044000,"at_human":"17:50:44"}
+`,
+		},
+		{
+			"per path precision",
+			Options{utc: true},
+			[]string{"-P", ".at:s", "-P", ".events[0].ts"},
+			`{"id": 1, "events": [{"ts": "2023-04-05T17:50:44Z", "n": "<a>"}, {"ts": "1680717045"}, {"ts": null}], "at": "2023-04-05T17:50:44Z"}
+{"events":[{"ts":"2023-04-05T17:50:46Z"}],"at":"55229-10-20T13:33:20Z"}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			o := JSONOptions{options: tt.options}
+			args, err := o.Parse(append([]string{"json"}, tt.args...)...)
+			require.NoError(t, err)
+			require.NoError(t, convertJSON(&buf, strings.NewReader(input), o, args))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestConvertJSONPretty(t *testing.T) {
+	input := "{\n  \"a\": {\n    \"ts\": \"2023-04-05T17:50:44Z\"\n  }\n}"
+
+	var buf strings.Builder
+	o := JSONOptions{options: Options{precision: "ms"}}
+	args, err := o.Parse("json", "-E", "-P", ".a.ts", "-s", "_ms")
+	require.NoError(t, err)
+	require.NoError(t, convertJSON(&buf, strings.NewReader(input), o, args))
+	assert.Equal(t, "{\n  \"a\": {\n    \"ts\": \"2023-04-05T17:50:44Z\",\n    \"ts_ms\": 1680717044000\n  }\n}\n", buf.String())
+}
+
+func TestConvertJSONErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		input string
+		code  int
+	}{
+		{"no path", []string{"json"}, `{}`, exitUsage},
+		{"invalid path", []string{"json", "-P", "ts"}, `{}`, exitUsage},
+		{"unknown precision", []string{"json", "-P", ".ts:days"}, `{}`, exitUsage},
+		{"suffix in array", []string{"json", "-P", "[]", "-s", "_h"}, `[1]`, exitUsage},
+		{"invalid json", []string{"json", "-P", ".ts"}, "{\"ts\": 1}\n{\"ts\": ", exitInvalidInput},
+		{"not an epoch", []string{"json", "-P", ".ts"}, `{"ts": true}`, exitInvalidInput},
+		{"number to epoch", []string{"json", "-E", "-P", ".ts"}, `{"ts": 1}`, exitInvalidInput},
+		{"keyword to epoch", []string{"json", "-E", "-P", ".ts"}, `{"ts": "yesterday"}`, exitInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := JSONOptions{options: Options{utc: true}}
+			args, err := o.Parse(tt.args...)
+			require.NoError(t, err)
+			err = convertJSON(&strings.Builder{}, strings.NewReader(tt.input), o, args)
+			code, _ := describeError(err)
+			assert.Equal(t, tt.code, code, err)
+		})
+	}
+
+	o := JSONOptions{options: Options{utc: true}}
+	args, err := o.Parse("json", "-P", ".a[].ts")
+	require.NoError(t, err)
+	err = convertJSON(&strings.Builder{}, strings.NewReader(`{"a": [{"ts": 1}]} {"a": [{"ts": "x"}]}`), o, args)
+	assert.ErrorIs(t, err, ut.ErrInvalidTimestamp)
+	assert.ErrorContains(t, err, "document 2: .a[0].ts:")
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var identifierMatch = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// pathStep is a step of a JSON path: an object key, an array index or every
+// element of an array.
+type pathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// jsonPath selects values in a JSON document, like ".request.ts",
+// ".events[0].ts" or ".events[].ts" for the ts of every event. Keys that are
+// not identifiers are quoted: .["@timestamp"].
+type jsonPath []pathStep
+
+func parseJSONPath(path string) (jsonPath, error) {
+	var p jsonPath
+	rest := path
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "[\""):
+			end := strings.Index(rest[2:], "\"]")
+			if end < 0 {
+				return nil, usageError("invalid JSON path %q: unterminated key", path)
+			}
+			p = append(p, pathStep{key: rest[2 : 2+end]})
+			rest = rest[2+end+2:]
+		case strings.HasPrefix(rest, "[]"), strings.HasPrefix(rest, "[*]"):
+			p = append(p, pathStep{isIndex: true, wildcard: true})
+			rest = rest[strings.IndexByte(rest, ']')+1:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, usageError("invalid JSON path %q: unterminated index", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, usageError("invalid JSON path %q: invalid index %q", path, rest[1:end])
+			}
+			p = append(p, pathStep{index: index, isIndex: true})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				if rest == "" && len(p) == 0 {
+					// "." is the whole document
+					return p, nil
+				}
+				if !strings.HasPrefix(rest, "[") {
+					return nil, usageError("invalid JSON path %q: empty key", path)
+				}
+				continue
+			}
+			p = append(p, pathStep{key: rest[:end]})
+			rest = rest[end:]
+		default:
+			return nil, usageError("invalid JSON path %q: expected \".\" or \"[\" at %q", path, rest)
+		}
+	}
+
+	return p, nil
+}
+
+// String returns the path in the form parseJSONPath reads.
+func (p jsonPath) String() string {
+	if len(p) == 0 {
+		return "."
+	}
+
+	var b strings.Builder
+	for _, step := range p {
+		switch {
+		case step.wildcard:
+			b.WriteString("[]")
+		case step.isIndex:
+			fmt.Fprintf(&b, "[%d]", step.index)
+		case identifierMatch.MatchString(step.key):
+			b.WriteString("." + step.key)
+		default:
+			fmt.Fprintf(&b, "[%q]", step.key)
+		}
+	}
+
+	return b.String()
+}
+
+// lookup returns the first value the path selects in v, a document decoded
+// with json.Decoder.UseNumber.
+func (p jsonPath) lookup(v any) (any, bool) {
+	for i, step := range p {
+		if step.isIndex {
+			array, ok := v.([]any)
+			if !ok {
+				return nil, false
+			}
+			if step.wildcard {
+				for _, element := range array {
+					if found, ok := p[i+1:].lookup(element); ok {
+						return found, true
+					}
+				}
+				return nil, false
+			}
+			if step.index >= len(array) {
+				return nil, false
+			}
+			v = array[step.index]
+			continue
+		}
+
+		object, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = object[step.key]; !ok {
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// matches tells whether the path selects the value at the given location,
+// made of keys and indexes only.
+func (p jsonPath) matches(location jsonPath) bool {
+	if len(p) != len(location) {
+		return false
+	}
+	for i, step := range p {
+		l := location[i]
+		switch {
+		case step.isIndex != l.isIndex:
+			return false
+		case step.wildcard:
+		case step.isIndex && step.index != l.index:
+			return false
+		case !step.isIndex && step.key != l.key:
+			return false
+		}
+	}
+
+	return true
+}
+
+// jsonValue is a value found in the text of a JSON document.
+type jsonValue struct {
+	start, end int      // bounds of the value in the document
+	location   jsonPath // where the value is, made of keys and indexes
+	// indent and colon are the text before the key and between the key and
+	// the value of object members.
+	indent, colon string
+	member        bool
+}
+
+// jsonScanner walks the text of a valid JSON document, calling visit with
+// every value, so values can be replaced without touching the rest of the
+// text.
+type jsonScanner struct {
+	doc   []byte
+	visit func(jsonValue)
+}
+
+func (s *jsonScanner) skipSpace(i int) int {
+	for i < len(s.doc) && strings.IndexByte(" \t\r\n", s.doc[i]) >= 0 {
+		i++
+	}
+	return i
+}
+
+// stringEnd returns the end of the string starting at i.
+func (s *jsonScanner) stringEnd(i int) (int, error) {
+	for j := i + 1; j < len(s.doc); j++ {
+		switch s.doc[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated string at offset %d", i)
+}
+
+// value scans the value starting at i, returning its end.
+func (s *jsonScanner) value(i int, v jsonValue) (int, error) {
+	if i >= len(s.doc) {
+		return 0, fmt.Errorf("unexpected end of document")
+	}
+	v.start = i
+
+	var end int
+	var err error
+	switch s.doc[i] {
+	case '{':
+		end, err = s.object(i, v.location)
+	case '[':
+		end, err = s.array(i, v.location)
+	case '"':
+		end, err = s.stringEnd(i)
+	default:
+		end = i
+		for end < len(s.doc) && strings.IndexByte(",]} \t\r\n", s.doc[end]) < 0 {
+			end++
+		}
+		if end == i {
+			err = fmt.Errorf("unexpected %q at offset %d", s.doc[i], i)
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	v.end = end
+	s.visit(v)
+	return end, nil
+}
+
+func (s *jsonScanner) object(i int, location jsonPath) (int, error) {
+	i = s.skipSpace(i + 1)
+	if i < len(s.doc) && s.doc[i] == '}' {
+		return i + 1, nil
+	}
+
+	for {
+		indentStart := i
+		for indentStart > 0 && strings.IndexByte(" \t\r\n", s.doc[indentStart-1]) >= 0 {
+			indentStart--
+		}
+		if i >= len(s.doc) || s.doc[i] != '"' {
+			return 0, fmt.Errorf("expected a key at offset %d", i)
+		}
+		keyEnd, err := s.stringEnd(i)
+		if err != nil {
+			return 0, err
+		}
+		var key string
+		if err := json.Unmarshal(s.doc[i:keyEnd], &key); err != nil {
+			return 0, err
+		}
+
+		valueStart := s.skipSpace(keyEnd)
+		if valueStart >= len(s.doc) || s.doc[valueStart] != ':' {
+			return 0, fmt.Errorf("expected \":\" at offset %d", valueStart)
+		}
+		valueStart = s.skipSpace(valueStart + 1)
+
+		member := jsonValue{
+			location: append(location[:len(location):len(location)], pathStep{key: key}),
+			colon:    string(s.doc[keyEnd:valueStart]),
+			indent:   string(s.doc[indentStart:i]),
+			member:   true,
+		}
+		end, err := s.value(valueStart, member)
+		if err != nil {
+			return 0, err
+		}
+
+		i = s.skipSpace(end)
+		switch {
+		case i < len(s.doc) && s.doc[i] == ',':
+			i = s.skipSpace(i + 1)
+		case i < len(s.doc) && s.doc[i] == '}':
+			return i + 1, nil
+		default:
+			return 0, fmt.Errorf("expected \",\" or \"}\" at offset %d", i)
+		}
+	}
+}
+
+func (s *jsonScanner) array(i int, location jsonPath) (int, error) {
+	i = s.skipSpace(i + 1)
+	if i < len(s.doc) && s.doc[i] == ']' {
+		return i + 1, nil
+	}
+
+	for index := 0; ; index++ {
+		element := jsonValue{location: append(location[:len(location):len(location)], pathStep{index: index, isIndex: true})}
+		end, err := s.value(i, element)
+		if err != nil {
+			return 0, err
+		}
+
+		i = s.skipSpace(end)
+		switch {
+		case i < len(s.doc) && s.doc[i] == ',':
+			i = s.skipSpace(i + 1)
+		case i < len(s.doc) && s.doc[i] == ']':
+			return i + 1, nil
+		default:
+			return 0, fmt.Errorf("expected \",\" or \"]\" at offset %d", i)
+		}
+	}
+}
+
+// scanJSON calls visit with every value of a JSON document, innermost first.
+func scanJSON(doc []byte, visit func(jsonValue)) error {
+	s := &jsonScanner{doc: doc, visit: visit}
+	_, err := s.value(s.skipSpace(0), jsonValue{})
+	return err
+}
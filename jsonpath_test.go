@@ -0,0 +1,75 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want jsonPath
+	}{
+		{".", nil},
+		{".ts", jsonPath{{key: "ts"}}},
+		{".request.ts", jsonPath{{key: "request"}, {key: "ts"}}},
+		{".events[1].ts", jsonPath{{key: "events"}, {index: 1, isIndex: true}, {key: "ts"}}},
+		{`.["@timestamp"]`, jsonPath{{key: "@timestamp"}}},
+		{`["a.b"][0]`, jsonPath{{key: "a.b"}, {index: 0, isIndex: true}}},
+		{".events[].ts", jsonPath{{key: "events"}, {isIndex: true, wildcard: true}, {key: "ts"}}},
+		{"[*]", jsonPath{{isIndex: true, wildcard: true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := parseJSONPath(tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, path := range []string{"ts", ".a..b", ".a[x]", ".a[-1]", ".a[0", `.["a`} {
+		t.Run(path, func(t *testing.T) {
+			_, err := parseJSONPath(path)
+			code, _ := describeError(err)
+			assert.Equal(t, exitUsage, code)
+		})
+	}
+}
+
+func TestJSONPathString(t *testing.T) {
+	for _, path := range []string{".", ".ts", ".events[].ts", `.a[2]["@timestamp"]`} {
+		p, err := parseJSONPath(path)
+		require.NoError(t, err)
+		assert.Equal(t, path, p.String())
+	}
+}
+
+func TestJSONPathLookup(t *testing.T) {
+	doc := map[string]any{"events": []any{map[string]any{"n": 1}, map[string]any{"ts": "a"}, map[string]any{"ts": "b"}}}
+
+	p, err := parseJSONPath(".events[].ts")
+	require.NoError(t, err)
+	v, ok := p.lookup(doc)
+	assert.True(t, ok)
+	assert.Equal(t, "a", v)
+
+	p, err = parseJSONPath(".events[0].ts")
+	require.NoError(t, err)
+	_, ok = p.lookup(doc)
+	assert.False(t, ok)
+}
+
+func TestScanJSON(t *testing.T) {
+	doc := `{"a": [1, {"b" :"x\"y"}], "c":null}`
+
+	var got []string
+	require.NoError(t, scanJSON([]byte(doc), func(v jsonValue) {
+		got = append(got, v.location.String()+"="+doc[v.start:v.end])
+	}))
+	assert.Equal(t, []string{`.a[0]=1`, `.a[1].b="x\"y"`, `.a[1]={"b" :"x\"y"}`, `.a=[1, {"b" :"x\"y"}]`, `.c=null`, `.=` + doc}, got)
+
+	for _, doc := range []string{`{"a" 1}`, `{"a": "b`, `[1 2]`, `{1: 2}`} {
+		assert.Error(t, scanJSON([]byte(doc), func(jsonValue) {}), doc)
+	}
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	historyEnvVar = "UT_HISTORY_FILE"
+	historyLimit  = 1000
+)
+
+// lineEditor reads lines from a terminal with basic emacs style editing and
+// history. When the input is not a terminal, lines are read as they come.
+type lineEditor struct {
+	in  *os.File
+	out io.Writer
+
+	reader   *bufio.Reader
+	history  []string
+	histFile string
+}
+
+func newLineEditor(in *os.File, out io.Writer) *lineEditor {
+	e := &lineEditor{
+		in:     in,
+		out:    out,
+		reader: bufio.NewReader(in),
+	}
+
+	e.histFile = os.Getenv(historyEnvVar)
+	if e.histFile == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			e.histFile = filepath.Join(home, ".ut_history")
+		}
+	}
+	e.loadHistory()
+
+	return e
+}
+
+func (e *lineEditor) loadHistory() {
+	if e.histFile == "" {
+		return
+	}
+
+	data, err := os.ReadFile(e.histFile)
+	if err != nil {
+		return
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			e.history = append(e.history, line)
+		}
+	}
+	if len(e.history) > historyLimit {
+		e.history = e.history[len(e.history)-historyLimit:]
+	}
+}
+
+// saveHistory writes the history back to disk. Failures are ignored, the
+// history is a convenience.
+func (e *lineEditor) saveHistory() {
+	if e.histFile == "" || len(e.history) == 0 {
+		return
+	}
+
+	_ = os.WriteFile(e.histFile, []byte(strings.Join(e.history, "\n")+"\n"), 0600)
+}
+
+func (e *lineEditor) addHistory(line string) {
+	if line == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return
+	}
+
+	e.history = append(e.history, line)
+	if len(e.history) > historyLimit {
+		e.history = e.history[1:]
+	}
+}
+
+func (e *lineEditor) isTerminal() bool {
+	return isTerminal(e.in)
+}
+
+// ReadLine prints the prompt and returns the next line, without the trailing
+// newline. It returns io.EOF when the input is exhausted.
+func (e *lineEditor) ReadLine(prompt string) (string, error) {
+	if e.isTerminal() {
+		if restore, err := makeRaw(e.in); err == nil {
+			defer restore()
+			line, err := e.edit(prompt)
+			if err == nil {
+				e.addHistory(line)
+			}
+			return line, err
+		}
+		fmt.Fprint(e.out, prompt)
+	}
+
+	line, err := e.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyCtrlH     = 8
+	keyNewline   = 10
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyEnter     = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyBackspace = 127
+)
+
+// errInterrupted is returned by ReadLine when the user presses Ctrl-C.
+var errInterrupted = errors.New("interrupted")
+
+// edit implements the line editing loop on a terminal in raw mode.
+func (e *lineEditor) edit(prompt string) (string, error) {
+	var buf []rune
+	pos := 0
+	histPos := len(e.history)
+	var pending []rune // line being edited while browsing history
+
+	redraw := func() {
+		fmt.Fprintf(e.out, "\r%s%s\x1b[K", prompt, string(buf))
+		if back := len(buf) - pos; back > 0 {
+			fmt.Fprintf(e.out, "\x1b[%dD", back)
+		}
+	}
+	setLine := func(line []rune) {
+		buf = append([]rune(nil), line...)
+		pos = len(buf)
+	}
+	historyMove := func(delta int) {
+		next := histPos + delta
+		if next < 0 || next > len(e.history) {
+			return
+		}
+		if histPos == len(e.history) {
+			pending = append([]rune(nil), buf...)
+		}
+		histPos = next
+		if histPos == len(e.history) {
+			setLine(pending)
+		} else {
+			setLine([]rune(e.history[histPos]))
+		}
+	}
+
+	redraw()
+	for {
+		r, _, err := e.reader.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case keyEnter, keyNewline:
+			fmt.Fprint(e.out, "\r\n")
+			return string(buf), nil
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupted
+		case keyCtrlD:
+			if len(buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if pos < len(buf) {
+				buf = append(buf[:pos], buf[pos+1:]...)
+			}
+		case keyBackspace, keyCtrlH:
+			if pos > 0 {
+				buf = append(buf[:pos-1], buf[pos:]...)
+				pos--
+			}
+		case keyCtrlA:
+			pos = 0
+		case keyCtrlE:
+			pos = len(buf)
+		case keyCtrlB:
+			if pos > 0 {
+				pos--
+			}
+		case keyCtrlF:
+			if pos < len(buf) {
+				pos++
+			}
+		case keyCtrlK:
+			buf = buf[:pos]
+		case keyCtrlU:
+			buf = buf[pos:]
+			pos = 0
+		case keyCtrlW:
+			start := pos
+			for start > 0 && buf[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && buf[start-1] != ' ' {
+				start--
+			}
+			buf = append(buf[:start], buf[pos:]...)
+			pos = start
+		case keyCtrlL:
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case keyCtrlP:
+			historyMove(-1)
+		case keyCtrlN:
+			historyMove(1)
+		case keyEscape:
+			switch e.readEscape() {
+			case "[A", "OA":
+				historyMove(-1)
+			case "[B", "OB":
+				historyMove(1)
+			case "[C", "OC":
+				if pos < len(buf) {
+					pos++
+				}
+			case "[D", "OD":
+				if pos > 0 {
+					pos--
+				}
+			case "[H", "OH", "[1~", "[7~":
+				pos = 0
+			case "[F", "OF", "[4~", "[8~":
+				pos = len(buf)
+			case "[3~":
+				if pos < len(buf) {
+					buf = append(buf[:pos], buf[pos+1:]...)
+				}
+			}
+		default:
+			if r < ' ' {
+				continue
+			}
+			buf = append(buf[:pos], append([]rune{r}, buf[pos:]...)...)
+			pos++
+		}
+
+		redraw()
+	}
+}
+
+// readEscape reads the rest of an escape sequence, like "[A" for the up arrow.
+func (e *lineEditor) readEscape() string {
+	first, _, err := e.reader.ReadRune()
+	if err != nil || (first != '[' && first != 'O') {
+		return ""
+	}
+
+	seq := []rune{first}
+	for {
+		r, _, err := e.reader.ReadRune()
+		if err != nil {
+			return ""
+		}
+		seq = append(seq, r)
+		if (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || r == '~' {
+			return string(seq)
+		}
+		if len(seq) > 8 {
+			return ""
+		}
+	}
+}
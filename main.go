@@ -2,7 +2,11 @@ package main
 
 import (
 	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
 	"os"
+	"path/filepath"
+	"time"
 )
 
 var version = "dev"
@@ -21,9 +25,28 @@ func handleHelp(binName string) {
 
 	fmt.Println("")
 	fmt.Println("SUBCOMMANDS:")
-	fmt.Println("  generate   Generate unix timestamp with given options")
-	fmt.Println("  help       Prints this message or the help of the given subcommand(s)")
-	fmt.Println("  parse      Parse a unix timestamp and print it in human readable format")
+	fmt.Println("  bucket          Print the time bucket (start, end and index) of each timestamp")
+	fmt.Println("  clock           Keep printing the current timestamp (same as generate --watch)")
+	fmt.Println("  cron            Print the next or previous fire times of a cron expression")
+	fmt.Println("  csv             Convert epoch columns of CSV or TSV files to dates, or dates to epochs")
+	fmt.Println("  filter          Keep the lines with a timestamp between --since and --until")
+	fmt.Println("  format-convert  Translate date formats between Go, strftime, Java, Moment and .NET")
+	fmt.Println("  generate        Generate unix timestamp with given options")
+	fmt.Println("  help            Prints this message or the help of the given subcommand(s)")
+	fmt.Println("  interval        Print the bounds of an ISO 8601 interval, expanding its repetitions")
+	fmt.Println("  id              Decode the time embedded in an ID (snowflake, UUID, ULID, ...), or generate one")
+	fmt.Println("  json            Convert the epochs at the given paths of JSON or NDJSON documents to dates")
+	fmt.Println("  parse           Parse a unix timestamp and print it in human readable format")
+	fmt.Println("  shell           Start an interactive session to convert timestamps")
+	fmt.Println("  sort            Sort lines chronologically by the timestamp they contain")
+	fmt.Println("  stats           Print statistics about timestamps: range, gaps, duplicates and order")
+	fmt.Println("  until           Print the time remaining until the given target, or wait for it")
+
+	fmt.Println("")
+	fmt.Println("EXIT CODES:")
+	for _, e := range exitCodeDescriptions {
+		fmt.Printf("  %d  %s\n", e.code, e.description)
+	}
 }
 
 func handleGenerateHelp(binName string) {
@@ -38,17 +61,229 @@ func handleGenerateHelp(binName string) {
 	options.Flags().PrintOptions(os.Stdout)
 }
 
+func handleParseHelp(binName string) {
+	options := ParseOptions{}
+	handleVersion(binName)
+	fmt.Println("Parse unix timestamps, given as arguments, on stdin or on the clipboard with")
+	fmt.Println("--paste, one per line, and print them in human readable format, in order")
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Printf("  %s [GENERAL_OPTIONS] parse [OPTIONS] [TIMESTAMP...]\n", binName)
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	options.Flags().PrintOptions(os.Stdout)
+}
+
+func handleUntilHelp(binName string) {
+	options := UntilOptions{}
+	handleVersion(binName)
+	fmt.Println("Print the time remaining until the given target, or wait for it")
+	fmt.Println("")
+	fmt.Println("The target is an epoch in the given precision, or a date in the same forms")
+	fmt.Println("accepted by the --base option of generate.")
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Printf("  %s [GENERAL_OPTIONS] until [OPTIONS] <TARGET>\n", binName)
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	options.Flags().PrintOptions(os.Stdout)
+}
+
+func handleIDHelp(binName string) {
+	options := IDOptions{}
+	handleVersion(binName)
+	fmt.Println("Decode the time embedded in an ID, or generate an ID for a given time")
+	fmt.Println("")
+	fmt.Println("Numeric IDs are decoded as Twitter snowflakes unless --kind is given.")
+	fmt.Println("Generated IDs have all their non-time bits set to zero (or one with --max),")
+	fmt.Println("so they can be used as bounds of range queries.")
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Printf("  %s [GENERAL_OPTIONS] id [OPTIONS] <ID>...\n", binName)
+	fmt.Printf("  %s [GENERAL_OPTIONS] id --generate --kind <KIND> [OPTIONS]\n", binName)
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	options.Flags().PrintOptions(os.Stdout)
+}
+
+func handleCronHelp(binName string) {
+	options := CronOptions{}
+	handleVersion(binName)
+	fmt.Println("Print the next (or previous) fire times of a cron expression")
+	fmt.Println("")
+	fmt.Println("Expressions have 5 fields (minute, hour, day of month, month, day of week),")
+	fmt.Println("6 with a leading seconds field, or are one of @yearly, @annually, @monthly,")
+	fmt.Println("@weekly, @daily, @midnight and @hourly. They are evaluated on the wall clock")
+	fmt.Println("of the zone given by --utc or --offset. Times skipped when clocks go forward")
+	fmt.Println("fire once when the gap ends; times repeated when clocks go back fire on their")
+	fmt.Println("first occurrence only.")
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Printf("  %s [GENERAL_OPTIONS] cron [OPTIONS] <EXPRESSION>\n", binName)
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	options.Flags().PrintOptions(os.Stdout)
+}
+
+func handleIntervalHelp(binName string) {
+	options := IntervalOptions{}
+	handleVersion(binName)
+	fmt.Println("Print the bounds of an ISO 8601 interval, expanding its repetitions")
+	fmt.Println("")
+	fmt.Println("Intervals are start/end, start/duration or duration/end, like")
+	fmt.Println("2023-01-01T00:00Z/P1D, optionally preceded by Rn/ for n repetitions or R/")
+	fmt.Println("to repeat forever. Times are ISO 8601, or follow --format when it is given.")
+	fmt.Println("Each repetition is printed on its own line, as start and end separated by a tab.")
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Printf("  %s [GENERAL_OPTIONS] interval [OPTIONS] <INTERVAL>\n", binName)
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	options.Flags().PrintOptions(os.Stdout)
+}
+
+func handleBucketHelp(binName string) {
+	options := BucketOptions{}
+	handleVersion(binName)
+	fmt.Println("Print the time bucket of each timestamp: its start, end and index")
+	fmt.Println("")
+	fmt.Println("Timestamps are read from the arguments or, without arguments, from stdin, one")
+	fmt.Println("per line. Fixed-size buckets are counted from the origin. Calendar buckets")
+	fmt.Println("(days, weeks, months, quarters and years) start at midnight in the zone given")
+	fmt.Println("by --utc or --offset, counted from the date of the origin.")
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Printf("  %s [GENERAL_OPTIONS] bucket --size <SIZE> [OPTIONS] [TIMESTAMP]...\n", binName)
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	options.Flags().PrintOptions(os.Stdout)
+}
+
+func handleStatsHelp(binName string) {
+	options := StatsOptions{}
+	handleVersion(binName)
+	fmt.Println("Print statistics about timestamps read from the arguments or, without")
+	fmt.Println("arguments, from stdin, one per line: count, range, gaps between consecutive")
+	fmt.Println("timestamps, duplicates and entries earlier than one before them.")
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Printf("  %s [GENERAL_OPTIONS] stats [OPTIONS] [TIMESTAMP]...\n", binName)
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	options.Flags().PrintOptions(os.Stdout)
+}
+
+// printFieldHelp describes how sort and filter find the timestamp of lines.
+func printFieldHelp() {
+	fmt.Println("Lines are read from the given files or, without files or with \"-\", from")
+	fmt.Println("stdin. Their timestamp is the first blank-separated column by default, or the")
+	fmt.Println("column given by --column and --delimiter, a capture of --regex or the value")
+	fmt.Println("at --json-path. It is an epoch, in the given precision or, without one, the")
+	fmt.Println("precision its number of digits suggests, or a date in the given format,")
+	fmt.Println("RFC 3339 by default.")
+}
+
+func handleSortHelp(binName string) {
+	options := SortOptions{}
+	handleVersion(binName)
+	fmt.Println("Sort lines chronologically by the timestamp they contain, keeping the order of")
+	fmt.Println("lines with the same timestamp. Inputs larger than --buffer-size are sorted in")
+	fmt.Println("chunks, merged from temporary files.")
+	fmt.Println("")
+	printFieldHelp()
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Printf("  %s [GENERAL_OPTIONS] sort [OPTIONS] [FILE]...\n", binName)
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	options.Flags().PrintOptions(os.Stdout)
+}
+
+func handleFilterHelp(binName string) {
+	options := FilterOptions{}
+	handleVersion(binName)
+	fmt.Println("Keep the lines with a timestamp from --since on and before --until. The bounds")
+	fmt.Println("are epochs or anything generate accepts as --base, followed by deltas, like")
+	fmt.Println("\"yesterday -3h\" or \"2024-01-02T00:00:00Z +1d\".")
+	fmt.Println("")
+	printFieldHelp()
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Printf("  %s [GENERAL_OPTIONS] filter [OPTIONS] [FILE]...\n", binName)
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	options.Flags().PrintOptions(os.Stdout)
+}
+
+func handleCSVHelp(binName string) {
+	options := CSVOptions{}
+	handleVersion(binName)
+	fmt.Println("Convert the given columns of a CSV or TSV file, or stdin, from epochs to dates")
+	fmt.Println("in the given format, RFC 3339 by default, and zone, or from dates to epochs")
+	fmt.Println("with --to-epoch. Records are converted one at a time; fields are written back")
+	fmt.Println("as they were read, quotes included. Epochs are read in the given precision or,")
+	fmt.Println("without one, the precision their number of digits suggests. Empty cells are")
+	fmt.Println("kept empty.")
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Printf("  %s [GENERAL_OPTIONS] csv --columns <COLUMNS> [OPTIONS] [FILE]\n", binName)
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	options.Flags().PrintOptions(os.Stdout)
+}
+
+func handleJSONHelp(binName string) {
+	options := JSONOptions{}
+	handleVersion(binName)
+	fmt.Println("Convert the values at the given paths of a JSON document, or a stream of them")
+	fmt.Println("like NDJSON, from epochs to dates in the given format, RFC 3339 by default,")
+	fmt.Println("and zone, or from date strings to epochs with --to-epoch. The rest of each")
+	fmt.Println("document is kept as it is; documents are printed one per line.")
+	fmt.Println("")
+	fmt.Println("Paths select keys and array elements: .ts, .events[0].ts, .events[].ts for")
+	fmt.Println("every event or .[\"@timestamp\"]. Epochs, numbers or strings, are read in the")
+	fmt.Println("precision given after the path, like .ts:ms, in the general precision or,")
+	fmt.Println("without one or with :auto, the precision their number of digits suggests.")
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Printf("  %s [GENERAL_OPTIONS] json --path <PATH> [OPTIONS] [FILE]\n", binName)
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	options.Flags().PrintOptions(os.Stdout)
+}
+
+func handleFormatConvertHelp(binName string) {
+	options := FormatConvertOptions{}
+	handleVersion(binName)
+	fmt.Println("Translate date format strings, given as arguments or read from stdin one per")
+	fmt.Println("line, between dialects: go (Go layouts), strftime (C and Python), java")
+	fmt.Println("(DateTimeFormatter), moment (Moment.js and Day.js) and dotnet (.NET custom")
+	fmt.Println("formats). Tokens without an equivalent in the target dialect are reported,")
+	fmt.Println("with exit code 3.")
+	fmt.Println("")
+	fmt.Println("The same translation reads --format in another dialect with --format-dialect.")
+	fmt.Println("")
+	fmt.Println("USAGE:")
+	fmt.Printf("  %s format-convert --from <DIALECT> --to <DIALECT> [FORMAT...]\n", binName)
+	fmt.Println("")
+	fmt.Println("OPTIONS:")
+	options.Flags().PrintOptions(os.Stdout)
+}
+
 func handleVersion(binName string) {
 	fmt.Printf("%s %s\n", binName, version)
 }
 
+// subcommands lists the subcommand names, used to suggest corrections for typos.
+var subcommands = []string{"bucket", "clock", "cron", "csv", "filter", "format-convert", "generate", "help", "id", "interval", "json", "parse", "shell", "sort", "stats", "until"}
+
 func run(runArgs ...string) error {
 	var options Options
 
 	binName := os.Args[0]
 	args, err := options.Parse(runArgs...)
 	if err != nil {
-		return fmt.Errorf("%s: %w", runArgs[0], err)
+		return err
 	}
 
 	if options.help != nil && *options.help {
@@ -62,41 +297,227 @@ func run(runArgs ...string) error {
 	}
 
 	if len(args) == 0 {
-		return fmt.Errorf("%s: missing subcommand", runArgs[0])
+		return usageError("missing subcommand")
+	}
+
+	if err := options.loadLeapSeconds(); err != nil {
+		return err
+	}
+	warnExpiredLeapSeconds(os.Stderr, options, time.Now())
+	if err := options.loadHolidays(); err != nil {
+		return err
+	}
+	if err := options.loadFormatPresets(); err != nil {
+		return err
+	}
+	if err := options.loadFormatDialect(); err != nil {
+		return err
 	}
 
 	switch args[0] {
-	case "generate", "g":
+	case "generate", "g", "clock":
+		if args[0] == "clock" {
+			args = append([]string{args[0], "--watch"}, args[1:]...)
+		}
 		generateOptions := GenerateOptions{options: options}
 		if remainingArgs, err := generateOptions.Parse(args...); err != nil {
-			return fmt.Errorf("%s: %w", runArgs[0], err)
+			return err
 		} else if len(remainingArgs) > 0 {
 			if remainingArgs[0] == "help" {
 				handleGenerateHelp(binName)
 				return nil
 			}
-			return fmt.Errorf("%s: unknown argument: %s", runArgs[0], remainingArgs[0])
+			return usageError("unknown argument: %q", remainingArgs[0])
+		}
+		if generateOptions.Watching() {
+			if generateOptions.copy {
+				return usageError("--copy cannot be used with --watch")
+			}
+			if table, err := tableOutput(options); err != nil {
+				return err
+			} else if table {
+				return usageError("--output table cannot be used with --watch")
+			}
+			return watchStdout(generateOptions)
 		}
-		if err := generate(os.Stdout, generateOptions); err != nil {
-			return fmt.Errorf("%s: %w", runArgs[0], err)
+		err := copyOutput(os.Stdout, generateOptions.copy, systemClipboard(), func(w io.Writer) error {
+			return generate(w, generateOptions)
+		})
+		if err != nil {
+			return err
 		}
 	case "parse", "p":
-		if err := parse(os.Stdout, args[1:], options); err != nil {
-			return fmt.Errorf("%s: %w", runArgs[0], err)
+		parseOptions := ParseOptions{options: options}
+		remainingArgs, err := parseOptions.Parse(args...)
+		if err != nil {
+			return err
+		}
+		if len(remainingArgs) > 0 && remainingArgs[0] == "help" {
+			handleParseHelp(binName)
+			return nil
+		}
+		return parseClipboard(os.Stdout, os.Stdin, parseOptions, remainingArgs, systemClipboard())
+	case "shell", "sh":
+		if len(args) > 1 {
+			if args[1] == "help" {
+				handleShellHelp(os.Stdout)
+				return nil
+			}
+			return usageError("unknown argument: %q", args[1])
+		}
+		return shell(os.Stdin, os.Stdout, options)
+	case "csv":
+		csvOptions := CSVOptions{options: options}
+		remainingArgs, err := csvOptions.Parse(args...)
+		if err != nil {
+			return err
+		}
+		if len(remainingArgs) > 0 && remainingArgs[0] == "help" {
+			handleCSVHelp(binName)
+			return nil
+		}
+		return convertCSV(os.Stdout, os.Stdin, csvOptions, remainingArgs)
+	case "json":
+		jsonOptions := JSONOptions{options: options}
+		remainingArgs, err := jsonOptions.Parse(args...)
+		if err != nil {
+			return err
+		}
+		if len(remainingArgs) > 0 && remainingArgs[0] == "help" {
+			handleJSONHelp(binName)
+			return nil
+		}
+		return convertJSON(os.Stdout, os.Stdin, jsonOptions, remainingArgs)
+	case "sort":
+		sortOptions := SortOptions{options: options}
+		remainingArgs, err := sortOptions.Parse(args...)
+		if err != nil {
+			return err
+		}
+		if len(remainingArgs) > 0 && remainingArgs[0] == "help" {
+			handleSortHelp(binName)
+			return nil
+		}
+		return sortLines(os.Stdout, os.Stdin, sortOptions, remainingArgs)
+	case "filter":
+		filterOptions := FilterOptions{options: options}
+		remainingArgs, err := filterOptions.Parse(args...)
+		if err != nil {
+			return err
+		}
+		if len(remainingArgs) > 0 && remainingArgs[0] == "help" {
+			handleFilterHelp(binName)
+			return nil
+		}
+		return filterLines(os.Stdout, os.Stdin, filterOptions, remainingArgs, time.Now())
+	case "format-convert":
+		formatConvertOptions := FormatConvertOptions{options: options}
+		remainingArgs, err := formatConvertOptions.Parse(args...)
+		if err != nil {
+			return err
 		}
+		if len(remainingArgs) > 0 && remainingArgs[0] == "help" {
+			handleFormatConvertHelp(binName)
+			return nil
+		}
+		return convertFormats(os.Stdout, os.Stdin, formatConvertOptions, remainingArgs)
+	case "stats":
+		statsOptions := StatsOptions{options: options}
+		remainingArgs, err := statsOptions.Parse(args...)
+		if err != nil {
+			return err
+		}
+		if len(remainingArgs) > 0 && remainingArgs[0] == "help" {
+			handleStatsHelp(binName)
+			return nil
+		}
+		return stats(os.Stdout, os.Stdin, statsOptions, remainingArgs)
+	case "until", "u":
+		untilOptions := UntilOptions{options: options}
+		remainingArgs, err := untilOptions.Parse(args...)
+		if err != nil {
+			return err
+		}
+		if len(remainingArgs) > 0 && remainingArgs[0] == "help" {
+			handleUntilHelp(binName)
+			return nil
+		}
+		return untilStdout(untilOptions, remainingArgs)
+	case "id":
+		idOptions := IDOptions{options: options}
+		remainingArgs, err := idOptions.Parse(args...)
+		if err != nil {
+			return err
+		}
+		if len(remainingArgs) > 0 && remainingArgs[0] == "help" {
+			handleIDHelp(binName)
+			return nil
+		}
+		if idOptions.generate {
+			return generateID(os.Stdout, remainingArgs, idOptions, time.Now())
+		}
+		return decodeIDs(os.Stdout, remainingArgs, idOptions)
+	case "bucket":
+		bucketOptions := BucketOptions{options: options}
+		remainingArgs, err := bucketOptions.Parse(args...)
+		if err != nil {
+			return err
+		}
+		if len(remainingArgs) > 0 && remainingArgs[0] == "help" {
+			handleBucketHelp(binName)
+			return nil
+		}
+		return bucket(os.Stdout, os.Stdin, bucketOptions, remainingArgs)
+	case "cron":
+		cronOptions := CronOptions{options: options}
+		remainingArgs, err := cronOptions.Parse(args...)
+		if err != nil {
+			return err
+		}
+		if len(remainingArgs) > 0 && remainingArgs[0] == "help" {
+			handleCronHelp(binName)
+			return nil
+		}
+		return cron(os.Stdout, cronOptions, remainingArgs, time.Now())
+	case "interval":
+		intervalOptions := IntervalOptions{options: options}
+		remainingArgs, err := intervalOptions.Parse(args...)
+		if err != nil {
+			return err
+		}
+		if len(remainingArgs) > 0 && remainingArgs[0] == "help" {
+			handleIntervalHelp(binName)
+			return nil
+		}
+		return printInterval(os.Stdout, intervalOptions, remainingArgs, time.Now())
 	case "help", "h":
 		handleHelp(binName)
 		return nil
 	default:
-		return fmt.Errorf("%s: unknown subcommand: %s", runArgs[0], args[0])
+		return unknownValueError(exitUsage, "unknown subcommand", args[0], subcommands)
 	}
 
 	return nil
 }
 
+// warnExpiredLeapSeconds warns when timestamps count leap seconds and the leap
+// second table may be missing recent ones.
+func warnExpiredLeapSeconds(w io.Writer, options Options, now time.Time) {
+	epoch, _, err := options.EpochPrecision()
+	if err != nil || !epoch.LeapSeconds() {
+		return
+	}
+
+	if table := ut.LeapSeconds(); table.Expired(now) {
+		fmt.Fprintf(w, "%s: warning: the leap second table expired on %s, use --leap-seconds or %s to read a newer leap-seconds.list\n",
+			filepath.Base(os.Args[0]), table.Expires.Format("2006-01-02"), leapEnvVar)
+	}
+}
+
 func main() {
 	if err := run(os.Args...); err != nil {
-		fmt.Printf("%s: %s\n", os.Args[0], err)
-		os.Exit(1)
+		code, msg := describeError(err)
+		fmt.Fprintf(os.Stderr, "%s: %s\n", filepath.Base(os.Args[0]), msg)
+		os.Exit(code)
 	}
 }
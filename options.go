@@ -1,47 +1,41 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
 	"github.com/pborman/getopt/v2"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
 	"time"
 )
 
+// TruncateOption is the command line value for ut.Truncate.
 type TruncateOption string
 
 const (
-	TruncateOptionNone   TruncateOption = ""
-	TruncateOptionDay    TruncateOption = "day"
-	TruncateOptionHour   TruncateOption = "hour"
-	TruncateOptionMinute TruncateOption = "minute"
-	TruncateOptionSecond TruncateOption = "second"
+	TruncateOptionNone   = TruncateOption(ut.TruncateNone)
+	TruncateOptionDay    = TruncateOption(ut.TruncateDay)
+	TruncateOptionHour   = TruncateOption(ut.TruncateHour)
+	TruncateOptionMinute = TruncateOption(ut.TruncateMinute)
+	TruncateOptionSecond = TruncateOption(ut.TruncateSecond)
 )
 
 func (opt TruncateOption) Truncate(t time.Time) (time.Time, error) {
-	switch opt {
-	case TruncateOptionDay:
-		return t.Truncate(time.Hour * 24), nil
-	case TruncateOptionHour:
-		return t.Truncate(time.Hour), nil
-	case TruncateOptionMinute:
-		return t.Truncate(time.Minute), nil
-	case TruncateOptionSecond:
-		return t.Truncate(time.Second), nil
-	case TruncateOptionNone:
-		return t, nil
-	}
-
-	return t, fmt.Errorf("unknown truncate option: %s", opt)
+	return ut.Truncate(opt).Truncate(t)
 }
 
 func (opt *TruncateOption) Set(value string, _ getopt.Option) error {
-	switch v := TruncateOption(value); v {
-	case TruncateOptionNone, TruncateOptionDay, TruncateOptionHour, TruncateOptionMinute, TruncateOptionSecond:
-		*opt = v
-	default:
-		return fmt.Errorf("unknown truncate option: %s", value)
+	v, err := ut.ParseTruncate(value)
+	if err != nil {
+		return err
 	}
 
+	*opt = TruncateOption(v)
+
 	return nil
 }
 
@@ -55,13 +49,36 @@ type Options struct {
 	help      *bool
 	version   *bool
 
-	format       string
-	formatOption getopt.Option
-
-	offset          string
-	offsetOption    getopt.Option
-	precision       string
-	precisionOption getopt.Option
+	format              string
+	formatOption        getopt.Option
+	formatDialect       string
+	formatDialectOption getopt.Option
+	dialectLayout       string // the format translated from its dialect
+
+	offset            string
+	offsetOption      getopt.Option
+	precision         string
+	precisionOption   getopt.Option
+	epoch             string
+	epochOption       getopt.Option
+	leapSeconds       string
+	leapSecondsOption getopt.Option
+	calendar          string
+	calendarOption    getopt.Option
+	inputFormat       string
+	inputFormatOption getopt.Option
+	holidays          string
+	holidaysOption    getopt.Option
+	region            string
+	regionOption      getopt.Option
+	presets           string
+	presetsOption     getopt.Option
+	template          string
+	templateOption    getopt.Option
+	output            string
+	outputOption      getopt.Option
+	color             string
+	colorOption       getopt.Option
 
 	flags *getopt.Set
 }
@@ -70,8 +87,23 @@ const (
 	offsetEnvVar    = "UT_OFFSET"
 	precisionEnvVar = "UT_PRECISION"
 	formatEnvVar    = "UT_DATETIME_FORMAT"
+	epochEnvVar     = "UT_EPOCH"
+	leapEnvVar      = "UT_LEAP_SECONDS"
+	holidaysEnvVar  = "UT_HOLIDAYS"
+	regionEnvVar    = "UT_HOLIDAY_REGION"
+	presetsEnvVar   = "UT_PRESETS"
 )
 
+// epochFamilies lists the names of the epoch families, like "unix, ldap or
+// tai", for help messages.
+func epochFamilies() string {
+	var names []string
+	for _, e := range ut.Epochs() {
+		names = append(names, e.String())
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
 func (o *Options) Flags() *getopt.Set {
 	if o.flags != nil {
 		return o.flags
@@ -83,9 +115,20 @@ func (o *Options) Flags() *getopt.Set {
 	o.help = o.flags.BoolLong("help", 'h', "Prints help information")
 	o.version = o.flags.BoolLong("version", 'V', "Prints version information")
 
-	o.formatOption = o.flags.FlagLong(&o.format, "format", 'f', "", "Format output using given format (used for generate command)")
+	o.formatOption = o.flags.FlagLong(&o.format, "format", 'f', "", "Format output using given Go layout, strftime format or preset, like rfc3339, sql or http-date")
+	o.formatDialectOption = o.flags.FlagLong(&o.formatDialect, "format-dialect", 0, "Dialect of --format: go, strftime (or python, c), java, moment (or dayjs) or dotnet", "dialect")
+	o.templateOption = o.flags.FlagLong(&o.template, "template", 0, "Print each timestamp with the given text/template, like '{{.Epoch.Ms}} {{.UTC.Format \"rfc3339\"}} {{.Relative}}'", "template")
+	o.outputOption = o.flags.FlagLong(&o.output, "output", 0, "Print several timestamps as plain lines or as a table of their epoch, local time, UTC, relative time, zone and offset", "plain|table")
+	o.colorOption = o.flags.FlagLong(&o.color, "color", 0, "Colour tables: auto (on terminals, unless NO_COLOR is set), always or never", "when")
 	o.offsetOption = o.flags.FlagLong(&o.offset, "offset", 'o', "", "Use given value as timezone offset")
 	o.precisionOption = o.flags.FlagLong(&o.precision, "precision", 'p', "", "Use given value as precision")
+	o.epochOption = o.flags.FlagLong(&o.epoch, "epoch", 'e', "Epoch family of timestamps: "+epochFamilies(), "epoch")
+	o.calendarOption = o.flags.FlagLong(&o.calendar, "calendar", 0, "Print parsed dates in another calendar: iso-week, ordinal, jdn, jd, mjd, julian, japanese or hijri", "calendar")
+	o.inputFormatOption = o.flags.FlagLong(&o.inputFormat, "input-format", 'I', "Read timestamps written by a log format: syslog, rfc5424, clf, journald, klog, java, go or auto", "format")
+	o.leapSecondsOption = o.flags.FlagLong(&o.leapSeconds, "leap-seconds", 0, "Read leap seconds from the given leap-seconds.list instead of the embedded one", "file")
+	o.holidaysOption = o.flags.FlagLong(&o.holidays, "holidays", 0, "Skip the holidays of the given ICS or YAML file in business-day deltas", "file")
+	o.regionOption = o.flags.FlagLong(&o.region, "region", 0, "Region of the holidays file to use, when it has several", "region")
+	o.presetsOption = o.flags.FlagLong(&o.presets, "presets", 0, "Read format presets from the given YAML file instead of ~/.config/ut/presets.yaml", "file")
 
 	return o.flags
 }
@@ -124,16 +167,189 @@ func (o *Options) Offset() (string, bool) {
 }
 
 func (o *Options) Precision() (string, bool) {
-	if !o.precisionOption.Seen() {
+	var seen bool
+	if o.precisionOption != nil {
+		seen = o.precisionOption.Seen()
+	}
+
+	if !seen {
 		if os.Getenv(precisionEnvVar) != "" {
 			return os.Getenv(precisionEnvVar), true
 		}
 	}
 
-	return o.precision, o.precisionOption.Seen()
+	return o.precision, seen
+}
+
+func (o *Options) Epoch() (string, bool) {
+	var seen bool
+	if o.epochOption != nil {
+		seen = o.epochOption.Seen()
+	}
+
+	if !seen {
+		if os.Getenv(epochEnvVar) != "" {
+			return os.Getenv(epochEnvVar), true
+		}
+	}
+
+	return o.epoch, seen
+}
+
+// Calendar returns the calendar used to print parsed dates, if any.
+func (o *Options) Calendar() (ut.Calendar, bool, error) {
+	if o.calendar == "" {
+		return ut.CalendarISOWeek, false, nil
+	}
+
+	c, err := ut.LookupCalendar(o.calendar)
+	return c, true, err
+}
+
+// InputFormat returns the log format timestamps are read in, if any.
+func (o *Options) InputFormat() (ut.LogFormat, bool, error) {
+	if o.inputFormat == "" {
+		return ut.LogFormat{}, false, nil
+	}
+
+	f, err := ut.LookupLogFormat(o.inputFormat)
+	return f, true, err
+}
+
+// Template returns the --template output is rendered with, if any.
+func (o *Options) Template() (*template.Template, bool, error) {
+	if o.template == "" {
+		return nil, false, nil
+	}
+
+	tmpl, err := template.New("--template").Option("missingkey=error").Parse(o.template)
+	if err != nil {
+		return nil, true, usageError("%s", err)
+	}
+	return tmpl, true, nil
+}
+
+// Output returns how several timestamps are printed: plain or table.
+func (o *Options) Output() (string, error) {
+	switch o.output {
+	case "", outputPlain:
+		return outputPlain, nil
+	case outputTable:
+		return outputTable, nil
+	}
+	return "", unknownValueError(exitUsage, "unknown --output", o.output, outputNames)
+}
+
+// LeapSeconds returns the path of the leap second table to use instead of the
+// embedded one.
+func (o *Options) LeapSeconds() (string, bool) {
+	var seen bool
+	if o.leapSecondsOption != nil {
+		seen = o.leapSecondsOption.Seen()
+	}
+
+	if !seen {
+		if os.Getenv(leapEnvVar) != "" {
+			return os.Getenv(leapEnvVar), true
+		}
+	}
+
+	return o.leapSeconds, seen
+}
+
+// loadLeapSeconds installs the leap second table given on the command line
+// or in the environment, if any.
+func (o *Options) loadLeapSeconds() error {
+	path, _ := o.LeapSeconds()
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ioError(err)
+	}
+	defer f.Close()
+
+	table, err := ut.ParseLeapSeconds(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	ut.SetLeapSeconds(table)
+
+	return nil
+}
+
+// Holidays returns the path of the holidays file skipped by business-day
+// deltas, and the region to read from it.
+func (o *Options) Holidays() (string, string) {
+	path, region := o.holidays, o.region
+	if o.holidaysOption == nil || !o.holidaysOption.Seen() {
+		if os.Getenv(holidaysEnvVar) != "" {
+			path = os.Getenv(holidaysEnvVar)
+		}
+	}
+	if o.regionOption == nil || !o.regionOption.Seen() {
+		if os.Getenv(regionEnvVar) != "" {
+			region = os.Getenv(regionEnvVar)
+		}
+	}
+
+	return path, region
+}
+
+// holidayTable holds the holidays skipped by business-day arithmetic, nil
+// for weekends only.
+var holidayTable *ut.HolidayTable
+
+// loadHolidays installs the holidays file given on the command line or in
+// the environment, if any.
+func (o *Options) loadHolidays() error {
+	path, region := o.Holidays()
+	if path == "" {
+		if region != "" {
+			return usageError("--region needs a holidays file")
+		}
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ioError(err)
+	}
+	defer f.Close()
+
+	table, err := ut.ParseHolidays(f, region)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	holidayTable = table
+
+	return nil
+}
+
+// EpochPrecision resolves the epoch family and the precision of timestamps.
+func (o *Options) EpochPrecision() (ut.Epoch, ut.Precision, error) {
+	precisionName, _ := o.Precision()
+	precision, err := ut.ParsePrecision(precisionName)
+	if err != nil {
+		return ut.EpochUnix, precision, err
+	}
+
+	epochName, _ := o.Epoch()
+	epoch, err := ut.LookupEpoch(epochName)
+	if err != nil {
+		return ut.EpochUnix, precision, err
+	}
+
+	return epoch, precision, nil
 }
 
 func (o *Options) Format() (string, bool) {
+	if o.dialectLayout != "" {
+		return o.dialectLayout, true
+	}
+
 	var seen bool
 
 	if o.formatOption != nil {
@@ -149,6 +365,77 @@ func (o *Options) Format() (string, bool) {
 	return o.format, seen
 }
 
+// Presets returns the path of the format presets file, and whether it was
+// given rather than the default one, which may not exist.
+func (o *Options) Presets() (string, bool) {
+	path := o.presets
+	if o.presetsOption == nil || !o.presetsOption.Seen() {
+		if os.Getenv(presetsEnvVar) != "" {
+			path = os.Getenv(presetsEnvVar)
+		}
+	}
+	if path != "" {
+		return path, true
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(dir, "ut", "presets.yaml"), false
+}
+
+// loadFormatPresets installs the format presets of the presets file, if any.
+func (o *Options) loadFormatPresets() error {
+	path, given := o.Presets()
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && !given {
+		return nil
+	}
+	if err != nil {
+		return ioError(err)
+	}
+	defer f.Close()
+
+	presets, err := ut.ParseFormatPresets(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	ut.SetFormatPresets(presets)
+
+	return nil
+}
+
+// loadFormatDialect translates the format from the dialect given with
+// --format-dialect, if any, to the layout Format returns.
+func (o *Options) loadFormatDialect() error {
+	if o.formatDialect == "" {
+		return nil
+	}
+
+	dialect, err := ut.LookupDialect(o.formatDialect)
+	if err != nil {
+		return err
+	}
+	format, _ := o.Format()
+	if format == "" {
+		return usageError("--format-dialect needs a format")
+	}
+	if _, ok := ut.LookupFormatPreset(format); ok {
+		// presets have a layout already
+		return nil
+	}
+	if o.dialectLayout, err = ut.FormatLayout(format, dialect); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 type GenerateOptions struct {
 	options Options
 
@@ -158,6 +445,15 @@ type GenerateOptions struct {
 	deltaOption    getopt.Option
 	truncate       TruncateOption
 	truncateOption getopt.Option
+	watch          string
+	watchOption    getopt.Option
+	zones          []string
+	zonesOption    getopt.Option
+
+	nextBusinessDay       bool
+	nextBusinessDayOption getopt.Option
+	copy                  bool
+	copyOption            getopt.Option
 
 	flags *getopt.Set
 }
@@ -172,6 +468,10 @@ func (o *GenerateOptions) Flags() *getopt.Set {
 	o.baseOption = o.flags.FlagLong(&o.base, "base", 'b', "", "Use given value as base timestamp")
 	o.deltaOption = o.flags.FlagLong(&o.delta, "delta", 'd', "", "Use given value as delta")
 	o.truncateOption = o.flags.FlagLong(&o.truncate, "truncate", 't', "", "Truncate the timestamp to the given precision")
+	o.watchOption = o.flags.FlagLong(&o.watch, "watch", 'w', "Keep printing the timestamp every interval (default: 1s, or the truncate unit)", "interval").SetOptional()
+	o.zonesOption = o.flags.FlagLong(&o.zones, "zone", 'z', "Also show the time in the given zone when watching or with --output table (can be repeated)", "zone")
+	o.nextBusinessDayOption = o.flags.FlagLong(&o.nextBusinessDay, "next-business-day", 0, "Move weekends and holidays to the start of the next business day")
+	o.copyOption = o.flags.FlagLong(&o.copy, "copy", 'c', "Also copy the result to the clipboard")
 
 	return o.flags
 }
@@ -181,5 +481,506 @@ func (o *GenerateOptions) Parse(args ...string) ([]string, error) {
 		return nil, err
 	}
 
+	remaining := o.Flags().Args()
+
+	// --watch takes an optional interval, which may come as the next argument.
+	for o.Watching() && o.watch == "" && len(remaining) > 0 {
+		if !looksLikeInterval(remaining[0]) {
+			break
+		}
+		o.watch = remaining[0]
+		if err := o.Flags().Getopt(append([]string{args[0]}, remaining[1:]...), nil); err != nil {
+			return nil, err
+		}
+		remaining = o.Flags().Args()
+	}
+
+	return remaining, nil
+}
+
+// Watching reports whether the watch mode was requested.
+func (o *GenerateOptions) Watching() bool {
+	return o.watchOption != nil && o.watchOption.Seen()
+}
+
+type ParseOptions struct {
+	options Options
+
+	copy            bool
+	copyOption      getopt.Option
+	paste           bool
+	pasteOption     getopt.Option
+	withInput       bool
+	withInputOption getopt.Option
+	null            bool
+	nullOption      getopt.Option
+
+	flags *getopt.Set
+}
+
+func (o *ParseOptions) Flags() *getopt.Set {
+	if o.flags != nil {
+		return o.flags
+	}
+
+	o.flags = getopt.New()
+
+	o.copyOption = o.flags.FlagLong(&o.copy, "copy", 'c', "Also copy the result to the clipboard")
+	o.pasteOption = o.flags.FlagLong(&o.paste, "paste", 'P', "Parse the values on the clipboard instead of stdin")
+	o.withInputOption = o.flags.FlagLong(&o.withInput, "with-input", 'i', "Print each input before its result, separated by a tab")
+	o.nullOption = o.flags.FlagLong(&o.null, "null", '0', "Read and write NUL-terminated records instead of lines")
+
+	return o.flags
+}
+
+// Parse parses the command line arguments and returns the remaining
+// arguments. Negative epochs, like -4000, are values, not options; -0 is
+// --null.
+func (o *ParseOptions) Parse(args ...string) ([]string, error) {
+	for i := 1; i < len(args) && args[i] != "--"; i++ {
+		if strings.HasPrefix(args[i], "-") && args[i] != "-0" && epochMatch.MatchString(args[i]) {
+			args = append(args[:i:i], append([]string{"--"}, args[i:]...)...)
+			break
+		}
+	}
+	if err := o.Flags().Getopt(args, nil); err != nil {
+		return nil, err
+	}
+
+	return o.Flags().Args(), nil
+}
+
+type UntilOptions struct {
+	options Options
+
+	delta          []string
+	deltaOption    getopt.Option
+	live           bool
+	liveOption     getopt.Option
+	sleep          bool
+	sleepOption    getopt.Option
+	exec           string
+	execOption     getopt.Option
+	display        string
+	displayOption  getopt.Option
+	interval       string
+	intervalOption getopt.Option
+
+	flags *getopt.Set
+}
+
+func (o *UntilOptions) Flags() *getopt.Set {
+	if o.flags != nil {
+		return o.flags
+	}
+
+	o.flags = getopt.New()
+
+	o.deltaOption = o.flags.FlagLong(&o.delta, "delta", 'd', "Apply the given delta to the target", "delta")
+	o.liveOption = o.flags.FlagLong(&o.live, "live", 'l', "Keep printing the remaining time until the target is reached")
+	o.sleepOption = o.flags.FlagLong(&o.sleep, "sleep", 's', "Print nothing, just wait until the target is reached")
+	o.execOption = o.flags.FlagLong(&o.exec, "exec", 'e', "Run the given shell command when the target is reached", "command")
+	o.displayOption = o.flags.FlagLong(&o.display, "display", 'D', "Format of the remaining time (%d days, %h hours, %m minutes, %s seconds, %H %M %S zero-padded, %T total seconds)", "format")
+	o.intervalOption = o.flags.FlagLong(&o.interval, "interval", 'i', "Refresh interval of --live (default: 1s)", "interval")
+
+	return o.flags
+}
+
+func (o *UntilOptions) Parse(args ...string) ([]string, error) {
+	if err := o.Flags().Getopt(args, nil); err != nil {
+		return nil, err
+	}
+
+	return o.Flags().Args(), nil
+}
+
+type IDOptions struct {
+	options Options
+
+	kind           string
+	kindOption     getopt.Option
+	generate       bool
+	generateOption getopt.Option
+	base           string
+	baseOption     getopt.Option
+	delta          []string
+	deltaOption    getopt.Option
+	upper          bool
+	upperOption    getopt.Option
+	verbose        bool
+	verboseOption  getopt.Option
+
+	flags *getopt.Set
+}
+
+func (o *IDOptions) Flags() *getopt.Set {
+	if o.flags != nil {
+		return o.flags
+	}
+
+	o.flags = getopt.New()
+
+	o.kindOption = o.flags.FlagLong(&o.kind, "kind", 'k', "Kind of ID: auto, twitter, discord, instagram, uuidv1, uuidv6, uuidv7, ulid, ksuid or objectid", "kind")
+	o.generateOption = o.flags.FlagLong(&o.generate, "generate", 'g', "Generate an ID for the base time instead of decoding one")
+	o.baseOption = o.flags.FlagLong(&o.base, "base", 'b', "Use given value as base time when generating", "base")
+	o.deltaOption = o.flags.FlagLong(&o.delta, "delta", 'd', "Apply the given delta to the base time when generating", "delta")
+	o.upperOption = o.flags.FlagLong(&o.upper, "max", 'M', "Generate the highest ID for the time instead of the lowest")
+	o.verboseOption = o.flags.FlagLong(&o.verbose, "verbose", 'v', "Prefix decoded times with the kind of ID")
+
+	return o.flags
+}
+
+func (o *IDOptions) Parse(args ...string) ([]string, error) {
+	if err := o.Flags().Getopt(args, nil); err != nil {
+		return nil, err
+	}
+
+	return o.Flags().Args(), nil
+}
+
+type CronOptions struct {
+	options Options
+
+	count          int
+	countOption    getopt.Option
+	previous       bool
+	previousOption getopt.Option
+	base           string
+	baseOption     getopt.Option
+	verbose        bool
+	verboseOption  getopt.Option
+
+	flags *getopt.Set
+}
+
+func (o *CronOptions) Flags() *getopt.Set {
+	if o.flags != nil {
+		return o.flags
+	}
+
+	o.flags = getopt.New()
+
+	o.count = 5
+	o.countOption = o.flags.FlagLong(&o.count, "count", 'n', "Number of fire times to print", "count")
+	o.previousOption = o.flags.FlagLong(&o.previous, "previous", 'P', "Print the fire times before the base time instead of after it")
+	o.baseOption = o.flags.FlagLong(&o.base, "base", 'b', "Use given value as base time instead of now", "base")
+	o.verboseOption = o.flags.FlagLong(&o.verbose, "verbose", 'v', "Also print the fire times in human readable format")
+
+	return o.flags
+}
+
+func (o *CronOptions) Parse(args ...string) ([]string, error) {
+	if err := o.Flags().Getopt(args, nil); err != nil {
+		return nil, err
+	}
+
+	return o.Flags().Args(), nil
+}
+
+type IntervalOptions struct {
+	options Options
+
+	count       int
+	countOption getopt.Option
+	dates       bool
+	datesOption getopt.Option
+	iso         bool
+	isoOption   getopt.Option
+
+	flags *getopt.Set
+}
+
+func (o *IntervalOptions) Flags() *getopt.Set {
+	if o.flags != nil {
+		return o.flags
+	}
+
+	o.flags = getopt.New()
+
+	o.countOption = o.flags.FlagLong(&o.count, "count", 'n', "Print at most the given number of repetitions (required for R/ intervals)", "count")
+	o.datesOption = o.flags.FlagLong(&o.dates, "dates", 'D', "Print the bounds as dates in the given format instead of epochs")
+	o.isoOption = o.flags.FlagLong(&o.iso, "iso", 0, "Print each repetition as an ISO 8601 interval")
+
+	return o.flags
+}
+
+func (o *IntervalOptions) Parse(args ...string) ([]string, error) {
+	if err := o.Flags().Getopt(args, nil); err != nil {
+		return nil, err
+	}
+
+	return o.Flags().Args(), nil
+}
+
+type BucketOptions struct {
+	options Options
+
+	size         string
+	sizeOption   getopt.Option
+	origin       string
+	originOption getopt.Option
+	dates        bool
+	datesOption  getopt.Option
+
+	flags *getopt.Set
+}
+
+func (o *BucketOptions) Flags() *getopt.Set {
+	if o.flags != nil {
+		return o.flags
+	}
+
+	o.flags = getopt.New()
+
+	o.sizeOption = o.flags.FlagLong(&o.size, "size", 's', "Size of the buckets: a duration like 15m, or day, week, month, quarter or year, optionally with a count like 2w", "size")
+	o.originOption = o.flags.FlagLong(&o.origin, "origin", 'O', "Count buckets from the given epoch (default: the unix epoch, or Monday 1970-01-05 for weeks)", "epoch")
+	o.datesOption = o.flags.FlagLong(&o.dates, "dates", 'D', "Print the bounds as dates in the given format instead of epochs")
+
+	return o.flags
+}
+
+func (o *BucketOptions) Parse(args ...string) ([]string, error) {
+	if err := o.Flags().Getopt(args, nil); err != nil {
+		return nil, err
+	}
+
+	return o.Flags().Args(), nil
+}
+
+type StatsOptions struct {
+	options Options
+
+	histogram       string
+	histogramOption getopt.Option
+
+	flags *getopt.Set
+}
+
+func (o *StatsOptions) Flags() *getopt.Set {
+	if o.flags != nil {
+		return o.flags
+	}
+
+	o.flags = getopt.New()
+
+	o.histogramOption = o.flags.FlagLong(&o.histogram, "histogram", 'H', "Also print the number of timestamps per minute, hour, day, week, month or any bucket size", "size")
+
+	return o.flags
+}
+
+func (o *StatsOptions) Parse(args ...string) ([]string, error) {
+	if err := o.Flags().Getopt(args, nil); err != nil {
+		return nil, err
+	}
+
+	return o.Flags().Args(), nil
+}
+
+// FieldOptions select the timestamp in lines of text, for sort and filter.
+type FieldOptions struct {
+	delimiter         string
+	delimiterOption   getopt.Option
+	column            int
+	columnOption      getopt.Option
+	regex             string
+	regexOption       getopt.Option
+	jsonPath          string
+	jsonPathOption    getopt.Option
+	skipInvalid       bool
+	skipInvalidOption getopt.Option
+}
+
+func (o *FieldOptions) register(flags *getopt.Set) {
+	o.column = 1
+
+	o.delimiterOption = flags.FlagLong(&o.delimiter, "delimiter", 'F', "Split lines on the given delimiter instead of runs of blanks", "delim")
+	o.columnOption = flags.FlagLong(&o.column, "column", 'k', "Read the timestamp from the given column, starting at 1", "column")
+	o.regexOption = flags.FlagLong(&o.regex, "regex", 'r', "Read the timestamp from the first capture group, or the whole match, of a regular expression", "regex")
+	o.jsonPathOption = flags.FlagLong(&o.jsonPath, "json-path", 'j', "Read the timestamp from a JSON path, like .request.ts, of lines of JSON", "path")
+	o.skipInvalidOption = flags.FlagLong(&o.skipInvalid, "skip-invalid", 0, "Drop lines without a valid timestamp instead of failing")
+}
+
+type SortOptions struct {
+	options Options
+	fields  FieldOptions
+
+	reverse          bool
+	reverseOption    getopt.Option
+	bufferSize       string
+	bufferSizeOption getopt.Option
+
+	flags *getopt.Set
+}
+
+func (o *SortOptions) Flags() *getopt.Set {
+	if o.flags != nil {
+		return o.flags
+	}
+
+	o.flags = getopt.New()
+	o.bufferSize = "64M"
+
+	o.fields.register(o.flags)
+	o.reverseOption = o.flags.FlagLong(&o.reverse, "reverse", 'R', "Print the latest lines first")
+	o.bufferSizeOption = o.flags.FlagLong(&o.bufferSize, "buffer-size", 'S', "Sort chunks of the given size in memory, like 512K or 1G, and merge them from temporary files", "size")
+
+	return o.flags
+}
+
+func (o *SortOptions) Parse(args ...string) ([]string, error) {
+	if err := o.Flags().Getopt(args, nil); err != nil {
+		return nil, err
+	}
+
+	return o.Flags().Args(), nil
+}
+
+type FilterOptions struct {
+	options Options
+	fields  FieldOptions
+
+	since       string
+	sinceOption getopt.Option
+	until       string
+	untilOption getopt.Option
+
+	flags *getopt.Set
+}
+
+func (o *FilterOptions) Flags() *getopt.Set {
+	if o.flags != nil {
+		return o.flags
+	}
+
+	o.flags = getopt.New()
+
+	o.fields.register(o.flags)
+	o.sinceOption = o.flags.FlagLong(&o.since, "since", 's', "Keep lines from the given time on: an epoch, or a base time and deltas like \"yesterday -3h\"", "time")
+	o.untilOption = o.flags.FlagLong(&o.until, "until", 'u', "Keep lines before the given time", "time")
+
+	return o.flags
+}
+
+func (o *FilterOptions) Parse(args ...string) ([]string, error) {
+	if err := o.Flags().Getopt(args, nil); err != nil {
+		return nil, err
+	}
+
+	return o.Flags().Args(), nil
+}
+
+type CSVOptions struct {
+	options Options
+
+	columns         string
+	columnsOption   getopt.Option
+	noHeader        bool
+	noHeaderOption  getopt.Option
+	toEpoch         bool
+	toEpochOption   getopt.Option
+	append          bool
+	appendOption    getopt.Option
+	suffix          string
+	suffixOption    getopt.Option
+	delimiter       string
+	delimiterOption getopt.Option
+	tsv             bool
+	tsvOption       getopt.Option
+
+	flags *getopt.Set
+}
+
+func (o *CSVOptions) Flags() *getopt.Set {
+	if o.flags != nil {
+		return o.flags
+	}
+
+	o.flags = getopt.New()
+	o.suffix = "_human"
+	o.delimiter = ","
+
+	o.columnsOption = o.flags.FlagLong(&o.columns, "columns", 'c', "Convert the given columns, by header name or index starting at 1, separated by commas", "columns")
+	o.noHeaderOption = o.flags.FlagLong(&o.noHeader, "no-header", 'N', "The first line is a record, not a header")
+	o.toEpochOption = o.flags.FlagLong(&o.toEpoch, "to-epoch", 'E', "Convert dates in the given format to epochs instead")
+	o.appendOption = o.flags.FlagLong(&o.append, "append", 'a', "Append the converted values as new columns instead of replacing them")
+	o.suffixOption = o.flags.FlagLong(&o.suffix, "suffix", 0, "Suffix of the header of appended columns (default: _human, or _epoch with --to-epoch)", "suffix")
+	o.delimiterOption = o.flags.FlagLong(&o.delimiter, "delimiter", 'd', "Field delimiter", "delim")
+	o.tsvOption = o.flags.FlagLong(&o.tsv, "tsv", 't', "Read and write tab-separated values")
+
+	return o.flags
+}
+
+func (o *CSVOptions) Parse(args ...string) ([]string, error) {
+	if err := o.Flags().Getopt(args, nil); err != nil {
+		return nil, err
+	}
+
+	return o.Flags().Args(), nil
+}
+
+type JSONOptions struct {
+	options Options
+
+	paths           []string
+	pathsOption     getopt.Option
+	toEpoch         bool
+	toEpochOption   getopt.Option
+	addSuffix       string
+	addSuffixOption getopt.Option
+
+	flags *getopt.Set
+}
+
+func (o *JSONOptions) Flags() *getopt.Set {
+	if o.flags != nil {
+		return o.flags
+	}
+
+	o.flags = getopt.New()
+
+	o.pathsOption = o.flags.FlagLong(&o.paths, "path", 'P', "Convert the values at the given path, like .events[].ts, optionally followed by a precision like :ms or :auto (repeatable)", "path")
+	o.toEpochOption = o.flags.FlagLong(&o.toEpoch, "to-epoch", 'E', "Convert date strings in the given format to epochs instead")
+	o.addSuffixOption = o.flags.FlagLong(&o.addSuffix, "add-suffix", 's', "Keep the original values and add the converted ones next to them, under their key with the given suffix", "suffix")
+
+	return o.flags
+}
+
+func (o *JSONOptions) Parse(args ...string) ([]string, error) {
+	if err := o.Flags().Getopt(args, nil); err != nil {
+		return nil, err
+	}
+
+	return o.Flags().Args(), nil
+}
+
+type FormatConvertOptions struct {
+	options Options
+
+	from       string
+	fromOption getopt.Option
+	to         string
+	toOption   getopt.Option
+
+	flags *getopt.Set
+}
+
+func (o *FormatConvertOptions) Flags() *getopt.Set {
+	if o.flags != nil {
+		return o.flags
+	}
+
+	o.flags = getopt.New()
+
+	o.fromOption = o.flags.FlagLong(&o.from, "from", 'F', "Dialect of the given formats: go, strftime (or python, c), java, moment (or dayjs) or dotnet", "dialect")
+	o.toOption = o.flags.FlagLong(&o.to, "to", 'T', "Dialect to translate the formats to", "dialect")
+
+	return o.flags
+}
+
+func (o *FormatConvertOptions) Parse(args ...string) ([]string, error) {
+	if err := o.Flags().Getopt(args, nil); err != nil {
+		return nil, err
+	}
+
 	return o.Flags().Args(), nil
 }
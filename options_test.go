@@ -1,10 +1,14 @@
 package main
 
 import (
+	"github.com/lsmoura/ut-cli/ut"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestGenerateOptionsParse(t *testing.T) {
@@ -55,3 +59,84 @@ func TestTruncateOption(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadLeapSeconds(t *testing.T) {
+	defer ut.SetLeapSeconds(nil)
+
+	path := filepath.Join(t.TempDir(), "leap-seconds.list")
+	list := "#@\t3786825600\n3692217600\t37\n"
+	require.NoError(t, os.WriteFile(path, []byte(list), 0600))
+
+	options := Options{leapSeconds: path}
+	require.NoError(t, options.loadLeapSeconds())
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), ut.LeapSeconds().Expires)
+
+	var buf strings.Builder
+	warnExpiredLeapSeconds(&buf, Options{epoch: "gps"}, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Contains(t, buf.String(), "warning: the leap second table expired on 2020-01-01")
+
+	buf.Reset()
+	warnExpiredLeapSeconds(&buf, Options{}, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Empty(t, buf.String(), "unix timestamps do not count leap seconds")
+
+	assert.Error(t, (&Options{leapSeconds: filepath.Join(t.TempDir(), "missing")}).loadLeapSeconds())
+
+	require.NoError(t, os.WriteFile(path, []byte("garbage\n"), 0600))
+	assert.ErrorIs(t, options.loadLeapSeconds(), ut.ErrInvalidLeapSeconds)
+}
+
+func TestLoadHolidays(t *testing.T) {
+	defer func() { holidayTable = nil }()
+
+	path := filepath.Join(t.TempDir(), "holidays.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("us:\n  - 2024-07-04\nbr:\n  - 2024-11-15\n"), 0600))
+
+	options := Options{holidays: path, region: "br"}
+	require.NoError(t, options.loadHolidays())
+	_, ok := holidayTable.Holiday(time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+
+	assert.ErrorIs(t, (&Options{holidays: path}).loadHolidays(), ut.ErrInvalidHolidays, "the file has several regions")
+	assert.Error(t, (&Options{holidays: filepath.Join(t.TempDir(), "missing")}).loadHolidays())
+
+	code, _ := describeError((&Options{region: "us"}).loadHolidays())
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestLoadFormatPresets(t *testing.T) {
+	defer ut.SetFormatPresets(nil)
+	t.Setenv(presetsEnvVar, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	require.NoError(t, (&Options{}).loadFormatPresets(), "a missing default file is not an error")
+
+	path := filepath.Join(t.TempDir(), "presets.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("deploy: 2006-01-02 15:04\n"), 0600))
+	t.Setenv(presetsEnvVar, path)
+	require.NoError(t, (&Options{}).loadFormatPresets())
+	p, ok := ut.LookupFormatPreset("deploy")
+	require.True(t, ok)
+	assert.Equal(t, "2006-01-02 15:04", p.Layout)
+
+	options := Options{format: "deploy", formatDialect: "java"}
+	require.NoError(t, options.loadFormatDialect(), "presets are not translated")
+	format, _ := options.Format()
+	assert.Equal(t, "deploy", format)
+
+	t.Setenv(presetsEnvVar, "")
+	code, _ := describeError((&Options{presets: filepath.Join(t.TempDir(), "missing")}).loadFormatPresets())
+	assert.Equal(t, exitIO, code, "a missing given file is an error")
+
+	require.NoError(t, os.WriteFile(path, []byte("deploy: {utc: true}\n"), 0600))
+	assert.ErrorIs(t, (&Options{presets: path}).loadFormatPresets(), ut.ErrInvalidFormatPresets)
+}
+
+func TestParseOptionsNegativeEpochs(t *testing.T) {
+	var o ParseOptions
+	args, err := o.Parse("parse", "-c", "-0", "-4000", "-1")
+	require.NoError(t, err)
+	assert.True(t, o.copy)
+	assert.True(t, o.null)
+	assert.Equal(t, []string{"-4000", "-1"}, args)
+}
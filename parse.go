@@ -2,201 +2,241 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
-	"github.com/lsmoura/ut-cli/strftime"
+	"github.com/lsmoura/ut-cli/ut"
 	"io"
 	"os"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
-
-	_ "time/tzdata"
 )
 
-var offsetToTZ = map[string]string{
-	"JST": "Asia/Tokyo",
-}
-
-var smallOffsetMatch = regexp.MustCompile(`^(\d{1}):(\d{2})$`)
-var timeOffsetMatch = regexp.MustCompile(`^([+-]?)(\d{1,2}):(\d{2})$`)
-var timeHundredOffsetMatch = regexp.MustCompile(`^([+-]?)(\d{3,4})$`)
-
-func sanitizeOffset(offset string) string {
-	if offset == "" {
-		return offset
-	}
-
-	var negative bool
-	if timeOffsetMatch.MatchString(offset) {
-		if offset[0] == '+' || offset[0] == '-' {
-			negative = offset[0] == '-'
-			offset = offset[1:]
-		}
-		if smallOffsetMatch.MatchString(offset) {
-			offset = "0" + offset
-		}
-		if negative {
-			offset = "-" + offset
-		}
-	}
+func transform(t time.Time, o Options) (time.Time, error) {
+	utc, _ := o.UTC()
+	offset, _ := o.Offset()
 
-	return offset
+	return inZone(t, utc, offset)
 }
 
-func transform(t time.Time, o Options) (time.Time, error) {
-	if utc, _ := o.UTC(); utc {
+// inZone converts t to UTC and/or the given offset or zone name.
+func inZone(t time.Time, utc bool, offset string) (time.Time, error) {
+	if utc {
 		t = t.UTC()
 	}
-	if offset, _ := o.Offset(); offset != "" {
-		offset = sanitizeOffset(offset)
-
-		var offsetLocation *time.Location
+	if offset != "" {
+		loc, err := ut.LoadZone(offset)
+		if err != nil {
+			return t, err
+		}
 
-		if matches := timeOffsetMatch.FindStringSubmatch(offset); len(matches) > 0 {
-			hours, err := strconv.Atoi(matches[2])
-			if err != nil {
-				return t, err
-			}
-			minutes, err := strconv.Atoi(matches[3])
-			if err != nil {
-				return t, err
-			}
+		t = t.In(loc)
+	}
 
-			if matches[1] == "-" {
-				hours = -hours
-				minutes = -minutes
-			}
+	return t, nil
+}
 
-			seconds := hours*3600 + minutes*60
+// eachValue calls fn with each argument or, without arguments, with each
+// non-blank line of r.
+func eachValue(r io.Reader, args []string, fn func(string) error) error {
+	return eachRecord(r, args, bufio.ScanLines, fn)
+}
 
-			offsetName := fmt.Sprintf("%d", hours*100+minutes)
-			if hours > 0 {
-				offsetName = "+" + offsetName
+// eachRecord calls fn with each argument or, without arguments, with each
+// non-blank record of r as cut by split, trimmed of spaces.
+func eachRecord(r io.Reader, args []string, split bufio.SplitFunc, fn func(string) error) error {
+	if len(args) > 0 {
+		for _, arg := range args {
+			if err := fn(arg); err != nil {
+				return err
 			}
-			offsetName = "(" + offsetName + ")"
-
-			offsetLocation = time.FixedZone(offsetName, seconds)
 		}
+		return nil
+	}
 
-		if matches := timeHundredOffsetMatch.FindStringSubmatch(offset); len(matches) > 0 {
-			value, err := strconv.Atoi(matches[2])
-			if err != nil {
-				return t, err
-			}
+	scanner := bufio.NewScanner(r)
+	scanner.Split(split)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
 
-			minutes := value % 100
-			hours := value / 100
+	return ioError(scanner.Err())
+}
 
-			if matches[1] == "-" {
-				hours = -hours
-				minutes = -minutes
-			}
+// parseTime returns the time of a timestamp, in the configured zone.
+func parseTime(arg string, options Options) (time.Time, error) {
+	epoch, precision, err := options.EpochPrecision()
+	if err != nil {
+		return time.Time{}, err
+	}
 
-			seconds := hours*3600 + minutes*60
+	var now time.Time
+	if inputFormat, ok, err := options.InputFormat(); err != nil {
+		return time.Time{}, err
+	} else if ok {
+		ref, err := transform(time.Now(), options)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if now, err = inputFormat.Parse(arg, ref); err != nil {
+			return time.Time{}, err
+		}
+	} else if now, err = epoch.Parse(arg, precision); err != nil {
+		return time.Time{}, err
+	}
 
-			offsetName := fmt.Sprintf("%d", hours*100+minutes)
-			if hours > 0 {
-				offsetName = "+" + offsetName
-			}
-			offsetName = "(" + offsetName + ")"
+	return transform(now, options)
+}
 
-			offsetLocation = time.FixedZone(offsetName, seconds)
-		}
+// parseValue returns the text parse prints for a timestamp.
+func parseValue(arg string, options Options) (string, error) {
+	now, err := parseTime(arg, options)
+	if err != nil {
+		return "", err
+	}
 
-		if offsetLocation == nil {
-			if mapped, ok := offsetToTZ[offset]; ok {
-				offset = mapped
-			}
+	if tmpl, ok, err := options.Template(); err != nil {
+		return "", err
+	} else if ok {
+		return renderTemplate(tmpl, arg, now, time.Now())
+	}
 
-			loc, err := time.LoadLocation(offset)
-			if err != nil {
-				return t, err
-			}
+	strFormat, _ := options.Format()
+	output := ut.Format(now, strFormat)
 
-			offsetLocation = loc
+	if calendar, ok, err := options.Calendar(); err != nil {
+		return "", err
+	} else if ok {
+		if output, err = calendar.Format(now); err != nil {
+			return "", err
 		}
-
-		t = t.In(offsetLocation)
 	}
 
-	return t, nil
+	return output, nil
 }
 
-func format(t time.Time, format string) string {
-	if format == "" {
-		return fmt.Sprintf("%s", t)
-	}
-
-	if strings.Contains(format, "%") {
-		return strftime.Strftime(t, format)
+// splitRecords is a bufio.SplitFunc for records terminated by sep, the last
+// one maybe not.
+func splitRecords(sep byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexByte(data, sep); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
 	}
-
-	return t.Format(format)
 }
 
-func parse(w io.Writer, args []string, options Options) error {
+// parse prints the timestamps given as arguments or, without any or with
+// "-", read from r one per line, in order. With --null records are read and
+// written NUL-terminated instead. With --output table they are printed
+// together once all are read.
+func parse(w io.Writer, r io.Reader, o ParseOptions, args []string) error {
 	if w == nil {
 		return fmt.Errorf("no writer")
 	}
-	if len(args) > 1 {
-		return fmt.Errorf("too many arguments")
-	}
 
-	var data io.Reader
-	if len(args) == 0 {
-		stat, _ := os.Stdin.Stat()
-		if (stat.Mode() & os.ModeCharDevice) != 0 {
-			return fmt.Errorf("no value to parse")
+	if table, err := tableOutput(o.options); err != nil {
+		return err
+	} else if table {
+		if o.null {
+			return usageError("--null cannot be used with --output table")
 		}
-		data = bufio.NewReader(os.Stdin)
-	} else if args[0] == "-" {
-		stat, _ := os.Stdin.Stat()
-		if (stat.Mode() & os.ModeCharDevice) != 0 {
-			return fmt.Errorf("no input")
+		var rows []tableRow
+		err := parseEach(r, o, args, func(value string) error {
+			t, err := parseTime(value, o.options)
+			if err != nil {
+				return err
+			}
+			rows = append(rows, tableRow{input: value, t: t})
+			return nil
+		})
+		if err != nil {
+			return err
 		}
-		data = bufio.NewReader(os.Stdin)
-	} else {
-		data = strings.NewReader(args[0])
+		return printTable(w, rows, o.options, time.Now())
 	}
 
-	argBytes, err := io.ReadAll(data)
-	if err != nil {
-		return err
+	sep := byte('\n')
+	if o.null {
+		sep = 0
 	}
+	return parseEach(r, o, args, func(value string) error {
+		output, err := parseValue(value, o.options)
+		if err != nil {
+			return err
+		}
+		if o.withInput {
+			output = value + "\t" + output
+		}
+		_, err = fmt.Fprintf(w, "%s%c", output, sep)
+		return ioError(err)
+	})
+}
 
-	arg := strings.Trim(string(argBytes), "\n \t")
-
-	if len(arg) == 0 {
-		return fmt.Errorf("no input")
+// parseEach calls emit with each value to parse: the arguments or the
+// records of r.
+func parseEach(r io.Reader, o ParseOptions, args []string, emit func(string) error) error {
+	if len(args) == 1 && args[0] == "-" {
+		args = nil
+	}
+	values := make([]string, 0, len(args))
+	for _, arg := range args {
+		value := strings.TrimSpace(arg)
+		if value == "" {
+			return usageError("no input")
+		}
+		values = append(values, value)
 	}
 
-	timestamp, err := strconv.ParseInt(arg, 10, 64)
-	if err != nil {
-		return err
+	if f, ok := r.(*os.File); ok && len(values) == 0 && isTerminal(f) {
+		return usageError("no value to parse")
 	}
 
-	var now time.Time
-	switch options.precision {
-	case "millisecond", "milli", "ms":
-		now = time.UnixMilli(timestamp)
-	case "microsecond", "micro", "us", "μs", "µs": // includes U+03BC (Greek letter mu) and U+00B5 (micro symbol)
-		now = time.UnixMicro(timestamp)
-	case "", "second", "s":
-		now = time.Unix(timestamp, 0)
-	default:
-		return fmt.Errorf("unknown precision: %s", options.precision)
-	}
-
-	now, err = transform(now, options)
+	sep := byte('\n')
+	if o.null {
+		sep = 0
+	}
+	count := 0
+	err := eachRecord(r, values, splitRecords(sep), func(value string) error {
+		count++
+		return emit(value)
+	})
 	if err != nil {
 		return err
 	}
-
-	strFormat, _ := options.Format()
-	if _, err := fmt.Fprintf(w, "%s\n", format(now, strFormat)); err != nil {
-		return err
+	if count == 0 {
+		return usageError("no input")
 	}
 
 	return nil
 }
+
+// parseClipboard parses the timestamps of the arguments, r or, with --paste,
+// the clipboard, copying the result to the clipboard with --copy.
+func parseClipboard(w io.Writer, r io.Reader, o ParseOptions, args []string, c *clipboard) error {
+	if o.paste {
+		if len(args) > 0 {
+			return usageError("--paste cannot be used with arguments")
+		}
+		text, err := c.paste()
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(text) == "" {
+			return usageError("nothing to parse on the clipboard")
+		}
+		r = strings.NewReader(text)
+	}
+
+	return copyOutput(w, o.copy, c, func(w io.Writer) error {
+		return parse(w, r, o, args)
+	})
+}
@@ -11,10 +11,16 @@ import (
 )
 
 func TestParse(t *testing.T) {
-	require.Error(t, parse(nil, []string{}, Options{}))
-	require.Error(t, parse(os.Stdout, []string{}, Options{}))
+	require.Error(t, parse(nil, nil, ParseOptions{}, nil))
+	require.Error(t, parse(os.Stdout, os.Stdin, ParseOptions{}, nil))
 
-	require.NoError(t, os.Setenv("TZ", "America/Toronto")) // UTC-4
+	t.Setenv("TZ", "America/Toronto") // UTC-4
+	// the local zone is read once, maybe before TZ was set by this test
+	toronto, err := time.LoadLocation("America/Toronto")
+	require.NoError(t, err)
+	local := time.Local
+	t.Cleanup(func() { time.Local = local })
+	time.Local = toronto
 	tests := []struct {
 		entry   string
 		want    string
@@ -42,11 +48,14 @@ func TestParse(t *testing.T) {
 		{"1680704033", "05/04/2023", Options{format: "%d/%m/%Y"}},
 		{"1680704033", "2023-04-05 10:13", Options{format: "%Y-%m-%d %H:%M"}},
 		{"1588059756238", "2020-04-28 03:42:36.238000", Options{precision: "millisecond", format: "%Y-%m-%d %H:%M:%S.%f"}},
+
+		{"[05/Apr/2023:10:13:53 -0400]", "2023-04-05 14:13:53 +0000 UTC", Options{inputFormat: "clf", utc: true}},
+		{"2023-04-05 14:13:53 +0000 UTC", "1680704033", Options{inputFormat: "go", format: "%s"}},
 	}
 
 	for _, tt := range tests {
 		var buf strings.Builder
-		assert.NoError(t, parse(&buf, []string{tt.entry}, tt.options))
+		assert.NoError(t, parse(&buf, nil, ParseOptions{options: tt.options}, []string{tt.entry}))
 
 		result := buf.String()
 		result = strings.Trim(result, "\n")
@@ -74,3 +83,89 @@ func TestTime(t *testing.T) {
 		}
 	}
 }
+
+func TestParseEpochFamilies(t *testing.T) {
+	tests := []struct {
+		entry   string
+		want    string
+		options Options
+	}{
+		{"133251906440000000", "2023-04-05 17:50:44 +0000 UTC", Options{utc: true, epoch: "ldap"}},
+		{"638163138440000000", "2023-04-05 17:50:44 +0000 UTC", Options{utc: true, epoch: "ticks"}},
+		{"3889705844", "2023-04-05 17:50:44 +0000 UTC", Options{utc: true, epoch: "ntp"}},
+		{"1364752262", "2023-04-05 17:50:44 +0000 UTC", Options{utc: true, epoch: "gps"}},
+		{"45021.5", "2023-04-05 12:00:00 +0000 UTC", Options{utc: true, epoch: "excel"}},
+		{"702409844", "2023-04-05 17:50:44 +0000 UTC", Options{utc: true, epoch: "cocoa"}},
+		{"13325190644000000", "2023-04-05 17:50:44 +0000 UTC", Options{utc: true, epoch: "webkit"}},
+		{"1680717081", "2023-04-05 17:50:44 +0000 UTC", Options{utc: true, epoch: "tai"}},
+		{"1483228836", "2017-01-01 00:00:00 +0000 UTC", Options{utc: true, epoch: "tai"}},
+	}
+
+	for _, tt := range tests {
+		var buf strings.Builder
+		assert.NoError(t, parse(&buf, nil, ParseOptions{options: tt.options}, []string{tt.entry}))
+		assert.Equalf(t, tt.want, strings.Trim(buf.String(), "\n"), "error parsing %q as %s", tt.entry, tt.options.epoch)
+	}
+
+	assert.Error(t, parse(&strings.Builder{}, nil, ParseOptions{options: Options{epoch: "mayan"}}, []string{"1"}))
+}
+
+func TestParseCalendar(t *testing.T) {
+	tests := []struct {
+		entry    string
+		want     string
+		calendar string
+	}{
+		{"1680717044", "2023-W14-3", "iso-week"},
+		{"1680717044", "2460040.2435648148", "jd"},
+		{"1680717044", "Reiwa 5-04-05", "japanese"},
+	}
+
+	for _, tt := range tests {
+		var buf strings.Builder
+		assert.NoError(t, parse(&buf, nil, ParseOptions{options: Options{utc: true, calendar: tt.calendar}}, []string{tt.entry}))
+		assert.Equalf(t, tt.want, strings.Trim(buf.String(), "\n"), "error parsing %q as %s", tt.entry, tt.calendar)
+	}
+
+	code, msg := describeError(parse(&strings.Builder{}, nil, ParseOptions{options: Options{calendar: "hijra"}}, []string{"1680717044"}))
+	assert.Equal(t, exitUsage, code)
+	assert.Equal(t, `unknown calendar: "hijra" (did you mean "hijri"?)`, msg)
+
+	code, _ = describeError(parse(&strings.Builder{}, nil, ParseOptions{options: Options{calendar: "japanese"}}, []string{"-4000000000"}))
+	assert.Equal(t, exitOutOfRange, code)
+}
+
+func TestParseMultiple(t *testing.T) {
+	options := Options{utc: true, format: "sql"}
+
+	tests := []struct {
+		name    string
+		options ParseOptions
+		input   string
+		args    []string
+		want    string
+	}{
+		{"arguments", ParseOptions{options: options}, "", []string{"1680717044", "-4000", "0"}, "2023-04-05 17:50:44\n1969-12-31 22:53:20\n1970-01-01 00:00:00\n"},
+		{"lines", ParseOptions{options: options}, "1680717044\n\n  0\r\n", nil, "2023-04-05 17:50:44\n1970-01-01 00:00:00\n"},
+		{"dash", ParseOptions{options: options}, "0\n", []string{"-"}, "1970-01-01 00:00:00\n"},
+		{"with input", ParseOptions{options: options, withInput: true}, "", []string{"1680717044", "0"}, "1680717044\t2023-04-05 17:50:44\n0\t1970-01-01 00:00:00\n"},
+		{"null", ParseOptions{options: options, null: true}, "1680717044\x000\x00", nil, "2023-04-05 17:50:44\x001970-01-01 00:00:00\x00"},
+		{"null without terminator", ParseOptions{options: options, null: true, withInput: true}, "0\n\x001", nil, "0\t1970-01-01 00:00:00\x001\t1970-01-01 00:00:01\x00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			require.NoError(t, parse(&buf, strings.NewReader(tt.input), tt.options, tt.args))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	var buf strings.Builder
+	err := parse(&buf, nil, ParseOptions{options: options}, []string{"0", "nope", "1"})
+	code, _ := describeError(err)
+	assert.Equal(t, exitInvalidInput, code)
+	assert.Equal(t, "1970-01-01 00:00:00\n", buf.String(), "values before the invalid one are printed")
+
+	code, _ = describeError(parse(&buf, strings.NewReader("\n\n"), ParseOptions{}, nil))
+	assert.Equal(t, exitUsage, code)
+}
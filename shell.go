@@ -0,0 +1,261 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+const shellPrompt = "ut> "
+
+// shellSession holds the state of an interactive session. It starts from the
+// general options and is changed with :commands.
+type shellSession struct {
+	utc       bool
+	zone      string
+	epoch     ut.Epoch
+	precision ut.Precision
+	format    string
+
+	// values holds the last two values seen, most recent last, for :diff.
+	values []time.Time
+	now    func() time.Time
+}
+
+func newShellSession(options Options) (*shellSession, error) {
+	s := &shellSession{now: time.Now}
+
+	s.utc, _ = options.UTC()
+	s.zone, _ = options.Offset()
+	s.format, _ = options.Format()
+
+	epoch, precision, err := options.EpochPrecision()
+	if err != nil {
+		return nil, err
+	}
+	s.epoch = epoch
+	s.precision = precision
+
+	if _, err := inZone(time.Now(), s.utc, s.zone); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func handleShellHelp(w io.Writer) {
+	fmt.Fprintln(w, "Type an epoch to see it as a date, or a date to see it as an epoch.")
+	fmt.Fprintln(w, "Dates use the session format as layout (RFC3339 by default), and may be")
+	fmt.Fprintln(w, "followed by deltas, like \"now -3h\" or \"yesterday +30min\".")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "COMMANDS:")
+	fmt.Fprintln(w, "  :zone [ZONE]        Show or set the zone (offset, abbreviation or IANA name, \"local\" to reset)")
+	fmt.Fprintln(w, "  :utc                Use the UTC zone")
+	fmt.Fprintln(w, "  :epoch [FAMILY]     Show or set the epoch family: "+epochFamilies())
+	fmt.Fprintln(w, "  :precision [VALUE]  Show or set the epoch precision")
+	fmt.Fprintln(w, "  :format [FORMAT]    Show or set the date format (empty to reset)")
+	fmt.Fprintln(w, "  :diff               Show the difference between the last two values")
+	fmt.Fprintln(w, "  :state              Show the session state")
+	fmt.Fprintln(w, "  :help               Prints this message")
+	fmt.Fprintln(w, "  :quit               Leave the shell (Ctrl-D works too)")
+}
+
+// errQuit is returned by eval when the session should end.
+var errQuit = errors.New("quit")
+
+// eval handles a single line of input.
+func (s *shellSession) eval(w io.Writer, line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+
+	if strings.HasPrefix(line, ":") {
+		fields := strings.Fields(line)
+		arg := strings.TrimSpace(strings.TrimPrefix(line, fields[0]))
+		return s.command(w, fields[0][1:], arg)
+	}
+
+	if epochMatch.MatchString(line) {
+		t, err := s.epoch.Parse(line, s.precision)
+		if err != nil {
+			return err
+		}
+		t, err = inZone(t, s.utc, s.zone)
+		if err != nil {
+			return err
+		}
+		s.push(t)
+		_, err = fmt.Fprintln(w, ut.Format(t, s.format))
+		return err
+	}
+
+	t, err := s.parseDate(line)
+	if err != nil {
+		return err
+	}
+	s.push(t)
+	_, err = fmt.Fprintln(w, s.epoch.Format(t, s.precision))
+	return err
+}
+
+// parseDate parses a date, optionally followed by deltas separated by spaces.
+func (s *shellSession) parseDate(line string) (time.Time, error) {
+	return parseDateDeltas(line, s.format, s.now())
+}
+
+func (s *shellSession) push(t time.Time) {
+	s.values = append(s.values, t)
+	if len(s.values) > 2 {
+		s.values = s.values[len(s.values)-2:]
+	}
+}
+
+func (s *shellSession) command(w io.Writer, name string, arg string) error {
+	switch name {
+	case "zone", "z", "offset":
+		if arg == "" {
+			break
+		}
+		zone := arg
+		if zone == "local" {
+			zone = ""
+		}
+		if _, err := inZone(s.now(), false, zone); err != nil {
+			return err
+		}
+		s.utc = false
+		s.zone = zone
+	case "utc":
+		s.utc = true
+		s.zone = ""
+	case "epoch", "e":
+		if arg == "" {
+			break
+		}
+		epoch, err := ut.LookupEpoch(arg)
+		if err != nil {
+			return err
+		}
+		s.epoch = epoch
+	case "precision", "p":
+		if arg == "" {
+			break
+		}
+		precision, err := ut.ParsePrecision(arg)
+		if err != nil {
+			return err
+		}
+		s.precision = precision
+	case "format", "f":
+		s.format = arg
+	case "diff", "d":
+		return s.diff(w)
+	case "state", "show":
+		return s.state(w)
+	case "help", "h", "?":
+		handleShellHelp(w)
+		return nil
+	case "quit", "q", "exit":
+		return errQuit
+	default:
+		return unknownValueError(exitUsage, "unknown command", ":"+name, shellCommands)
+	}
+
+	return s.state(w)
+}
+
+// shellCommands lists the session commands, used to suggest corrections for typos.
+var shellCommands = []string{":zone", ":utc", ":epoch", ":precision", ":format", ":diff", ":state", ":help", ":quit"}
+
+func (s *shellSession) diff(w io.Writer) error {
+	if len(s.values) < 2 {
+		return usageError("need two values to diff")
+	}
+
+	// epoch values may not fit in an int64, so subtract them as big integers
+	first, _ := new(big.Int).SetString(ut.EpochUnix.Format(s.values[0], s.precision), 10)
+	last, _ := new(big.Int).SetString(ut.EpochUnix.Format(s.values[1], s.precision), 10)
+	n := new(big.Int).Sub(last, first)
+
+	_, err := fmt.Fprintf(w, "%s (%s %ss)\n", span(s.values[0], s.values[1]), n, s.precision)
+	return err
+}
+
+// span formats the time from a to b like time.Duration does. Beyond the 292
+// years a time.Duration holds, whole days are counted separately.
+func span(a, b time.Time) string {
+	if d := b.Sub(a); a.Add(d).Equal(b) {
+		return d.String()
+	}
+
+	sign := ""
+	if b.Before(a) {
+		sign = "-"
+		a, b = b, a
+	}
+	days := (b.Unix() - a.Unix()) / 86400
+	rest := b.Sub(time.Unix(a.Unix()+days*86400, int64(a.Nanosecond())))
+	if rest < 0 {
+		days--
+		rest += 24 * time.Hour
+	}
+
+	return fmt.Sprintf("%s%dd%s", sign, days, rest)
+}
+
+func (s *shellSession) state(w io.Writer) error {
+	zone := s.zone
+	switch {
+	case s.utc && zone == "":
+		zone = "UTC"
+	case zone == "":
+		zone = "local"
+	}
+
+	format := s.format
+	if format == "" {
+		format = "(default)"
+	}
+
+	_, err := fmt.Fprintf(w, "zone=%s epoch=%s precision=%s format=%s\n", zone, s.epoch, s.precision, format)
+	return err
+}
+
+// shell runs the interactive session until the input ends or :quit is typed.
+func shell(in *os.File, w io.Writer, options Options) error {
+	session, err := newShellSession(options)
+	if err != nil {
+		return err
+	}
+
+	editor := newLineEditor(in, w)
+	defer editor.saveHistory()
+
+	if editor.isTerminal() {
+		fmt.Fprintln(w, "Type :help for help, :quit to leave.")
+	}
+
+	for {
+		line, err := editor.ReadLine(shellPrompt)
+		if errors.Is(err, errInterrupted) {
+			continue
+		} else if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return ioError(err)
+		}
+
+		if err := session.eval(w, line); errors.Is(err, errQuit) {
+			return nil
+		} else if err != nil {
+			_, msg := describeError(err)
+			fmt.Fprintf(w, "error: %s\n", msg)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShellSession(t *testing.T) {
+	session, err := newShellSession(Options{utc: true})
+	require.NoError(t, err)
+	session.now = func() time.Time { return time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC) }
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"1680717044", "2023-04-05 17:50:44 +0000 UTC"},
+		{":zone Asia/Tokyo", "zone=Asia/Tokyo epoch=unix precision=second format=(default)"},
+		{"1680717044", "2023-04-06 02:50:44 +0900 JST"},
+		{":precision ms", "zone=Asia/Tokyo epoch=unix precision=millisecond format=(default)"},
+		{"2023-04-05T17:50:44Z", "1680717044000"},
+		{"now -1h", "1680713444000"},
+		{":diff", "-1h0m0s (-3600000 milliseconds)"},
+		{":format %Y-%m-%d", "zone=Asia/Tokyo epoch=unix precision=millisecond format=%Y-%m-%d"},
+		{"1680717044000", "2023-04-06"},
+		{":utc", "zone=UTC epoch=unix precision=millisecond format=%Y-%m-%d"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		var buf strings.Builder
+		require.NoErrorf(t, session.eval(&buf, test.line), "evaluating %q", test.line)
+		assert.Equalf(t, test.want, strings.TrimRight(buf.String(), "\n"), "evaluating %q", test.line)
+	}
+
+	var buf strings.Builder
+	assert.ErrorIs(t, session.eval(&buf, ":quit"), errQuit)
+
+	_, msg := describeError(session.eval(&buf, ":zone Asia/Tokio"))
+	assert.Equal(t, `unknown time zone: "Asia/Tokio" (did you mean "Asia/Tokyo"?)`, msg)
+
+	_, msg = describeError(session.eval(&buf, ":presicion ms"))
+	assert.Equal(t, `unknown command: ":presicion" (did you mean ":precision"?)`, msg)
+}
+
+func TestShellDiffNeedsTwoValues(t *testing.T) {
+	session, err := newShellSession(Options{})
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, session.eval(&buf, "1680717044"))
+	assert.Error(t, session.eval(&buf, ":diff"))
+}
+
+func TestShellDiffLongSpan(t *testing.T) {
+	session, err := newShellSession(Options{utc: true, precision: "ns"})
+	require.NoError(t, err)
+
+	var buf strings.Builder
+	require.NoError(t, session.eval(&buf, "-30000000000000000000"))
+	require.NoError(t, session.eval(&buf, "30000000000000000000"))
+
+	buf.Reset()
+	require.NoError(t, session.eval(&buf, ":diff"))
+	assert.Equal(t, "694444d10h40m0s (60000000000000000000 nanoseconds)\n", buf.String())
+}
+
+func TestShellFromPipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	t.Setenv(historyEnvVar, t.TempDir()+"/history")
+
+	_, err = w.WriteString("1680717044\n:zone 9:00\n1680717044\n:q\n1\n")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	var buf strings.Builder
+	require.NoError(t, shell(r, &buf, Options{utc: true}))
+	assert.Equal(t, "2023-04-05 17:50:44 +0000 UTC\n"+
+		"zone=9:00 epoch=unix precision=second format=(default)\n"+
+		"2023-04-06 02:50:44 +0900 (+900)\n", buf.String())
+}
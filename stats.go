@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxHistogramBuckets bounds the histogram, which lists empty buckets too.
+const maxHistogramBuckets = 10000
+
+// histogramBarWidth is the width of the largest bar of the histogram.
+const histogramBarWidth = 40
+
+// gapPercentiles are the percentiles of the gaps between timestamps shown by stats.
+var gapPercentiles = []int{50, 90, 99}
+
+// timeKey identifies an instant, to find duplicates.
+type timeKey struct {
+	sec  int64
+	nsec int
+}
+
+// gap is the time between two consecutive timestamps, in chronological order.
+type gap struct {
+	from, to time.Time
+	d        time.Duration // saturated beyond 292 years, for sorting
+}
+
+// timeStats summarizes a stream of timestamps.
+type timeStats struct {
+	times      []time.Time // in input order
+	duplicates int
+	outOfOrder int
+}
+
+// readStats parses the timestamps of the stats subcommand.
+func readStats(r io.Reader, o StatsOptions, args []string) (*timeStats, error) {
+	epoch, precision, err := o.options.EpochPrecision()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &timeStats{}
+	seen := map[timeKey]bool{}
+	var latest time.Time
+	err = eachValue(r, args, func(value string) error {
+		t, err := epoch.Parse(value, precision)
+		if err != nil {
+			return err
+		}
+		if t, err = transform(t, o.options); err != nil {
+			return err
+		}
+
+		key := timeKey{t.Unix(), t.Nanosecond()}
+		if seen[key] {
+			s.duplicates++
+		}
+		seen[key] = true
+		if len(s.times) > 0 && t.Before(latest) {
+			s.outOfOrder++
+		}
+		if len(s.times) == 0 || t.After(latest) {
+			latest = t
+		}
+
+		s.times = append(s.times, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(s.times) == 0 {
+		return nil, usageError("no timestamps")
+	}
+
+	return s, nil
+}
+
+// sorted returns the timestamps in chronological order.
+func (s *timeStats) sorted() []time.Time {
+	sorted := append([]time.Time(nil), s.times...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	return sorted
+}
+
+// gapsBetween returns the gaps between consecutive timestamps, given in
+// chronological order, shortest first.
+func gapsBetween(sorted []time.Time) []gap {
+	var gaps []gap
+	for i := 1; i < len(sorted); i++ {
+		gaps = append(gaps, gap{from: sorted[i-1], to: sorted[i], d: sorted[i].Sub(sorted[i-1])})
+	}
+	sort.SliceStable(gaps, func(i, j int) bool { return gaps[i].d < gaps[j].d })
+
+	return gaps
+}
+
+// percentile returns the gap at the given percentile, with the nearest-rank
+// method.
+func percentile(gaps []gap, p int) gap {
+	rank := (p*len(gaps) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return gaps[rank-1]
+}
+
+func stats(w io.Writer, r io.Reader, o StatsOptions, args []string) error {
+	s, err := readStats(r, o, args)
+	if err != nil {
+		return err
+	}
+
+	epoch, precision, err := o.options.EpochPrecision()
+	if err != nil {
+		return err
+	}
+	format, _ := o.options.Format()
+
+	sorted := s.sorted()
+	first, last := sorted[0], sorted[len(sorted)-1]
+
+	lines := [][2]string{
+		{"count", strconv.Itoa(len(s.times))},
+		{"duplicates", strconv.Itoa(s.duplicates)},
+		{"out of order", strconv.Itoa(s.outOfOrder)},
+		{"min", epoch.Format(first, precision) + "  " + ut.Format(first, format)},
+		{"max", epoch.Format(last, precision) + "  " + ut.Format(last, format)},
+		{"span", span(first, last)},
+	}
+	if gaps := gapsBetween(sorted); len(gaps) > 0 {
+		lines = append(lines, [2]string{"gap min", span(gaps[0].from, gaps[0].to)})
+		for _, p := range gapPercentiles {
+			g := percentile(gaps, p)
+			lines = append(lines, [2]string{fmt.Sprintf("gap p%d", p), span(g.from, g.to)})
+		}
+		g := gaps[len(gaps)-1]
+		lines = append(lines, [2]string{"gap max", fmt.Sprintf("%s  after %s", span(g.from, g.to), epoch.Format(g.from, precision))})
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintf(w, "%-13s %s\n", line[0], line[1]); err != nil {
+			return ioError(err)
+		}
+	}
+
+	if o.histogram == "" {
+		return nil
+	}
+
+	return histogram(w, o, sorted)
+}
+
+// histogram prints the number of timestamps in each bucket of the histogram
+// size, empty buckets included, between the first and the last timestamp.
+func histogram(w io.Writer, o StatsOptions, sorted []time.Time) error {
+	value := o.histogram
+	switch value {
+	case "minute":
+		value = "1m"
+	case "hour":
+		value = "1h"
+	}
+	size, err := ut.ParseBucketSize(value)
+	if err != nil {
+		return err
+	}
+
+	// fixed sizes, like hours, follow the wall clock of the zone too
+	first := sorted[0]
+	origin := size.DefaultOrigin()
+	origin = time.Date(origin.Year(), origin.Month(), origin.Day(), 0, 0, 0, 0, first.Location())
+
+	firstBucket, err := size.Bucket(first, origin)
+	if err != nil {
+		return err
+	}
+	lastBucket, err := size.Bucket(sorted[len(sorted)-1], origin)
+	if err != nil {
+		return err
+	}
+	if n := lastBucket.Index - firstBucket.Index + 1; n > maxHistogramBuckets {
+		return usageError("the histogram would have %d buckets, use a larger size than %q", n, o.histogram)
+	}
+
+	counts := make([]int, lastBucket.Index-firstBucket.Index+1)
+	highest := 0
+	for _, t := range sorted {
+		b, err := size.Bucket(t, origin)
+		if err != nil {
+			return err
+		}
+		i := b.Index - firstBucket.Index
+		counts[i]++
+		if counts[i] > highest {
+			highest = counts[i]
+		}
+	}
+
+	format, _ := o.options.Format()
+	b := firstBucket
+	for _, c := range counts {
+		bar := strings.Repeat("#", (c*histogramBarWidth+highest-1)/highest)
+		if _, err := fmt.Fprintf(w, "%s\t%d\t%s\n", ut.Format(b.Start, format), c, bar); err != nil {
+			return ioError(err)
+		}
+
+		if b, err = size.Bucket(b.End, origin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
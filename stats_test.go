@@ -0,0 +1,68 @@
+package main
+
+import (
+	"github.com/lsmoura/ut-cli/ut"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	input := "1680717044\n1680717104\n1680717104\n\n1680717050\n1680720000\n1680730000\n"
+
+	var buf strings.Builder
+	o := StatsOptions{options: Options{utc: true}}
+	args, err := o.Parse("stats", "--histogram", "hour")
+	require.NoError(t, err)
+	require.NoError(t, stats(&buf, strings.NewReader(input), o, args))
+
+	assert.Equal(t, "count         6\n"+
+		"duplicates    1\n"+
+		"out of order  1\n"+
+		"min           1680717044  2023-04-05 17:50:44 +0000 UTC\n"+
+		"max           1680730000  2023-04-05 21:26:40 +0000 UTC\n"+
+		"span          3h35m56s\n"+
+		"gap min       0s\n"+
+		"gap p50       54s\n"+
+		"gap p90       2h46m40s\n"+
+		"gap p99       2h46m40s\n"+
+		"gap max       2h46m40s  after 1680720000\n"+
+		"2023-04-05 17:00:00 +0000 UTC\t4\t########################################\n"+
+		"2023-04-05 18:00:00 +0000 UTC\t1\t##########\n"+
+		"2023-04-05 19:00:00 +0000 UTC\t0\t\n"+
+		"2023-04-05 20:00:00 +0000 UTC\t0\t\n"+
+		"2023-04-05 21:00:00 +0000 UTC\t1\t##########\n", buf.String())
+}
+
+func TestStatsHistogramZone(t *testing.T) {
+	// hours follow the wall clock of the zone, even with a half-hour offset
+	var buf strings.Builder
+	o := StatsOptions{options: Options{offset: "Asia/Kolkata", precision: "ms", format: "%H:%M"}, histogram: "hour"}
+	require.NoError(t, stats(&buf, strings.NewReader(""), o, []string{"1680717044000", "1680720644000"}))
+	assert.Contains(t, buf.String(), "min           1680717044000  23:20\n")
+	assert.True(t, strings.HasSuffix(buf.String(), "23:00\t1\t########################################\n00:00\t1\t########################################\n"))
+
+	buf.Reset()
+	o = StatsOptions{options: Options{utc: true}, histogram: "day"}
+	require.NoError(t, stats(&buf, strings.NewReader(""), o, []string{"1680717044"}))
+	assert.Contains(t, buf.String(), "count         1\n")
+	assert.NotContains(t, buf.String(), "gap")
+	assert.True(t, strings.HasSuffix(buf.String(), "2023-04-05 00:00:00 +0000 UTC\t1\t########################################\n"))
+}
+
+func TestStatsErrors(t *testing.T) {
+	err := stats(&strings.Builder{}, strings.NewReader("\n"), StatsOptions{}, nil)
+	code, _ := describeError(err)
+	assert.Equal(t, exitUsage, code)
+
+	err = stats(&strings.Builder{}, strings.NewReader("12\nfoo\n"), StatsOptions{}, nil)
+	assert.ErrorIs(t, err, ut.ErrInvalidTimestamp)
+
+	err = stats(&strings.Builder{}, strings.NewReader(""), StatsOptions{histogram: "1s"}, []string{"0", "1680717044"})
+	code, _ = describeError(err)
+	assert.Equal(t, exitUsage, code, "too many buckets")
+
+	err = stats(&strings.Builder{}, strings.NewReader(""), StatsOptions{histogram: "fortnight"}, []string{"0"})
+	assert.ErrorIs(t, err, ut.ErrInvalidBucketSize)
+}
@@ -2,6 +2,7 @@ package strftime
 
 import (
 	"fmt"
+	"github.com/lsmoura/ut-cli/calendar"
 	"strconv"
 	"strings"
 	"time"
@@ -24,7 +25,8 @@ func StrTimeTokens(format string) []string {
 				// malformed format string
 				break
 			}
-			if format[i+1] == '-' {
+			if format[i+1] == '-' || format[i+1] == 'E' || format[i+1] == 'O' {
+				// flag or modifier, part of the directive
 				extraN++
 			}
 			if i == len(format)-extraN {
@@ -34,7 +36,7 @@ func StrTimeTokens(format string) []string {
 			pieces = append(pieces, format[i:i+extraN+1])
 			i += extraN
 		} else {
-			block += string(c)
+			block += format[i : i+1]
 		}
 	}
 
@@ -131,6 +133,43 @@ func Strftime(t time.Time, format string) string {
 			output = append(output, t.Format("01/02/06"))
 		case "%X": // Locale's appropriate time representation
 			output = append(output, t.Format("15:04:05"))
+		case "%G": // ISO 8601 week-based year
+			year, _ := t.ISOWeek()
+			output = append(output, strconv.Itoa(year))
+		case "%V": // ISO 8601 week number as a zero-padded decimal number
+			_, week := t.ISOWeek()
+			output = append(output, fmt.Sprintf("%02d", week))
+		case "%-V": // ISO 8601 week number as a decimal number
+			_, week := t.ISOWeek()
+			output = append(output, strconv.Itoa(week))
+		case "%u": // ISO 8601 weekday as a decimal number, where 1 is Monday and 7 is Sunday
+			output = append(output, strconv.Itoa((int(t.Weekday())+6)%7+1))
+		case "%J": // Julian day number
+			output = append(output, strconv.FormatInt(calendar.JulianDayNumber(t), 10))
+		case "%Ej": // Date in the proleptic Julian calendar
+			year, month, day := calendar.Julian(t)
+			output = append(output, fmt.Sprintf("%04d-%02d-%02d", year, month, day))
+		case "%Eh": // Date in the tabular Hijri calendar
+			year, month, day := calendar.Hijri(t)
+			output = append(output, fmt.Sprintf("%04d-%02d-%02d", year, month, day))
+		case "%EC": // Japanese era name, or the century before the Meiji era
+			if name, _, ok := calendar.JapaneseEra(t); ok {
+				output = append(output, name)
+			} else {
+				output = append(output, fmt.Sprintf("%02d", t.Year()/100))
+			}
+		case "%Ey": // Year of the Japanese era, or the year without century before it
+			if _, year, ok := calendar.JapaneseEra(t); ok {
+				output = append(output, strconv.Itoa(year))
+			} else {
+				output = append(output, fmt.Sprintf("%02d", t.Year()%100))
+			}
+		case "%EY": // Japanese era and year, like "Reiwa 5", or the year before the Meiji era
+			if name, year, ok := calendar.JapaneseEra(t); ok {
+				output = append(output, fmt.Sprintf("%s %d", name, year))
+			} else {
+				output = append(output, strconv.Itoa(t.Year()))
+			}
 		case "%%":
 			output = append(output, "%")
 
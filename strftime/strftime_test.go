@@ -16,6 +16,8 @@ func TestStrTimeTokens(t *testing.T) {
 		{"%A %B %C", []string{"%A", " ", "%B", " ", "%C"}},
 		{"%Afoo%Bbar %C", []string{"%A", "foo", "%B", "bar ", "%C"}},
 		{"%-Afoo%Bbar %C", []string{"%-A", "foo", "%B", "bar ", "%C"}},
+		{"%EY-%m %Ej%Od", []string{"%EY", "-", "%m", " ", "%Ej", "%Od"}},
+		{"%Y年%m月", []string{"%Y", "年", "%m", "月"}},
 	}
 
 	for _, test := range tests {
@@ -67,6 +69,15 @@ func TestStrftime(t *testing.T) {
 		{"%X", time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "03:04:05"},
 		{"%%", time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "%"},
 		{"%s", time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "1483326245"},
+		{"%G-W%V-%u", time.Date(2023, 4, 5, 3, 4, 5, 0, time.UTC), "2023-W14-3"},
+		{"%G-W%V-%u", time.Date(2021, 1, 3, 3, 4, 5, 0, time.UTC), "2020-W53-7"},
+		{"%-V", time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), "1"},
+		{"%J", time.Date(2023, 4, 5, 3, 4, 5, 0, time.UTC), "2460040"},
+		{"%Ej", time.Date(2023, 4, 5, 3, 4, 5, 0, time.UTC), "2023-03-23"},
+		{"%Eh", time.Date(2023, 4, 5, 3, 4, 5, 0, time.UTC), "1444-09-14"},
+		{"%EY-%m-%d", time.Date(2023, 4, 5, 3, 4, 5, 0, time.UTC), "Reiwa 5-04-05"},
+		{"%EC %Ey", time.Date(1989, 1, 7, 3, 4, 5, 0, time.UTC), "Showa 64"},
+		{"%EC %Ey %EY", time.Date(1850, 1, 7, 3, 4, 5, 0, time.UTC), "18 50 1850"},
 	}
 
 	for _, test := range tests {
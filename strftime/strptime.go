@@ -0,0 +1,337 @@
+package strftime
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrParse is returned when a value does not match the format given to Strptime.
+var ErrParse = errors.New("cannot parse time")
+
+// LeapSecondError is returned by Strptime for times on a 60th second, like
+// 23:59:60. A time.Time cannot hold a leap second; Time is the instant right
+// after it, so callers that know the leap second exists can use it.
+type LeapSecondError struct {
+	Value string
+	Time  time.Time
+}
+
+func (e *LeapSecondError) Error() string {
+	return fmt.Sprintf("leap second %q cannot be represented", e.Value)
+}
+
+// expansions lists the directives standing for a combination of others.
+var expansions = map[string]string{
+	"%x": "%m/%d/%y",
+	"%X": "%H:%M:%S",
+	"%T": "%H:%M:%S",
+	"%F": "%Y-%m-%d",
+	"%D": "%m/%d/%y",
+	"%R": "%H:%M",
+}
+
+type fields struct {
+	year, month, day, yday   int
+	hour, minute, second     int
+	nsec                     int
+	pm, hasPM, hour12        bool
+	loc                      *time.Location
+	unix                     int64
+	hasUnix, hasYDay, hasDay bool
+}
+
+// Strptime parses a value written with a strftime format, the reverse of
+// Strftime. Fields missing from the format default to January 1st of year 0,
+// at midnight UTC, like time.Parse does. A 60th second is reported with a
+// LeapSecondError.
+func Strptime(value string, format string) (time.Time, error) {
+	f := fields{month: 1, day: 1, loc: time.UTC}
+
+	rest, err := f.parse(value, format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if rest != "" {
+		return time.Time{}, fmt.Errorf("%w %q as %q: extra text %q", ErrParse, value, format, rest)
+	}
+
+	if f.hasUnix {
+		return time.Unix(f.unix, 0).In(f.loc), nil
+	}
+
+	if f.hour12 {
+		// like strptime(3), AM/PM only applies to the 12-hour clock
+		if f.hour < 1 || f.hour > 12 {
+			return time.Time{}, fmt.Errorf("%w %q as %q: hour out of range", ErrParse, value, format)
+		}
+		f.hour %= 12
+		if f.hasPM && f.pm {
+			f.hour += 12
+		}
+	}
+
+	if f.hasYDay && !f.hasDay {
+		if f.yday < 1 || f.yday > daysIn(f.year, 0) {
+			return time.Time{}, fmt.Errorf("%w %q as %q: day of year out of range", ErrParse, value, format)
+		}
+		d := time.Date(f.year, 1, f.yday, 0, 0, 0, 0, time.UTC)
+		f.month, f.day = int(d.Month()), d.Day()
+	}
+
+	switch {
+	case f.month < 1 || f.month > 12:
+		return time.Time{}, fmt.Errorf("%w %q as %q: month out of range", ErrParse, value, format)
+	case f.day < 1 || f.day > daysIn(f.year, time.Month(f.month)):
+		return time.Time{}, fmt.Errorf("%w %q as %q: day out of range", ErrParse, value, format)
+	case f.hour > 23:
+		return time.Time{}, fmt.Errorf("%w %q as %q: hour out of range", ErrParse, value, format)
+	case f.minute > 59:
+		return time.Time{}, fmt.Errorf("%w %q as %q: minute out of range", ErrParse, value, format)
+	case f.second > 60:
+		return time.Time{}, fmt.Errorf("%w %q as %q: second out of range", ErrParse, value, format)
+	}
+
+	if f.second == 60 {
+		t := time.Date(f.year, time.Month(f.month), f.day, f.hour, f.minute, 59, f.nsec, f.loc)
+		return time.Time{}, &LeapSecondError{Value: value, Time: t.Add(time.Second)}
+	}
+
+	return time.Date(f.year, time.Month(f.month), f.day, f.hour, f.minute, f.second, f.nsec, f.loc), nil
+}
+
+// daysIn returns the number of days of the month, or of the year when month is 0.
+func daysIn(year int, month time.Month) int {
+	if month == 0 {
+		return time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	}
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// parse consumes value according to format and returns the unparsed rest.
+func (f *fields) parse(value string, format string) (string, error) {
+	for _, piece := range StrTimeTokens(format) {
+		if expanded, ok := expansions[piece]; ok {
+			var err error
+			if value, err = f.parse(value, expanded); err != nil {
+				return value, err
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(piece, "%") || piece == "%%" {
+			literal := piece
+			if piece == "%%" {
+				literal = "%"
+			}
+			rest, ok := matchLiteral(value, literal)
+			if !ok {
+				return value, fmt.Errorf("%w %q as %q: expected %q", ErrParse, value, format, literal)
+			}
+			value = rest
+			continue
+		}
+
+		var err error
+		if value, err = f.directive(value, piece); err != nil {
+			return value, fmt.Errorf("%w %q as %q: %s", ErrParse, value, format, err)
+		}
+	}
+
+	return value, nil
+}
+
+// matchLiteral consumes literal from value. Whitespace in the literal matches
+// any run of whitespace.
+func matchLiteral(value string, literal string) (string, bool) {
+	for _, r := range literal {
+		if unicode.IsSpace(r) {
+			trimmed := strings.TrimLeftFunc(value, unicode.IsSpace)
+			if trimmed == value {
+				return value, false
+			}
+			value = trimmed
+			continue
+		}
+		if !strings.HasPrefix(value, string(r)) {
+			return value, false
+		}
+		value = value[len(string(r)):]
+	}
+
+	return value, true
+}
+
+func (f *fields) directive(value string, piece string) (string, error) {
+	var err error
+	switch strings.Replace(piece, "-", "", 1) {
+	case "%Y":
+		sign := 1
+		if strings.HasPrefix(value, "-") {
+			sign, value = -1, value[1:]
+		}
+		f.year, value, err = number(value, 1, 4)
+		f.year *= sign
+	case "%y":
+		f.year, value, err = number(value, 2, 2)
+		// POSIX: 69-99 are 1969-1999, 00-68 are 2000-2068
+		if f.year < 69 {
+			f.year += 2000
+		} else {
+			f.year += 1900
+		}
+	case "%m":
+		f.month, value, err = number(value, 1, 2)
+	case "%d", "%e":
+		value = strings.TrimLeft(value, " ")
+		f.day, value, err = number(value, 1, 2)
+		f.hasDay = true
+	case "%j":
+		f.yday, value, err = number(value, 1, 3)
+		f.hasYDay = true
+	case "%H":
+		f.hour, value, err = number(value, 1, 2)
+	case "%I":
+		f.hour, value, err = number(value, 1, 2)
+		f.hour12 = true
+	case "%M":
+		f.minute, value, err = number(value, 1, 2)
+	case "%S":
+		f.second, value, err = number(value, 1, 2)
+	case "%f":
+		digits := 0
+		for digits < len(value) && digits < 9 && value[digits] >= '0' && value[digits] <= '9' {
+			digits++
+		}
+		if digits == 0 {
+			return value, errors.New("expected fraction of second")
+		}
+		f.nsec, _ = strconv.Atoi(value[:digits] + strings.Repeat("0", 9-digits))
+		value = value[digits:]
+	case "%p":
+		switch {
+		case len(value) >= 2 && strings.EqualFold(value[:2], "AM"):
+			f.pm = false
+		case len(value) >= 2 && strings.EqualFold(value[:2], "PM"):
+			f.pm = true
+		default:
+			return value, errors.New("expected AM or PM")
+		}
+		f.hasPM = true
+		value = value[2:]
+	case "%b", "%B":
+		var month int
+		if month, value, err = name(value, func(i int) string { return time.Month(i + 1).String() }, 12); err == nil {
+			f.month = month + 1
+		}
+	case "%a", "%A":
+		_, value, err = name(value, func(i int) string { return time.Weekday(i).String() }, 7)
+	case "%w":
+		_, value, err = number(value, 1, 1)
+	case "%U", "%W":
+		_, value, err = number(value, 1, 2)
+	case "%z":
+		value, err = f.offset(value)
+	case "%Z":
+		end := 0
+		for end < len(value) && (unicode.IsLetter(rune(value[end])) || (end > 0 && strings.ContainsRune("+-0123456789:", rune(value[end])))) {
+			end++
+		}
+		if end == 0 {
+			return value, errors.New("expected time zone name")
+		}
+		switch zone := value[:end]; zone {
+		case "UTC", "GMT", "Z":
+			f.loc = time.UTC
+		default:
+			if f.loc == time.UTC {
+				f.loc = time.FixedZone(zone, 0)
+			}
+		}
+		value = value[end:]
+	case "%s":
+		end := 0
+		if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+") {
+			end++
+		}
+		for end < len(value) && value[end] >= '0' && value[end] <= '9' {
+			end++
+		}
+		if f.unix, err = strconv.ParseInt(value[:end], 10, 64); err != nil {
+			return value, errors.New("expected unix timestamp")
+		}
+		f.hasUnix = true
+		value = value[end:]
+	default:
+		return value, fmt.Errorf("unsupported directive %s", piece)
+	}
+
+	return value, err
+}
+
+// number consumes a decimal number of minDigits to maxDigits digits.
+func number(value string, minDigits int, maxDigits int) (int, string, error) {
+	n := 0
+	for n < len(value) && n < maxDigits && value[n] >= '0' && value[n] <= '9' {
+		n++
+	}
+	if n < minDigits {
+		return 0, value, errors.New("expected number")
+	}
+
+	v, _ := strconv.Atoi(value[:n])
+	return v, value[n:], nil
+}
+
+// name consumes one of count names, full or abbreviated to three letters,
+// ignoring case, and returns its index.
+func name(value string, names func(int) string, count int) (int, string, error) {
+	for i := 0; i < count; i++ {
+		full := names(i)
+		if len(value) >= len(full) && strings.EqualFold(value[:len(full)], full) {
+			return i, value[len(full):], nil
+		}
+	}
+	for i := 0; i < count; i++ {
+		short := names(i)[:3]
+		if len(value) >= 3 && strings.EqualFold(value[:3], short) {
+			return i, value[3:], nil
+		}
+	}
+
+	return 0, value, errors.New("expected name")
+}
+
+// offset consumes a UTC offset: Z, +HH, +HHMM or +HH:MM.
+func (f *fields) offset(value string) (string, error) {
+	if strings.HasPrefix(value, "Z") {
+		f.loc = time.UTC
+		return value[1:], nil
+	}
+	if value == "" || (value[0] != '+' && value[0] != '-') {
+		return value, errors.New("expected UTC offset")
+	}
+
+	sign := 1
+	if value[0] == '-' {
+		sign = -1
+	}
+	hours, rest, err := number(value[1:], 2, 2)
+	if err != nil {
+		return value, errors.New("expected UTC offset")
+	}
+	rest = strings.TrimPrefix(rest, ":")
+	minutes, rest, err := number(rest, 2, 2)
+	if err != nil {
+		minutes = 0
+	}
+	if hours > 23 || minutes > 59 {
+		return value, errors.New("UTC offset out of range")
+	}
+
+	f.loc = time.FixedZone("", sign*(hours*3600+minutes*60))
+	return rest, nil
+}
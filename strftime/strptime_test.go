@@ -0,0 +1,91 @@
+package strftime
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestStrptime(t *testing.T) {
+	jst := time.FixedZone("", 9*60*60)
+
+	tests := []struct {
+		format   string
+		value    string
+		expected time.Time
+	}{
+		{"%Y-%m-%d %H:%M:%S", "2017-01-02 03:04:05", time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"%Y-%m-%d", "2017-1-2", time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"%F %T", "2017-01-02 03:04:05", time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"%d %b %Y", "02 jan 2017", time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"%A, %B %-d %Y", "Monday, January 2 2017", time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"%I:%M%p", "03:04PM", time.Date(0, 1, 1, 15, 4, 0, 0, time.UTC)},
+		{"%I:%M %p", "12:04 am", time.Date(0, 1, 1, 0, 4, 0, 0, time.UTC)},
+		{"%y/%m/%d", "69/01/02", time.Date(1969, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"%y/%m/%d", "17/01/02", time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"%Y %j", "2016 366", time.Date(2016, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"%H:%M:%S.%f", "03:04:05.678", time.Date(0, 1, 1, 3, 4, 5, 678000000, time.UTC)},
+		{"%Y-%m-%dT%H:%M:%S%z", "2017-01-02T03:04:05+0900", time.Date(2017, 1, 2, 3, 4, 5, 0, jst)},
+		{"%Y-%m-%dT%H:%M:%S%z", "2017-01-02T03:04:05+09:00", time.Date(2017, 1, 2, 3, 4, 5, 0, jst)},
+		{"%Y-%m-%dT%H:%M:%S%z", "2017-01-02T03:04:05Z", time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"%H:%M %Z", "03:04 UTC", time.Date(0, 1, 1, 3, 4, 0, 0, time.UTC)},
+		{"%s", "1483228800", time.Unix(1483228800, 0)},
+		{"100%% at %H", "100% at 03", time.Date(0, 1, 1, 3, 0, 0, 0, time.UTC)},
+		{"%Y %m %d", "2017  01\t02", time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		actual, err := Strptime(test.value, test.format)
+		require.NoErrorf(t, err, "parsing %q as %q", test.value, test.format)
+		assert.Truef(t, test.expected.Equal(actual), "parsing %q as %q: expected %s, got %s", test.value, test.format, test.expected, actual)
+	}
+}
+
+func TestStrptimeRoundTrip(t *testing.T) {
+	ref := time.Date(2023, 4, 5, 17, 50, 44, 0, time.FixedZone("", -3*60*60))
+
+	for _, format := range []string{"%Y-%m-%d %H:%M:%S %z", "%a %d %B %Y %I:%M:%S %p %z", "%x %X %z", "%Y %j %H:%M:%S%z"} {
+		actual, err := Strptime(Strftime(ref, format), format)
+		require.NoErrorf(t, err, "parsing with %q", format)
+		assert.Truef(t, ref.Equal(actual), "round trip with %q: got %s", format, actual)
+	}
+}
+
+func TestStrptimeErrors(t *testing.T) {
+	tests := []struct {
+		format string
+		value  string
+	}{
+		{"%Y-%m-%d", "2017-13-02"},
+		{"%Y-%m-%d", "2017-02-29"},
+		{"%Y-%m-%d", "2017-01"},
+		{"%Y-%m-%d", "2017-01-02 extra"},
+		{"%H:%M:%S", "24:00:00"},
+		{"%H:%M:%S", "23:60:00"},
+		{"%H:%M:%S", "23:59:61"},
+		{"%I %p", "13 PM"},
+		{"%b", "Foo"},
+		{"%z", "0900"},
+		{"%Q", "x"},
+	}
+
+	for _, test := range tests {
+		_, err := Strptime(test.value, test.format)
+		assert.ErrorIsf(t, err, ErrParse, "parsing %q as %q", test.value, test.format)
+	}
+}
+
+func TestStrptimeLeapSecond(t *testing.T) {
+	_, err := Strptime("2016-12-31 23:59:60", "%Y-%m-%d %H:%M:%S")
+
+	var leapErr *LeapSecondError
+	require.True(t, errors.As(err, &leapErr))
+	assert.Equal(t, "2016-12-31 23:59:60", leapErr.Value)
+	assert.Equal(t, time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), leapErr.Time)
+
+	_, err = Strptime("2016-12-31 23:59:60.5", "%Y-%m-%d %H:%M:%S.%f")
+	require.True(t, errors.As(err, &leapErr))
+	assert.Equal(t, time.Date(2017, 1, 1, 0, 0, 0, 500000000, time.UTC), leapErr.Time)
+}
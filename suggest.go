@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// suggest returns the candidate closest to value, if it is close enough to be
+// a plausible typo. A candidate starting with value is always a match.
+func suggest(value string, candidates []string) (string, bool) {
+	if value == "" {
+		return "", false
+	}
+
+	lower := strings.ToLower(value)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), lower) && candidate != value {
+			return candidate, true
+		}
+	}
+
+	maxDistance := (utf8.RuneCountInString(value) + 2) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+	if maxDistance > 3 {
+		maxDistance = 3
+	}
+
+	best := ""
+	bestDistance := maxDistance + 1
+	for _, candidate := range candidates {
+		d := levenshtein(lower, strings.ToLower(candidate))
+		if d < bestDistance {
+			best = candidate
+			bestDistance = d
+		}
+	}
+
+	if best == "" || best == value {
+		return "", false
+	}
+
+	return best, true
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"generate", "help", "parse"}
+
+	tests := []struct {
+		value    string
+		expected string
+		found    bool
+	}{
+		{"gen", "generate", true},
+		{"parze", "parse", true},
+		{"hlep", "help", true},
+		{"PARSE", "parse", true},
+		{"parse", "", false},
+		{"zzzzzz", "", false},
+		{"", "", false},
+	}
+
+	for _, test := range tests {
+		s, ok := suggest(test.value, candidates)
+		assert.Equalf(t, test.found, ok, "suggesting for %q", test.value)
+		assert.Equalf(t, test.expected, s, "suggesting for %q", test.value)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("abc", "abc"))
+	assert.Equal(t, 3, levenshtein("", "abc"))
+	assert.Equal(t, 1, levenshtein("Tokio", "Tokyo"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	outputPlain = "plain"
+	outputTable = "table"
+)
+
+var outputNames = []string{outputPlain, outputTable}
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiYellow  = "\x1b[33m"
+	ansiMagenta = "\x1b[35m"
+)
+
+// tableRow is a timestamp shown by --output table, the text it comes from,
+// if any, and the name of its zone, the name of its location when empty, or
+// of the local zone. The
+// timestamps of buckets and intervals start a range, ending at end, and
+// buckets have an index.
+type tableRow struct {
+	input string
+	zone  string
+	t     time.Time
+	end   time.Time
+	index string
+}
+
+// tableStyle is how a table is rendered: at most width columns wide, 0 for
+// no limit, and with colours or not.
+type tableStyle struct {
+	width int
+	color bool
+}
+
+// tableColumns are the columns of a table, with the order in which they are
+// dropped when it is too wide: lower first, 0 never.
+var tableColumns = []struct {
+	header string
+	drop   int
+}{
+	{"INPUT", 3},
+	{"EPOCH", 0},
+	{"LOCAL", 0},
+	{"END", 0},
+	{"INDEX", 0},
+	{"UTC", 2},
+	{"RELATIVE", 1},
+	{"ZONE", 5},
+	{"OFFSET", 4},
+}
+
+// newTableStyle returns the style of tables written to w: as wide as the
+// terminal, $COLUMNS if set, and coloured on terminals unless NO_COLOR is set.
+func newTableStyle(w io.Writer, o Options) (tableStyle, error) {
+	var style tableStyle
+	f, ok := w.(*os.File)
+	terminal := ok && isTerminal(f)
+
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		style.width = columns
+	} else if terminal {
+		style.width, _ = terminalWidth(f)
+	}
+
+	switch o.color {
+	case "", "auto":
+		style.color = terminal && os.Getenv("NO_COLOR") == ""
+	case "always":
+		style.color = true
+	case "never":
+	default:
+		return style, unknownValueError(exitUsage, "unknown --color", o.color, []string{"auto", "always", "never"})
+	}
+
+	return style, nil
+}
+
+// tableOutput reports whether timestamps are printed as a table.
+func tableOutput(o Options) (bool, error) {
+	output, err := o.Output()
+	return output == outputTable, err
+}
+
+// printTable writes the rows as a table in the style of w.
+func printTable(w io.Writer, rows []tableRow, o Options, now time.Time) error {
+	style, err := newTableStyle(w, o)
+	if err != nil {
+		return err
+	}
+	return writeTable(w, rows, o, now, style)
+}
+
+// localZoneName returns the name of the local zone, from $TZ or the link of
+// /etc/localtime, or "" when it has none.
+func localZoneName() string {
+	name, ok := os.LookupEnv("TZ")
+	if name = strings.TrimPrefix(name, ":"); !ok {
+		name, _ = os.Readlink("/etc/localtime")
+	} else if !strings.HasPrefix(name, "/") {
+		return name
+	}
+
+	// a file of the zoneinfo database, like /usr/share/zoneinfo/Europe/Paris
+	if _, name, ok = strings.Cut(name, "zoneinfo/"); ok {
+		return name
+	}
+	return ""
+}
+
+// tableColors returns the colours of the local time and the offset of t:
+// weekends in yellow, times out of business hours dimmed, and offsets in
+// daylight saving time in magenta.
+func tableColors(t time.Time) (string, string) {
+	var local, offset string
+	switch {
+	case t.Weekday() == time.Saturday || t.Weekday() == time.Sunday:
+		local = ansiYellow
+	case t.Hour() < 9 || t.Hour() >= 17:
+		local = ansiDim
+	}
+	if t.IsDST() {
+		offset = ansiMagenta
+	}
+
+	return local, offset
+}
+
+// writeTable writes the rows as aligned columns: the input, the epoch, the
+// time in its zone, the end and index of ranges, the time in UTC, the time
+// from now, the zone and its offset.
+// Columns without any value, like the input of generated times, are left out.
+func writeTable(w io.Writer, rows []tableRow, o Options, now time.Time, style tableStyle) error {
+	epoch, precision, err := o.EpochPrecision()
+	if err != nil {
+		return err
+	}
+	format, _ := o.Format()
+	if format == "" {
+		format = "2006-01-02 15:04:05"
+	}
+
+	cells := [][]string{make([]string, len(tableColumns))}
+	colors := [][]string{make([]string, len(tableColumns))}
+	for i, c := range tableColumns {
+		cells[0][i] = c.header
+		colors[0][i] = ansiBold
+	}
+	for _, r := range rows {
+		zone := r.zone
+		if zone == "" {
+			zone = r.t.Location().String()
+		}
+		if zone == "Local" {
+			zone = localZoneName()
+		}
+		name, _ := r.t.Zone()
+		if strings.HasPrefix(name, "(") {
+			name = "" // fixed offsets are named after their value, like (+530)
+		}
+		var end string
+		if !r.end.IsZero() {
+			end = ut.Format(r.end, format)
+		}
+		cells = append(cells, []string{
+			r.input,
+			epoch.Format(r.t, precision),
+			ut.Format(r.t, format),
+			end,
+			r.index,
+			ut.Format(r.t.UTC(), format),
+			relative(r.t, now),
+			zone,
+			strings.TrimSpace(r.t.Format("-07:00") + " " + name),
+		})
+		local, offset := tableColors(r.t)
+		colors = append(colors, []string{"", "", local, "", "", "", "", "", offset})
+	}
+
+	widths := make([]int, len(tableColumns))
+	for _, row := range cells {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	// drop empty columns, then others until the table fits
+	shown := make([]bool, len(tableColumns))
+	for i := range shown {
+		shown[i] = len(rows) == 0
+		for _, row := range cells[1:] {
+			shown[i] = shown[i] || row[i] != ""
+		}
+	}
+	for drop := 1; style.width > 0; drop++ {
+		total := -2
+		for i, width := range widths {
+			if shown[i] {
+				total += width + 2
+			}
+		}
+		if total <= style.width {
+			break
+		}
+
+		dropped := false
+		for i, c := range tableColumns {
+			if c.drop == drop {
+				shown[i], dropped = false, true
+			}
+		}
+		if !dropped {
+			break
+		}
+	}
+
+	for r, row := range cells {
+		var line strings.Builder
+		last := len(row) - 1
+		for last > 0 && !shown[last] {
+			last--
+		}
+		for i, cell := range row {
+			if !shown[i] {
+				continue
+			}
+			text := cell
+			if style.color && colors[r][i] != "" {
+				text = colors[r][i] + cell + ansiReset
+			}
+			line.WriteString(text)
+			if i < last {
+				line.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2))
+			}
+		}
+		if _, err := fmt.Fprintln(w, line.String()); err != nil {
+			return ioError(err)
+		}
+	}
+
+	return nil
+}
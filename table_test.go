@@ -0,0 +1,175 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteTable(t *testing.T) {
+	now := time.Date(2023, 4, 5, 20, 50, 44, 0, time.UTC)
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	rows := []tableRow{
+		{input: "1680717044", t: time.Unix(1680717044, 0).In(paris)},
+		{input: "0", t: time.Unix(0, 0).UTC()},
+	}
+
+	tests := []struct {
+		name  string
+		style tableStyle
+		want  string
+	}{
+		{"all columns", tableStyle{}, "" +
+			"INPUT       EPOCH       LOCAL                UTC                  RELATIVE      ZONE          OFFSET\n" +
+			"1680717044  1680717044  2023-04-05 19:50:44  2023-04-05 17:50:44  3 hours ago   Europe/Paris  +02:00 CEST\n" +
+			"0           0           1970-01-01 00:00:00  1970-01-01 00:00:00  53 years ago  UTC           +00:00 UTC\n"},
+		{"narrow", tableStyle{width: 60}, "" +
+			"EPOCH       LOCAL                ZONE          OFFSET\n" +
+			"1680717044  2023-04-05 19:50:44  Europe/Paris  +02:00 CEST\n" +
+			"0           1970-01-01 00:00:00  UTC           +00:00 UTC\n"},
+		{"too narrow", tableStyle{width: 10}, "" +
+			"EPOCH       LOCAL\n" +
+			"1680717044  2023-04-05 19:50:44\n" +
+			"0           1970-01-01 00:00:00\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			require.NoError(t, writeTable(&buf, rows, Options{format: "sql"}, now, tt.style))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	var buf strings.Builder
+	require.NoError(t, writeTable(&buf, []tableRow{{t: time.Unix(0, 0).UTC()}}, Options{format: "sql"}, now, tableStyle{}))
+	assert.Equal(t, ""+
+		"EPOCH  LOCAL                UTC                  RELATIVE      ZONE  OFFSET\n"+
+		"0      1970-01-01 00:00:00  1970-01-01 00:00:00  53 years ago  UTC   +00:00 UTC\n", buf.String(), "rows without an input hide the column")
+}
+
+func TestLocalZoneName(t *testing.T) {
+	for value, want := range map[string]string{
+		"Europe/Paris":                        "Europe/Paris",
+		":Asia/Tokyo":                         "Asia/Tokyo",
+		"/usr/share/zoneinfo/America/Toronto": "America/Toronto",
+		"/etc/custom-zone":                    "",
+	} {
+		t.Setenv("TZ", value)
+		assert.Equal(t, want, localZoneName(), value)
+	}
+
+	now := time.Date(2023, 4, 5, 20, 50, 44, 0, time.UTC)
+	rows := []tableRow{{t: time.Unix(0, 0).In(time.FixedZone("Local", 0))}}
+	var buf strings.Builder
+	require.NoError(t, writeTable(&buf, rows, Options{format: "sql"}, now, tableStyle{width: 60}))
+	assert.Equal(t, ""+
+		"EPOCH  LOCAL                OFFSET\n"+
+		"0      1970-01-01 00:00:00  +00:00 Local\n", buf.String(), "the zone column is dropped when unknown")
+
+	t.Setenv("TZ", "Europe/London")
+	buf.Reset()
+	require.NoError(t, writeTable(&buf, rows, Options{format: "sql"}, now, tableStyle{width: 60}))
+	assert.Contains(t, buf.String(), "  Europe/London  ")
+}
+
+func TestWriteTableColor(t *testing.T) {
+	now := time.Date(2023, 4, 5, 20, 50, 44, 0, time.UTC)
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	rows := []tableRow{
+		{input: "office", t: time.Date(2023, 4, 5, 10, 0, 0, 0, paris)},
+		{input: "night", t: time.Date(2023, 1, 4, 22, 0, 0, 0, paris)},
+		{input: "weekend", t: time.Date(2023, 1, 7, 10, 0, 0, 0, paris)},
+	}
+
+	var buf strings.Builder
+	require.NoError(t, writeTable(&buf, rows, Options{format: "sql"}, now, tableStyle{width: 60, color: true}))
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 4)
+
+	assert.Equal(t, ansiBold+"EPOCH"+ansiReset+"       "+ansiBold+"LOCAL"+ansiReset, lines[0][:len(ansiBold+"EPOCH"+ansiReset+"       "+ansiBold+"LOCAL"+ansiReset)])
+	assert.Contains(t, lines[1], "  2023-04-05 10:00:00  Europe/Paris  "+ansiMagenta+"+02:00 CEST"+ansiReset)
+	assert.Contains(t, lines[2], ansiDim+"2023-01-04 22:00:00"+ansiReset+"  Europe/Paris  +01:00 CET")
+	assert.Contains(t, lines[3], ansiYellow+"2023-01-07 10:00:00"+ansiReset+"  Europe/Paris  +01:00 CET")
+	assert.Equal(t, strings.Index(lines[1], "2023"), strings.Index(lines[3], ansiYellow), "colours do not shift columns")
+}
+
+func TestNewTableStyle(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+	t.Setenv("NO_COLOR", "")
+
+	var buf strings.Builder
+	style, err := newTableStyle(&buf, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, tableStyle{}, style, "no width and no colour when not writing to a terminal")
+
+	style, err = newTableStyle(&buf, Options{color: "always"})
+	require.NoError(t, err)
+	assert.True(t, style.color)
+
+	t.Setenv("COLUMNS", "72")
+	t.Setenv("NO_COLOR", "1")
+	style, err = newTableStyle(&buf, Options{color: "never"})
+	require.NoError(t, err)
+	assert.Equal(t, tableStyle{width: 72}, style)
+
+	_, err = newTableStyle(&buf, Options{color: "sometimes"})
+	code, _ := describeError(err)
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestOutputTable(t *testing.T) {
+	t.Setenv("COLUMNS", "60")
+	options := Options{utc: true, format: "sql", output: outputTable}
+
+	var buf strings.Builder
+	require.NoError(t, parse(&buf, strings.NewReader("1680717044\n0\n"), ParseOptions{options: options}, nil))
+	assert.Equal(t, ""+
+		"EPOCH       LOCAL                ZONE  OFFSET\n"+
+		"1680717044  2023-04-05 17:50:44  UTC   +00:00 UTC\n"+
+		"0           1970-01-01 00:00:00  UTC   +00:00 UTC\n", buf.String())
+
+	buf.Reset()
+	o := GenerateOptions{options: options, base: "2023-04-05 17:50:44", zones: []string{"Asia/Tokyo", "+05:30"}}
+	require.NoError(t, generate(&buf, o))
+	assert.Equal(t, ""+
+		"EPOCH       LOCAL                ZONE        OFFSET\n"+
+		"1680717044  2023-04-05 17:50:44  UTC         +00:00 UTC\n"+
+		"1680717044  2023-04-06 02:50:44  Asia/Tokyo  +09:00 JST\n"+
+		"1680717044  2023-04-05 23:20:44  +05:30      +05:30\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, cron(&buf, CronOptions{options: options, base: "2023-04-05 17:50:44", count: 2}, []string{"0 9 * * 1-5"}, time.Now()))
+	assert.Equal(t, ""+
+		"EPOCH       LOCAL                ZONE  OFFSET\n"+
+		"1680771600  2023-04-06 09:00:00  UTC   +00:00 UTC\n"+
+		"1680858000  2023-04-07 09:00:00  UTC   +00:00 UTC\n", buf.String())
+
+	t.Setenv("COLUMNS", "100")
+	buf.Reset()
+	require.NoError(t, printInterval(&buf, IntervalOptions{options: options}, []string{"R2/2023-01-31 00:00:00/P1M"}, time.Now()))
+	assert.Equal(t, ""+
+		"EPOCH       LOCAL                END                  ZONE  OFFSET\n"+
+		"1675123200  2023-01-31 00:00:00  2023-02-28 00:00:00  UTC   +00:00 UTC\n"+
+		"1677542400  2023-02-28 00:00:00  2023-03-31 00:00:00  UTC   +00:00 UTC\n", buf.String())
+
+	t.Setenv("COLUMNS", "60")
+	buf.Reset()
+	require.NoError(t, bucket(&buf, nil, BucketOptions{options: options, size: "day"}, []string{"1680717044", "0"}))
+	assert.Equal(t, ""+
+		"EPOCH       LOCAL                END                  INDEX\n"+
+		"1680652800  2023-04-05 00:00:00  2023-04-06 00:00:00  19452\n"+
+		"0           1970-01-01 00:00:00  1970-01-02 00:00:00  0\n", buf.String())
+
+	err := parse(&buf, nil, ParseOptions{options: options, null: true}, []string{"0"})
+	code, _ := describeError(err)
+	assert.Equal(t, exitUsage, code)
+
+	_, err = (&Options{output: "tabel"}).Output()
+	code, msg := describeError(err)
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, msg, `did you mean "table"?`)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
+	"text/template"
+	"time"
+)
+
+// templateEpoch is a unix epoch at each precision.
+type templateEpoch struct {
+	S, Ms, Us, Ns string
+}
+
+// templateTime is a time whose Format method takes any layout --format
+// does: a Go layout, a strftime format or a preset.
+type templateTime struct {
+	time.Time
+}
+
+func (t templateTime) Format(layout string) string {
+	return ut.Format(t.Time, layout)
+}
+
+func (t templateTime) String() string {
+	return ut.Format(t.Time, "")
+}
+
+// templateData is what --template renders, for each timestamp.
+type templateData struct {
+	Input    string // the value as given, if any
+	Epoch    templateEpoch
+	Time     templateTime // in the configured zone
+	Local    templateTime // in the local zone
+	UTC      templateTime
+	Zone     string // abbreviation of the configured zone, like CEST
+	Offset   string // offset of the configured zone, like +02:00
+	ISOWeek  string // ISO 8601 week date, like 2023-W14-3
+	Relative string // from now, like "3 hours ago"
+}
+
+func newTemplateData(input string, t time.Time, now time.Time) templateData {
+	zone, _ := t.Zone()
+	week, _ := ut.CalendarISOWeek.Format(t)
+
+	return templateData{
+		Input: input,
+		Epoch: templateEpoch{
+			S:  ut.EpochUnix.Format(t, ut.Second),
+			Ms: ut.EpochUnix.Format(t, ut.Millisecond),
+			Us: ut.EpochUnix.Format(t, ut.Microsecond),
+			Ns: ut.EpochUnix.Format(t, ut.Nanosecond),
+		},
+		Time:     templateTime{t},
+		Local:    templateTime{t.Local()},
+		UTC:      templateTime{t.UTC()},
+		Zone:     zone,
+		Offset:   t.Format("-07:00"),
+		ISOWeek:  week,
+		Relative: relative(t, now),
+	}
+}
+
+// relative describes t from now in its largest unit, like "in 2 days".
+// Years and months are calendar ones; the other units count whole seconds,
+// as a time.Duration cannot hold more than 292 years.
+func relative(t time.Time, now time.Time) string {
+	from, to := now.UTC(), t.UTC()
+	future := to.After(from)
+	if !future {
+		from, to = to, from
+	}
+
+	amount := func(n int64, unit string) string {
+		s := fmt.Sprintf("%d %s", n, unit)
+		if n > 1 {
+			s += "s"
+		}
+		if future {
+			return "in " + s
+		}
+		return s + " ago"
+	}
+
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	if months > 0 && from.AddDate(0, months, 0).After(to) {
+		months--
+	}
+	if months >= 12 {
+		return amount(int64(months/12), "year")
+	}
+	if months > 0 {
+		return amount(int64(months), "month")
+	}
+
+	seconds := to.Unix() - from.Unix()
+	if to.Nanosecond() < from.Nanosecond() {
+		seconds--
+	}
+	units := []struct {
+		name string
+		size int64 // seconds
+	}{
+		{"week", 7 * 86400},
+		{"day", 86400},
+		{"hour", 3600},
+		{"minute", 60},
+		{"second", 1},
+	}
+	for _, u := range units {
+		if n := seconds / u.size; n > 0 {
+			return amount(n, u.name)
+		}
+	}
+
+	return "now"
+}
+
+// renderTemplate renders the template for t, without a trailing newline.
+func renderTemplate(tmpl *template.Template, input string, t time.Time, now time.Time) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, newTemplateData(input, t, now)); err != nil {
+		return "", usageError("%s", err)
+	}
+	return buf.String(), nil
+}
+
+// writeTemplate writes the template rendered for t, followed by a newline.
+func writeTemplate(w io.Writer, tmpl *template.Template, input string, t time.Time, now time.Time) error {
+	out, err := renderTemplate(tmpl, input, t, now)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, out)
+	return ioError(err)
+}
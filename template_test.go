@@ -0,0 +1,85 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRelative(t *testing.T) {
+	now := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{now, "now"},
+		{now.Add(500 * time.Millisecond), "now"},
+		{now.Add(-time.Second), "1 second ago"},
+		{now.Add(-3 * time.Hour), "3 hours ago"},
+		{now.Add(49 * time.Hour), "in 2 days"},
+		{now.AddDate(0, 0, 14), "in 2 weeks"},
+		{now.AddDate(-3, 0, 0), "3 years ago"},
+		{now.Add(-1500 * time.Millisecond), "1 second ago"},
+		{time.Unix(-62135596800, 0), "2022 years ago"},
+		{time.Unix(99999999999, 0), "in 3115 years"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, relative(tt.t, now), tt.t)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	now := time.Date(2023, 4, 5, 20, 50, 45, 0, time.UTC)
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	tm := time.Date(2023, 4, 5, 19, 50, 44, 123000000, paris)
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{`{{.Epoch.S}} {{.Epoch.Ms}} {{.Epoch.Us}} {{.Epoch.Ns}}`, "1680717044 1680717044123 1680717044123000 1680717044123000000"},
+		{`{{.UTC.Format "rfc3339"}} {{.Relative}}`, "2023-04-05T17:50:44Z 3 hours ago"},
+		{`{{.Time.Format "%H:%M"}} {{.Zone}} {{.Offset}} {{.ISOWeek}}`, "19:50 CEST +02:00 2023-W14-3"},
+		{`{{.Input}}: {{.Time}}`, "1680717044123: 2023-04-05 19:50:44.123 +0200 CEST"},
+		{`{{.UTC.Year}}-{{printf "%02d" .UTC.Month}}`, "2023-04"},
+	}
+	for _, tt := range tests {
+		tmpl, ok, err := (&Options{template: tt.template}).Template()
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		got, err := renderTemplate(tmpl, "1680717044123", tm, now)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, tt.template)
+	}
+
+	_, _, err = (&Options{template: "{{.Epoch"}).Template()
+	code, _ := describeError(err)
+	assert.Equal(t, exitUsage, code)
+
+	tmpl, _, err := (&Options{template: "{{.Nope}}"}).Template()
+	require.NoError(t, err)
+	_, err = renderTemplate(tmpl, "", tm, now)
+	code, _ = describeError(err)
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestTemplateOutput(t *testing.T) {
+	options := Options{utc: true, template: `{{.Epoch.Ms}} {{.Time.Format "sql"}} {{.Input}}`}
+
+	var buf strings.Builder
+	require.NoError(t, parse(&buf, nil, ParseOptions{options: options}, []string{"1680717044"}))
+	assert.Equal(t, "1680717044000 2023-04-05 17:50:44 1680717044\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, generate(&buf, GenerateOptions{options: options, base: "2023-04-05T17:50:44Z", delta: []string{"1h"}}))
+	assert.Equal(t, "1680720644000 2023-04-05 18:50:44 2023-04-05T17:50:44Z\n", buf.String())
+
+	line, err := watchLine(GenerateOptions{options: options}, time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC), 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "1680717044000 2023-04-05 17:50:44 ", line)
+}
@@ -0,0 +1,16 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+func makeRaw(_ *os.File) (func(), error) {
+	return nil, errors.New("raw terminal mode not supported on this platform")
+}
+
+func terminalWidth(_ *os.File) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bufio"
+	"container/heap"
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var byteSizeMatch = regexp.MustCompile(`^(\d+)([KMG]?)B?$`)
+
+// timedLine is a line of text and its timestamp.
+type timedLine struct {
+	t    time.Time
+	line string
+}
+
+// eachLine calls fn with each non-blank line of the files named in args, "-"
+// being r, or of r without arguments. Errors are prefixed with the file name
+// and the line number.
+func eachLine(r io.Reader, args []string, fn func(string) error) error {
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+
+	for _, name := range args {
+		if err := eachFileLine(r, name, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// eachFileLine calls fn with each non-blank line of the named file, "-" being
+// r, closing the file before returning.
+func eachFileLine(r io.Reader, name string, fn func(string) error) error {
+	in, label := r, "stdin"
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return ioError(err)
+		}
+		defer f.Close()
+		in, label = f, name
+	}
+
+	reader := bufio.NewReader(in)
+	for n := 1; ; n++ {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return ioError(err)
+		}
+		if text := strings.TrimRight(line, "\r\n"); strings.TrimSpace(text) != "" {
+			if fnErr := fn(text); fnErr != nil {
+				return fmt.Errorf("%s:%d: %w", label, n, fnErr)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// eachTimedLine calls fn with each line and its timestamp, dropping lines
+// without one when asked to.
+func eachTimedLine(r io.Reader, args []string, lt *lineTime, fn func(timedLine) error) error {
+	return eachLine(r, args, func(line string) error {
+		t, err := lt.parse(line)
+		if err != nil {
+			if lt.fields.skipInvalid {
+				return nil
+			}
+			return err
+		}
+		return fn(timedLine{t: t, line: line})
+	})
+}
+
+// parseByteSize parses a size in bytes, optionally in K, M or G.
+func parseByteSize(value string) (int64, error) {
+	matches := byteSizeMatch.FindStringSubmatch(strings.ToUpper(value))
+	if len(matches) == 0 {
+		return 0, usageError("invalid size %q", value)
+	}
+
+	n, err := strconv.ParseInt(matches[1], 10, 64)
+	shift := map[string]uint{"": 0, "K": 10, "M": 20, "G": 30}[matches[2]]
+	if err != nil || n < 1 || n > int64(1<<62)>>shift {
+		return 0, usageError("invalid size %q", value)
+	}
+
+	return n << shift, nil
+}
+
+// sortLines prints lines in chronological order, keeping the input order of
+// lines with the same timestamp. Inputs larger than the buffer size are
+// sorted in chunks, written to temporary files and merged.
+func sortLines(w io.Writer, r io.Reader, o SortOptions, args []string) error {
+	lt, err := newLineTime(o.options, o.fields)
+	if err != nil {
+		return err
+	}
+	bufferSize, err := parseByteSize(o.bufferSize)
+	if err != nil {
+		return err
+	}
+
+	less := func(a, b time.Time) bool { return a.Before(b) }
+	if o.reverse {
+		less = func(a, b time.Time) bool { return a.After(b) }
+	}
+	sortChunk := func(lines []timedLine) {
+		sort.SliceStable(lines, func(i, j int) bool { return less(lines[i].t, lines[j].t) })
+	}
+
+	var chunk []timedLine
+	var chunkSize int64
+	var spilled []*os.File
+	defer func() {
+		for _, f := range spilled {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	err = eachTimedLine(r, args, lt, func(l timedLine) error {
+		chunk = append(chunk, l)
+		chunkSize += int64(len(l.line))
+		if chunkSize < bufferSize {
+			return nil
+		}
+
+		sortChunk(chunk)
+		f, err := spill(chunk)
+		if f != nil {
+			spilled = append(spilled, f)
+		}
+		chunk, chunkSize = chunk[:0], 0
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	sortChunk(chunk)
+	if len(spilled) == 0 {
+		out := bufio.NewWriter(w)
+		for _, l := range chunk {
+			if _, err := fmt.Fprintln(out, l.line); err != nil {
+				return ioError(err)
+			}
+		}
+		return ioError(out.Flush())
+	}
+
+	if len(chunk) > 0 {
+		f, err := spill(chunk)
+		if f != nil {
+			spilled = append(spilled, f)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return mergeChunks(w, spilled, less)
+}
+
+// spill writes a sorted chunk to a temporary file, one line per entry with
+// its timestamp in front: seconds, nanoseconds and the line, separated by
+// tabs. The file is returned even on failure, to be removed.
+func spill(chunk []timedLine) (*os.File, error) {
+	f, err := os.CreateTemp("", "ut-sort-")
+	if err != nil {
+		return nil, ioError(err)
+	}
+
+	out := bufio.NewWriter(f)
+	for _, l := range chunk {
+		if _, err := fmt.Fprintf(out, "%d\t%d\t%s\n", l.t.Unix(), l.t.Nanosecond(), l.line); err != nil {
+			return f, ioError(err)
+		}
+	}
+	if err := out.Flush(); err != nil {
+		return f, ioError(err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return f, ioError(err)
+	}
+
+	return f, nil
+}
+
+// chunkReader reads back a chunk written by spill.
+type chunkReader struct {
+	reader *bufio.Reader
+	index  int // position of the chunk in the input, to keep the sort stable
+	head   timedLine
+}
+
+// next reads the next line of the chunk, returning false at its end.
+func (c *chunkReader) next() (bool, error) {
+	line, err := c.reader.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return false, nil
+	}
+	if err != nil && err != io.EOF {
+		return false, ioError(err)
+	}
+
+	fields := strings.SplitN(strings.TrimSuffix(line, "\n"), "\t", 3)
+	if len(fields) != 3 {
+		return false, fmt.Errorf("corrupted temporary file: %q", line)
+	}
+	sec, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("corrupted temporary file: %q", line)
+	}
+	nsec, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("corrupted temporary file: %q", line)
+	}
+	c.head = timedLine{t: time.Unix(sec, nsec), line: fields[2]}
+
+	return true, nil
+}
+
+// chunkHeap orders chunks by their next line.
+type chunkHeap struct {
+	chunks []*chunkReader
+	less   func(a, b time.Time) bool
+}
+
+func (h *chunkHeap) Len() int { return len(h.chunks) }
+
+func (h *chunkHeap) Less(i, j int) bool {
+	a, b := h.chunks[i], h.chunks[j]
+	if a.head.t.Equal(b.head.t) {
+		return a.index < b.index
+	}
+	return h.less(a.head.t, b.head.t)
+}
+
+func (h *chunkHeap) Swap(i, j int) { h.chunks[i], h.chunks[j] = h.chunks[j], h.chunks[i] }
+
+func (h *chunkHeap) Push(x any) { h.chunks = append(h.chunks, x.(*chunkReader)) }
+
+func (h *chunkHeap) Pop() any {
+	c := h.chunks[len(h.chunks)-1]
+	h.chunks = h.chunks[:len(h.chunks)-1]
+	return c
+}
+
+// mergeChunks prints the lines of sorted chunks, merged in order.
+func mergeChunks(w io.Writer, files []*os.File, less func(a, b time.Time) bool) error {
+	h := &chunkHeap{less: less}
+	for i, f := range files {
+		c := &chunkReader{reader: bufio.NewReader(f), index: i}
+		ok, err := c.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h.chunks = append(h.chunks, c)
+		}
+	}
+	heap.Init(h)
+
+	out := bufio.NewWriter(w)
+	for h.Len() > 0 {
+		c := h.chunks[0]
+		if _, err := fmt.Fprintln(out, c.head.line); err != nil {
+			return ioError(err)
+		}
+
+		ok, err := c.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+
+	return ioError(out.Flush())
+}
+
+// filterBound parses --since or --until: an epoch or anything generate
+// accepts as base, optionally followed by deltas separated by spaces.
+func filterBound(value string, lt *lineTime, now time.Time) (time.Time, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || !epochMatch.MatchString(fields[0]) {
+		return parseDateDeltas(value, lt.layout, now)
+	}
+
+	precision := lt.precision
+	if lt.infer {
+		precision = ut.InferPrecision(fields[0])
+	}
+	t, err := lt.epoch.Parse(fields[0], precision)
+	if err != nil {
+		return t, err
+	}
+	for _, delta := range fields[1:] {
+		if t, err = holidayTable.ApplyDelta(t, delta); err != nil {
+			return t, err
+		}
+	}
+
+	return t, nil
+}
+
+// filterLines prints the lines with a timestamp from --since on and before
+// --until, as they are read.
+func filterLines(w io.Writer, r io.Reader, o FilterOptions, args []string, now time.Time) error {
+	lt, err := newLineTime(o.options, o.fields)
+	if err != nil {
+		return err
+	}
+
+	var since, until time.Time
+	hasSince, hasUntil := o.since != "", o.until != ""
+	if hasSince {
+		if since, err = filterBound(o.since, lt, now); err != nil {
+			return err
+		}
+	}
+	if hasUntil {
+		if until, err = filterBound(o.until, lt, now); err != nil {
+			return err
+		}
+	}
+
+	out := bufio.NewWriter(w)
+	err = eachTimedLine(r, args, lt, func(l timedLine) error {
+		if (hasSince && l.t.Before(since)) || (hasUntil && !l.t.Before(until)) {
+			return nil
+		}
+		if _, err := fmt.Fprintln(out, l.line); err != nil {
+			return ioError(err)
+		}
+		return nil
+	})
+	if flushErr := out.Flush(); err == nil {
+		err = ioError(flushErr)
+	}
+
+	return err
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"github.com/lsmoura/ut-cli/ut"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSortLines(t *testing.T) {
+	input := "1680717104 b\n1680717044000 a\n\n1680720000 d\n1680717104 c\n1680717044 a2\n"
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"in memory", []string{"sort"}, "1680717044000 a\n1680717044 a2\n1680717104 b\n1680717104 c\n1680720000 d\n"},
+		{"reverse", []string{"sort", "-R"}, "1680720000 d\n1680717104 b\n1680717104 c\n1680717044000 a\n1680717044 a2\n"},
+		{"merged", []string{"sort", "--buffer-size", "20"}, "1680717044000 a\n1680717044 a2\n1680717104 b\n1680717104 c\n1680720000 d\n"},
+		{"merged line by line", []string{"sort", "-S", "1", "-R"}, "1680720000 d\n1680717104 b\n1680717104 c\n1680717044000 a\n1680717044 a2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			o := SortOptions{}
+			args, err := o.Parse(tt.args...)
+			require.NoError(t, err)
+			require.NoError(t, sortLines(&buf, strings.NewReader(input), o, args))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestSortLinesFiles(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log")
+	require.NoError(t, os.WriteFile(first, []byte("GET 1680717104\nGET 1680717044"), 0o600))
+	require.NoError(t, os.WriteFile(second, []byte("POST 1680717050\n"), 0o600))
+
+	var buf strings.Builder
+	o := SortOptions{}
+	args, err := o.Parse("sort", "-k2", first, "-", second)
+	require.NoError(t, err)
+	require.NoError(t, sortLines(&buf, strings.NewReader("PUT 1680717000\n"), o, args))
+	assert.Equal(t, "PUT 1680717000\nGET 1680717044\nPOST 1680717050\nGET 1680717104\n", buf.String())
+
+	err = sortLines(&buf, strings.NewReader(""), o, []string{filepath.Join(dir, "missing")})
+	code, _ := describeError(err)
+	assert.Equal(t, exitIO, code)
+}
+
+func TestSortLinesInvalid(t *testing.T) {
+	input := "1680717104 b\nno timestamp\n1680717044 a\n"
+
+	err := sortLines(&strings.Builder{}, strings.NewReader(input), SortOptions{fields: FieldOptions{column: 1}, bufferSize: "64M"}, nil)
+	assert.ErrorIs(t, err, ut.ErrInvalidTimestamp)
+	assert.ErrorContains(t, err, "stdin:2:")
+
+	var buf strings.Builder
+	o := SortOptions{}
+	args, err := o.Parse("sort", "--skip-invalid")
+	require.NoError(t, err)
+	require.NoError(t, sortLines(&buf, strings.NewReader(input), o, args))
+	assert.Equal(t, "1680717044 a\n1680717104 b\n", buf.String())
+
+	o = SortOptions{}
+	_, err = o.Parse("sort", "-S", "12X")
+	require.NoError(t, err)
+	err = sortLines(&buf, strings.NewReader(input), o, nil)
+	code, _ := describeError(err)
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestParseByteSize(t *testing.T) {
+	for value, want := range map[string]int64{"1": 1, "512K": 512 << 10, "64m": 64 << 20, "2GB": 2 << 30} {
+		got, err := parseByteSize(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+	for _, value := range []string{"", "0", "-1", "1T", "99999999999G"} {
+		_, err := parseByteSize(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestFilterLines(t *testing.T) {
+	now := time.Date(2023, 4, 5, 18, 0, 0, 0, time.UTC)
+	input := `{"ts": 1680717044, "id": 1}
+{"ts": 1680710000000, "id": 2}
+{"ts": "2023-04-05T17:59:59Z", "id": 3}
+{"ts": 1680717600, "id": 4}
+`
+
+	tests := []struct {
+		name string
+		args []string
+		want []int
+	}{
+		{"since", []string{"--since", "1680717044"}, []int{1, 3, 4}},
+		{"until", []string{"--until", "now -1h"}, []int{2}},
+		{"since and until", []string{"-s", "2023-04-05T17:00:00Z", "-u", "now"}, []int{1, 3}},
+		{"epoch and delta", []string{"--since", "1680717044 +1s"}, []int{3, 4}},
+		{"keyword", []string{"--since", "now -5min"}, []int{3, 4}},
+		{"no bounds", nil, []int{1, 2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			o := FilterOptions{}
+			args, err := o.Parse(append([]string{"filter", "-j", ".ts"}, tt.args...)...)
+			require.NoError(t, err)
+			require.NoError(t, filterLines(&buf, strings.NewReader(input), o, args, now))
+
+			var want strings.Builder
+			for _, id := range tt.want {
+				want.WriteString(strings.Split(input, "\n")[id-1] + "\n")
+			}
+			assert.Equal(t, want.String(), buf.String())
+		})
+	}
+
+	o := FilterOptions{}
+	_, err := o.Parse("filter", "--since", "last week")
+	require.NoError(t, err)
+	err = filterLines(&strings.Builder{}, strings.NewReader(input), o, nil, now)
+	assert.ErrorIs(t, err, ut.ErrInvalidBase)
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/lsmoura/ut-cli/strftime"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// maxSleepChunk bounds each wait, so the wall clock is checked again after a
+// suspend or a clock change.
+const maxSleepChunk = time.Minute
+
+var epochMatch = regexp.MustCompile(`^[+-]?\d+(\.\d+)?$`)
+
+// untilTarget resolves the target of the until subcommand: an epoch in the
+// configured precision or anything generate accepts as base, plus deltas.
+func untilTarget(o UntilOptions, target string, now time.Time) (time.Time, error) {
+	var t time.Time
+	if epochMatch.MatchString(target) {
+		epoch, precision, err := o.options.EpochPrecision()
+		if err != nil {
+			return t, err
+		}
+		if t, err = epoch.Parse(target, precision); err != nil {
+			return t, err
+		}
+	} else {
+		layout, _ := o.options.Format()
+		var err error
+		if t, err = ut.ParseBase(target, layout, now); err != nil {
+			return t, err
+		}
+	}
+
+	for _, delta := range o.delta {
+		var err error
+		if t, err = holidayTable.ApplyDelta(t, delta); err != nil {
+			return t, err
+		}
+	}
+
+	return t, nil
+}
+
+// formatRemaining renders a duration using the --display format. Without a
+// format the duration is printed the Go way, rounded to the second.
+func formatRemaining(d time.Duration, format string) string {
+	if format == "" {
+		return d.Round(time.Second).String()
+	}
+
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	total := int64(d.Round(time.Second) / time.Second)
+
+	var out string
+	for _, piece := range strftime.StrTimeTokens(format) {
+		switch piece {
+		case "%d":
+			out += strconv.FormatInt(total/86400, 10)
+		case "%h":
+			out += strconv.FormatInt(total%86400/3600, 10)
+		case "%m":
+			out += strconv.FormatInt(total%3600/60, 10)
+		case "%s":
+			out += strconv.FormatInt(total%60, 10)
+		case "%H":
+			out += fmt.Sprintf("%02d", total%86400/3600)
+		case "%M":
+			out += fmt.Sprintf("%02d", total%3600/60)
+		case "%S":
+			out += fmt.Sprintf("%02d", total%60)
+		case "%T":
+			out += strconv.FormatInt(total, 10)
+		case "%%":
+			out += "%"
+		default:
+			out += piece
+		}
+	}
+
+	return sign + out
+}
+
+// sleepUntil blocks until the wall clock reaches target or ctx is done.
+func sleepUntil(ctx context.Context, target time.Time, step time.Duration) error {
+	for {
+		remaining := time.Until(target)
+		if remaining <= 0 {
+			return nil
+		}
+		if remaining > step {
+			remaining = step
+		}
+
+		timer := time.NewTimer(remaining)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// shellCommand returns the command running the given line through the
+// platform shell.
+func shellCommand(ctx context.Context, line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", line)
+	}
+	return exec.CommandContext(ctx, "/bin/sh", "-c", line)
+}
+
+// until prints the time remaining until the target, or waits for it, with
+// now reading the clock.
+func until(ctx context.Context, w io.Writer, o UntilOptions, args []string, inPlace bool, now func() time.Time) error {
+	if len(args) == 0 {
+		return usageError("missing target")
+	}
+	if len(args) > 1 {
+		return usageError("too many arguments")
+	}
+	if o.live && o.sleep {
+		return usageError("--live and --sleep cannot be used together")
+	}
+
+	target, err := untilTarget(o, args[0], now())
+	if err != nil {
+		return err
+	}
+
+	// the remaining time is a time.Duration, which cannot hold more than 292 years
+	if start := now(); target.After(start.Add(math.MaxInt64)) || target.Before(start.Add(math.MinInt64)) {
+		return fmt.Errorf("%w: %s is more than 292 years away", ut.ErrOutOfRange, target)
+	}
+
+	interval := time.Second
+	if o.interval != "" {
+		if interval, err = parseInterval(o.interval); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case o.live:
+		for {
+			remaining := target.Sub(now())
+			if remaining < 0 {
+				remaining = 0
+			}
+			line := formatRemaining(remaining, o.display)
+			if inPlace {
+				_, err = fmt.Fprintf(w, "\r%s\x1b[K", line)
+			} else {
+				_, err = fmt.Fprintln(w, line)
+			}
+			if err != nil {
+				return ioError(err)
+			}
+			if remaining == 0 {
+				break
+			}
+
+			step := remaining % interval
+			if step == 0 {
+				step = interval
+			}
+			if err := sleepUntil(ctx, time.Now().Add(step), maxSleepChunk); err != nil {
+				if inPlace {
+					fmt.Fprintln(w)
+				}
+				return errInterruptedWait
+			}
+		}
+		if inPlace {
+			fmt.Fprintln(w)
+		}
+	case o.sleep, o.exec != "":
+		if err := sleepUntil(ctx, target, maxSleepChunk); err != nil {
+			return errInterruptedWait
+		}
+	default:
+		_, err := fmt.Fprintln(w, formatRemaining(target.Sub(now()), o.display))
+		return ioError(err)
+	}
+
+	if o.exec == "" {
+		return nil
+	}
+
+	cmd := shellCommand(ctx, o.exec)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &cliError{code: exitFailure, err: fmt.Errorf("command %q failed: %w", o.exec, err)}
+		}
+		return &cliError{code: exitFailure, err: fmt.Errorf("cannot run command %q: %w", o.exec, err)}
+	}
+
+	return nil
+}
+
+// errInterruptedWait is returned when the wait for the target is interrupted,
+// so scripts can tell the target was not reached.
+var errInterruptedWait = &cliError{code: exitFailure, err: errors.New("interrupted before reaching the target")}
+
+// untilStdout runs until on stdout, stopping when interrupted.
+func untilStdout(o UntilOptions, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return until(ctx, os.Stdout, o, args, isTerminal(os.Stdout), time.Now)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"github.com/lsmoura/ut-cli/ut"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUntilTarget(t *testing.T) {
+	now := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	tests := []struct {
+		target   string
+		options  UntilOptions
+		expected time.Time
+	}{
+		{"1680717044", UntilOptions{}, now},
+		{"1680717044000", UntilOptions{options: Options{precision: "ms"}}, now},
+		{"tomorrow", UntilOptions{}, now.AddDate(0, 0, 1)},
+		{"2023-04-05T18:00:00Z", UntilOptions{}, time.Date(2023, 4, 5, 18, 0, 0, 0, time.UTC)},
+		{"2023-04-05", UntilOptions{options: Options{format: "%Y-%m-%d"}, delta: []string{"9h"}}, time.Date(2023, 4, 5, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		actual, err := untilTarget(test.options, test.target, now)
+		require.NoErrorf(t, err, "resolving %q", test.target)
+		assert.Truef(t, test.expected.Equal(actual), "resolving %q: expected %s, got %s", test.target, test.expected, actual)
+	}
+
+	_, err := untilTarget(UntilOptions{}, "someday", now)
+	assert.Error(t, err)
+}
+
+func TestFormatRemaining(t *testing.T) {
+	d := 26*time.Hour + 3*time.Minute + 4*time.Second + 400*time.Millisecond
+
+	assert.Equal(t, "26h3m4s", formatRemaining(d, ""))
+	assert.Equal(t, "1d 02:03:04", formatRemaining(d, "%dd %H:%M:%S"))
+	assert.Equal(t, "1 days, 2 hours, 3 minutes, 4 seconds", formatRemaining(d, "%d days, %h hours, %m minutes, %s seconds"))
+	assert.Equal(t, "93784", formatRemaining(d, "%T"))
+	assert.Equal(t, "-00:01:00", formatRemaining(-time.Minute, "%H:%M:%S"))
+	assert.Equal(t, "100%", formatRemaining(0, "100%%"))
+}
+
+func TestUntil(t *testing.T) {
+	ctx := context.Background()
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	soon := strconv.FormatInt(time.Now().Add(time.Second).Unix(), 10)
+	clock := func() time.Time { return time.Date(2023, 4, 5, 17, 51, 44, 400000000, time.UTC) }
+
+	var buf strings.Builder
+	require.NoError(t, until(ctx, &buf, UntilOptions{}, []string{"1680717044"}, false, clock))
+	assert.Equal(t, "-1m0s\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, until(ctx, &buf, UntilOptions{sleep: true}, []string{past}, false, time.Now))
+	assert.Equal(t, "", buf.String())
+
+	buf.Reset()
+	require.NoError(t, until(ctx, &buf, UntilOptions{exec: "echo reached"}, []string{past}, false, time.Now))
+	assert.Equal(t, "reached\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, until(ctx, &buf, UntilOptions{live: true, display: "%T"}, []string{soon}, false, time.Now))
+	assert.True(t, strings.HasSuffix(buf.String(), "0\n"), "live output should end at 0: %q", buf.String())
+
+	assert.Error(t, until(ctx, &buf, UntilOptions{exec: "exit 3"}, []string{past}, false, time.Now))
+	assert.Error(t, until(ctx, &buf, UntilOptions{}, nil, false, time.Now))
+	assert.Error(t, until(ctx, &buf, UntilOptions{live: true, sleep: true}, []string{past}, false, time.Now))
+	assert.ErrorIs(t, until(ctx, &buf, UntilOptions{}, []string{"99999999999"}, false, time.Now), ut.ErrOutOfRange)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, until(cancelled, &buf, UntilOptions{sleep: true}, []string{"tomorrow"}, false, time.Now), errInterruptedWait)
+}
@@ -0,0 +1,169 @@
+package ut
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var bucketUnitMatch = regexp.MustCompile(`^(\d*)(d|days?|w|weeks?|mo|months?|q|quarters?|y|years?)$`)
+
+// BucketSize is the width of time buckets: a fixed duration, counted from an
+// instant, or a number of calendar days, weeks, months or years, counted on
+// the calendar of the zone of the bucketed times.
+type BucketSize struct {
+	fixed time.Duration
+	days  int64 // calendar days or weeks, in days
+	month int64 // calendar months or years, in months
+}
+
+// Bucket is the bucket a time falls in.
+type Bucket struct {
+	Start, End time.Time
+	// Index counts buckets from the one starting at the origin.
+	Index int64
+}
+
+// ParseBucketSize parses a bucket size: a Go duration like "15m" or "1h30m",
+// a number of seconds, or a number of calendar units, like "day", "2w",
+// "month", "1q" or "year".
+func ParseBucketSize(value string) (BucketSize, error) {
+	if matches := bucketUnitMatch.FindStringSubmatch(value); len(matches) > 0 {
+		n := int64(1)
+		if matches[1] != "" {
+			var err error
+			if n, err = strconv.ParseInt(matches[1], 10, 64); err != nil || n < 1 || n > 1000000 {
+				return BucketSize{}, valueError(ErrInvalidBucketSize, value)
+			}
+		}
+
+		switch matches[2][0] {
+		case 'd':
+			return BucketSize{days: n}, nil
+		case 'w':
+			return BucketSize{days: 7 * n}, nil
+		case 'm':
+			return BucketSize{month: n}, nil
+		case 'q':
+			return BucketSize{month: 3 * n}, nil
+		default:
+			return BucketSize{month: 12 * n}, nil
+		}
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, numErr := strconv.ParseInt(value, 10, 64)
+		if numErr != nil || seconds > int64(1<<63-1)/int64(time.Second) {
+			return BucketSize{}, valueError(ErrInvalidBucketSize, value)
+		}
+		d = time.Duration(seconds) * time.Second
+	}
+	if d <= 0 {
+		return BucketSize{}, valueError(ErrInvalidBucketSize, value)
+	}
+
+	return BucketSize{fixed: d}, nil
+}
+
+// DefaultOrigin returns the origin buckets are counted from when none is
+// given: the unix epoch, or Monday 1970-01-05 for weeks, so they follow ISO
+// 8601.
+func (s BucketSize) DefaultOrigin() time.Time {
+	if s.days > 0 && s.days%7 == 0 {
+		return time.Date(1970, 1, 5, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Unix(0, 0).UTC()
+}
+
+// Bucket returns the bucket t falls in. Fixed buckets start at the origin
+// plus a multiple of their size. Calendar buckets start at midnight, in the
+// zone of t, on the date of the origin in its own location, or a multiple of
+// the size away from it.
+func (s BucketSize) Bucket(t time.Time, origin time.Time) (Bucket, error) {
+	if s.fixed > 0 {
+		return s.fixedBucket(t, origin)
+	}
+
+	loc := t.Location()
+
+	var b Bucket
+	if s.days > 0 {
+		b.Index = floorDiv(civilDay(t)-civilDay(origin), s.days)
+		day := civilDay(origin) + b.Index*s.days
+		b.Start = time.Date(1970, 1, 1+int(day), 0, 0, 0, 0, loc)
+		b.End = time.Date(1970, 1, 1+int(day+s.days), 0, 0, 0, 0, loc)
+	} else {
+		// months without the day of the origin start on their last day
+		y, m, d := origin.Date()
+		first := time.Date(y, m, d, 0, 0, 0, 0, loc)
+		b.Index = floorDiv(civilMonth(t)-civilMonth(origin), s.month)
+		if b.Start = addMonths(first, b.Index*s.month); t.Before(b.Start) {
+			b.Index--
+			b.Start = addMonths(first, b.Index*s.month)
+		}
+		b.End = addMonths(first, (b.Index+1)*s.month)
+	}
+
+	if err := checkRange(b.Start); err != nil {
+		return b, err
+	}
+	if err := checkRange(b.End); err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+func (s BucketSize) fixedBucket(t time.Time, origin time.Time) (Bucket, error) {
+	size := big.NewInt(int64(s.fixed))
+	elapsed := new(big.Int).Sub(unixNanos(t), unixNanos(origin))
+
+	// big.Int implements Euclidean division, which rounds down for positive divisors
+	index := new(big.Int).Div(elapsed, size)
+	if !index.IsInt64() {
+		return Bucket{}, fmt.Errorf("%w: bucket index of %s does not fit in 64 bits", ErrOutOfRange, t.Format(time.RFC3339Nano))
+	}
+
+	start := new(big.Int).Add(unixNanos(origin), new(big.Int).Mul(index, size))
+	end := new(big.Int).Add(start, size)
+
+	b := Bucket{Index: index.Int64(), Start: fromNanos(start).In(t.Location()), End: fromNanos(end).In(t.Location())}
+	if err := checkRange(b.End); err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// civilDay returns the days since 1970-01-01 of the date of t, in its zone.
+func civilDay(t time.Time) int64 {
+	y, m, d := t.Date()
+	return floorDiv(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix(), 86400)
+}
+
+// civilMonth returns the months since January of year 0 of the date of t.
+func civilMonth(t time.Time) int64 {
+	return int64(t.Year())*12 + int64(t.Month()) - 1
+}
+
+func unixNanos(t time.Time) *big.Int {
+	nanos := new(big.Int).Mul(big.NewInt(t.Unix()), billion)
+	return nanos.Add(nanos, big.NewInt(int64(t.Nanosecond())))
+}
+
+func fromNanos(nanos *big.Int) time.Time {
+	sec, nsec := new(big.Int).DivMod(nanos, billion, new(big.Int))
+	return time.Unix(sec.Int64(), nsec.Int64())
+}
+
+// floorDiv divides rounding towards negative infinity.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
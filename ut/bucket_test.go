@@ -0,0 +1,100 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParseBucketSize(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected BucketSize
+	}{
+		{"15m", BucketSize{fixed: 15 * time.Minute}},
+		{"1h30m", BucketSize{fixed: 90 * time.Minute}},
+		{"300", BucketSize{fixed: 5 * time.Minute}},
+		{"day", BucketSize{days: 1}},
+		{"2d", BucketSize{days: 2}},
+		{"week", BucketSize{days: 7}},
+		{"2w", BucketSize{days: 14}},
+		{"month", BucketSize{month: 1}},
+		{"1mo", BucketSize{month: 1}},
+		{"quarter", BucketSize{month: 3}},
+		{"years", BucketSize{month: 12}},
+	}
+
+	for _, test := range tests {
+		actual, err := ParseBucketSize(test.value)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, actual, test.value)
+	}
+
+	for _, value := range []string{"", "0", "-5m", "0d", "2x", "fortnight", "99999999999999999999"} {
+		_, err := ParseBucketSize(value)
+		assert.ErrorIsf(t, err, ErrInvalidBucketSize, "expected error for %q", value)
+	}
+}
+
+func TestBucket(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 2023-04-05 17:50:44 UTC, a Wednesday
+	at := time.Unix(1680717044, 0)
+
+	tests := []struct {
+		size   string
+		t      time.Time
+		origin time.Time
+		start  time.Time
+		end    time.Time
+		index  int64
+		note   string
+	}{
+		{"15m", at.UTC(), time.Time{}, time.Unix(1680716700, 0), time.Unix(1680717600, 0), 1867463, ""},
+		{"1h", at.UTC(), time.Unix(1800, 0), time.Unix(1680715800, 0), time.Unix(1680719400, 0), 466865, ""},
+		{"1h", time.Unix(-1, 0), time.Time{}, time.Unix(-3600, 0), time.Unix(0, 0), -1, ""},
+		{"day", at.In(newYork), time.Time{}, time.Date(2023, 4, 5, 0, 0, 0, 0, newYork), time.Date(2023, 4, 6, 0, 0, 0, 0, newYork), 19452, ""},
+		{"week", at.In(newYork), time.Time{}, time.Date(2023, 4, 3, 0, 0, 0, 0, newYork), time.Date(2023, 4, 10, 0, 0, 0, 0, newYork), 2778, ""},
+		{"week", at.UTC(), time.Date(2023, 4, 2, 0, 0, 0, 0, time.UTC), time.Date(2023, 4, 2, 0, 0, 0, 0, time.UTC), time.Date(2023, 4, 9, 0, 0, 0, 0, time.UTC), 0, ""},
+		{"quarter", at.UTC(), time.Time{}, time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), 213, ""},
+		{"year", at.UTC(), time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), 1, "fiscal years starting in February"},
+		// the day clocks go forward is 23 hours long
+		{"day", time.Date(2023, 3, 12, 12, 0, 0, 0, newYork), time.Time{}, time.Date(2023, 3, 12, 0, 0, 0, 0, newYork), time.Date(2023, 3, 13, 0, 0, 0, 0, newYork), 19428, ""},
+		{"month", at.UTC(), time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC), time.Date(2023, 4, 15, 0, 0, 0, 0, time.UTC), 2, "before the day of the origin"},
+		{"month", time.Date(2023, 2, 28, 12, 0, 0, 0, time.UTC), time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC), time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC), 1, "on the last day of shorter months"},
+		{"quarter", at.In(newYork), time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 15, 0, 0, 0, 0, newYork), time.Date(2023, 4, 15, 0, 0, 0, 0, newYork), 0, ""},
+		{"month", time.Date(1969, 12, 31, 23, 0, 0, 0, time.UTC), time.Time{}, time.Date(1969, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), -1, ""},
+	}
+
+	for _, test := range tests {
+		size, err := ParseBucketSize(test.size)
+		require.NoError(t, err)
+
+		origin := test.origin
+		if origin.IsZero() {
+			origin = size.DefaultOrigin()
+		}
+
+		b, err := size.Bucket(test.t, origin)
+		require.NoError(t, err)
+		assert.Truef(t, test.start.Equal(b.Start), "%s bucket of %s starts at %s, not %s %s", test.size, test.t, b.Start, test.start, test.note)
+		assert.Truef(t, test.end.Equal(b.End), "%s bucket of %s ends at %s, not %s %s", test.size, test.t, b.End, test.end, test.note)
+		assert.Equal(t, test.index, b.Index, test.size)
+		assert.Equal(t, test.t.Location(), b.Start.Location())
+	}
+}
+
+func TestBucketRange(t *testing.T) {
+	size, err := ParseBucketSize("1ns")
+	require.NoError(t, err)
+	_, err = size.Bucket(MaxTime, size.DefaultOrigin())
+	assert.ErrorIs(t, err, ErrOutOfRange)
+
+	size, err = ParseBucketSize("year")
+	require.NoError(t, err)
+	_, err = size.Bucket(MaxTime, size.DefaultOrigin())
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
@@ -0,0 +1,102 @@
+package ut
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/lsmoura/ut-cli/calendar"
+	"github.com/lsmoura/ut-cli/strftime"
+)
+
+// Calendar is a representation of dates other than the default Gregorian one.
+type Calendar int
+
+const (
+	CalendarISOWeek  Calendar = iota // ISO 8601 week date, like 2023-W14-3
+	CalendarOrdinal                  // ISO 8601 ordinal date, like 2023-095
+	CalendarJDN                      // Julian day number of the date
+	CalendarJD                       // astronomical Julian date of the instant, with fractions
+	CalendarMJD                      // Modified Julian date of the instant, with fractions
+	CalendarJulian                   // proleptic Julian calendar
+	CalendarJapanese                 // Japanese era, like Reiwa 5-04-05
+	CalendarHijri                    // tabular Islamic calendar
+)
+
+type calendarSpec struct {
+	name    string
+	aliases []string
+	layout  string // strftime layout, empty for the Julian dates
+}
+
+var calendarSpecs = map[Calendar]calendarSpec{
+	CalendarISOWeek:  {"iso-week", []string{"isoweek", "week"}, "%G-W%V-%u"},
+	CalendarOrdinal:  {"ordinal", []string{"yday"}, "%Y-%j"},
+	CalendarJDN:      {"jdn", nil, "%J"},
+	CalendarJD:       {"jd", nil, ""},
+	CalendarMJD:      {"mjd", nil, ""},
+	CalendarJulian:   {"julian", nil, "%Ej"},
+	CalendarJapanese: {"japanese", []string{"era", "wareki"}, "%EY-%m-%d"},
+	CalendarHijri:    {"hijri", []string{"islamic"}, "%Eh"},
+}
+
+func (c Calendar) String() string {
+	if spec, ok := calendarSpecs[c]; ok {
+		return spec.name
+	}
+
+	return fmt.Sprintf("Calendar(%d)", int(c))
+}
+
+// CalendarNames lists the names accepted by LookupCalendar, aliases included.
+func CalendarNames() []string {
+	var names []string
+	for c := CalendarISOWeek; c <= CalendarHijri; c++ {
+		names = append(names, calendarSpecs[c].name)
+		names = append(names, calendarSpecs[c].aliases...)
+	}
+	return names
+}
+
+// LookupCalendar returns the calendar with the given name.
+func LookupCalendar(name string) (Calendar, error) {
+	lower := strings.ToLower(name)
+	for c, spec := range calendarSpecs {
+		if spec.name == lower {
+			return c, nil
+		}
+		for _, alias := range spec.aliases {
+			if alias == lower {
+				return c, nil
+			}
+		}
+	}
+
+	return CalendarISOWeek, valueError(ErrUnknownCalendar, name)
+}
+
+// Format returns t in the calendar. Dates come from the location of t, except
+// for the Julian and Modified Julian dates, which count from UTC. Japanese
+// eras start with the adoption of the Gregorian calendar in 1873; earlier
+// dates return ErrOutOfRange.
+func (c Calendar) Format(t time.Time) (string, error) {
+	switch c {
+	case CalendarJD:
+		return decimalString(calendar.JulianDate(t)), nil
+	case CalendarMJD:
+		return decimalString(calendar.ModifiedJulianDate(t)), nil
+	case CalendarJapanese:
+		if _, _, ok := calendar.JapaneseEra(t); !ok {
+			return "", fmt.Errorf("%w (Japanese eras start in 1873)", valueError(ErrOutOfRange, t.Format("2006-01-02")))
+		}
+	}
+
+	return strftime.Strftime(t, calendarSpecs[c].layout), nil
+}
+
+// decimalString prints r with up to 10 decimals, without trailing zeros.
+func decimalString(r *big.Rat) string {
+	s := strings.TrimRight(r.FloatString(10), "0")
+	return strings.TrimSuffix(s, ".")
+}
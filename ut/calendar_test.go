@@ -0,0 +1,57 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestLookupCalendar(t *testing.T) {
+	for _, name := range CalendarNames() {
+		_, err := LookupCalendar(name)
+		assert.NoErrorf(t, err, "looking up %q", name)
+	}
+
+	c, err := LookupCalendar("Wareki")
+	require.NoError(t, err)
+	assert.Equal(t, CalendarJapanese, c)
+
+	_, err = LookupCalendar("mayan")
+	assert.ErrorIs(t, err, ErrUnknownCalendar)
+}
+
+func TestCalendarFormat(t *testing.T) {
+	ref := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	tests := []struct {
+		calendar Calendar
+		time     time.Time
+		expected string
+	}{
+		{CalendarISOWeek, ref, "2023-W14-3"},
+		{CalendarISOWeek, time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC), "2020-W53-7"},
+		{CalendarOrdinal, ref, "2023-095"},
+		{CalendarJDN, ref, "2460040"},
+		{CalendarJD, time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC), "2451545"},
+		{CalendarJD, ref, "2460040.2435648148"},
+		{CalendarMJD, time.Date(2023, 4, 5, 18, 0, 0, 0, time.UTC), "60039.75"},
+		{CalendarJulian, ref, "2023-03-23"},
+		{CalendarJulian, time.Date(1582, 10, 15, 0, 0, 0, 0, time.UTC), "1582-10-05"},
+		{CalendarJapanese, ref, "Reiwa 5-04-05"},
+		{CalendarJapanese, time.Date(2019, 4, 30, 0, 0, 0, 0, time.UTC), "Heisei 31-04-30"},
+		{CalendarHijri, ref, "1444-09-14"},
+		// calendar dates follow the zone of the time, Julian dates do not
+		{CalendarJDN, ref.In(time.FixedZone("JST", 9*3600)), "2460041"},
+		{CalendarJD, ref.In(time.FixedZone("JST", 9*3600)), "2460040.2435648148"},
+	}
+
+	for _, test := range tests {
+		actual, err := test.calendar.Format(test.time)
+		require.NoErrorf(t, err, "formatting %s as %s", test.time, test.calendar)
+		assert.Equalf(t, test.expected, actual, "formatting %s as %s", test.time, test.calendar)
+	}
+
+	_, err := CalendarJapanese.Format(time.Date(1850, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
@@ -0,0 +1,320 @@
+package ut
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears bounds the search for fire times: schedules like
+// "0 0 29 2 *", on the 29th of February, go 8 years without firing around
+// 2100, which is not a leap year.
+const cronSearchYears = 100
+
+// dstMargin is more than the largest daylight saving shift of any zone.
+const dstMargin = 3 * time.Hour
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	second, minute, hour, dom, month, dow uint64 // bit sets of the allowed values
+	// domStar and dowStar tell whether the day fields are unrestricted; when
+	// both are restricted, a day matching either fires, like Vixie cron.
+	domStar, dowStar bool
+}
+
+// cronField describes the values allowed in a field of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // names of the values from min on, if any
+}
+
+var (
+	cronSeconds = cronField{name: "second", max: 59}
+	cronMinutes = cronField{name: "minute", max: 59}
+	cronHours   = cronField{name: "hour", max: 23}
+	cronDoms    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonths  = cronField{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	// 7 is Sunday too
+	cronDows = cronField{name: "day of week", max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// cronMacros maps the @ shortcuts to their expressions.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// ParseCron parses a cron expression: the five standard fields (minute, hour,
+// day of month, month and day of week), an optional leading seconds field, or
+// one of the macros @yearly, @annually, @monthly, @weekly, @daily, @midnight
+// and @hourly. Fields accept "*", "?", lists, ranges, steps like "*/15" or
+// "1-30/2", and the names of months and days of the week.
+func ParseCron(expr string) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+		macro, ok := cronMacros[strings.ToLower(fields[0])]
+		if !ok {
+			return nil, fmt.Errorf("%w (unknown macro)", valueError(ErrInvalidCron, expr))
+		}
+		fields = strings.Fields(macro)
+	}
+
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w (expected 5 or 6 fields, found %d)", valueError(ErrInvalidCron, expr), len(fields))
+	}
+
+	s := &Schedule{
+		domStar: strings.HasPrefix(fields[3], "*") || fields[3] == "?",
+		dowStar: strings.HasPrefix(fields[5], "*") || fields[5] == "?",
+	}
+	specs := []struct {
+		bits  *uint64
+		field cronField
+	}{
+		{&s.second, cronSeconds},
+		{&s.minute, cronMinutes},
+		{&s.hour, cronHours},
+		{&s.dom, cronDoms},
+		{&s.month, cronMonths},
+		{&s.dow, cronDows},
+	}
+	for i, spec := range specs {
+		bits, err := spec.field.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w (%s)", valueError(ErrInvalidCron, expr), err)
+		}
+		*spec.bits = bits
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+
+	return s, nil
+}
+
+// parse returns the bit set of the values allowed by a field.
+func (f cronField) parse(value string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q in %s", stepPart, f.name)
+			}
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			if rangePart == "?" && f.name != cronDoms.name && f.name != cronDows.name {
+				return 0, fmt.Errorf("\"?\" is only allowed for days, not %s", f.name)
+			}
+			lo, hi = f.min, f.max
+			if f.name == cronDows.name {
+				hi = 6
+			}
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = f.value(from); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(to); err != nil {
+				return 0, err
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("invalid range %q in %s", rangePart, f.name)
+			}
+		default:
+			var err error
+			if lo, err = f.value(rangePart); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if hasStep {
+				// "5/15" is "5-max/15"
+				hi = f.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// value parses a single value of the field, as a number or a name.
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q", f.name, s)
+	}
+
+	return v, nil
+}
+
+func (s *Schedule) matchDay(w time.Time) bool {
+	dom := s.dom&(1<<uint(w.Day())) != 0
+	dow := s.dow&(1<<uint(w.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// nextWall returns the first wall clock time from w on matching the
+// schedule. Wall clock times are carried in UTC.
+func (s *Schedule) nextWall(w time.Time) (time.Time, bool) {
+	limit := w.Year() + cronSearchYears
+	for w.Year() <= limit {
+		y, m, d := w.Date()
+		switch {
+		case s.month&(1<<uint(m)) == 0:
+			w = time.Date(y, m+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.matchDay(w):
+			w = time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<uint(w.Hour())) == 0:
+			w = w.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<uint(w.Minute())) == 0:
+			w = w.Truncate(time.Minute).Add(time.Minute)
+		case s.second&(1<<uint(w.Second())) == 0:
+			w = w.Add(time.Second)
+		default:
+			return w, true
+		}
+	}
+
+	return w, false
+}
+
+// prevWall returns the last wall clock time up to w matching the schedule.
+func (s *Schedule) prevWall(w time.Time) (time.Time, bool) {
+	limit := w.Year() - cronSearchYears
+	for w.Year() >= limit {
+		y, m, d := w.Date()
+		switch {
+		case s.month&(1<<uint(m)) == 0:
+			w = time.Date(y, m, 1, 0, 0, 0, 0, time.UTC).Add(-time.Second)
+		case !s.matchDay(w):
+			w = time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Add(-time.Second)
+		case s.hour&(1<<uint(w.Hour())) == 0:
+			w = w.Truncate(time.Hour).Add(-time.Second)
+		case s.minute&(1<<uint(w.Minute())) == 0:
+			w = w.Truncate(time.Minute).Add(-time.Second)
+		case s.second&(1<<uint(w.Second())) == 0:
+			w = w.Add(-time.Second)
+		default:
+			return w, true
+		}
+	}
+
+	return w, false
+}
+
+// wall returns the wall clock time of t, carried in UTC.
+func wall(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// resolveWall returns the instant of the wall clock time w in loc. Times
+// skipped by a daylight saving gap resolve to the end of the gap; times
+// repeated when clocks go back resolve to their first occurrence.
+func resolveWall(w time.Time, loc *time.Location) time.Time {
+	t := time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), 0, loc)
+	start, end := t.ZoneBounds()
+
+	// the offsets in effect around t, the only ones w may be read with
+	_, offset := t.Zone()
+	offsets := []int{offset}
+	if !start.IsZero() {
+		_, before := start.Add(-time.Second).Zone()
+		offsets = append(offsets, before)
+	}
+	if !end.IsZero() {
+		_, after := end.Zone()
+		offsets = append(offsets, after)
+	}
+
+	var first time.Time
+	for _, offset := range offsets {
+		c := time.Unix(w.Unix()-int64(offset), 0).In(loc)
+		if wall(c).Equal(w) && (first.IsZero() || c.Before(first)) {
+			first = c
+		}
+	}
+	if first.IsZero() {
+		// in a gap: t was read with the offset of one of its sides
+		if wall(t).Before(w) {
+			return end
+		}
+		return start
+	}
+
+	return first
+}
+
+// Next returns the first fire time after t, evaluated on the wall clock of
+// t's location. It returns ErrOutOfRange when the schedule does not fire in
+// the next 100 years, like "0 0 30 2 *".
+func (s *Schedule) Next(t time.Time) (time.Time, error) {
+	loc := t.Location()
+	w := wall(t).Add(time.Second)
+	for {
+		var ok bool
+		if w, ok = s.nextWall(w); !ok {
+			return t, fmt.Errorf("%w: the schedule does not fire in the %d years after %s", ErrOutOfRange, cronSearchYears, t.Format(time.RFC3339))
+		}
+		if err := checkRange(w); err != nil {
+			return t, err
+		}
+		// times repeated or skipped by daylight saving may resolve before t
+		if fire := resolveWall(w, loc); fire.After(t) {
+			return fire, nil
+		}
+		w = w.Add(time.Second)
+	}
+}
+
+// Prev returns the last fire time before t, evaluated like Next.
+func (s *Schedule) Prev(t time.Time) (time.Time, error) {
+	loc := t.Location()
+	// fire times are ordered like their wall clock times, but a wall clock
+	// time after t's may resolve before t around a daylight saving change
+	w := wall(t).Add(dstMargin)
+	for {
+		var ok bool
+		if w, ok = s.prevWall(w); !ok {
+			return t, fmt.Errorf("%w: the schedule does not fire in the %d years before %s", ErrOutOfRange, cronSearchYears, t.Format(time.RFC3339))
+		}
+		if err := checkRange(w); err != nil {
+			return t, err
+		}
+		if fire := resolveWall(w, loc); fire.Before(t) {
+			return fire, nil
+		}
+		w = w.Add(-time.Second)
+	}
+}
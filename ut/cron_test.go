@@ -0,0 +1,148 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "* * * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "? * * * *", "* * * foo *", "@reboot"} {
+		_, err := ParseCron(expr)
+		assert.ErrorIsf(t, err, ErrInvalidCron, "expected error for %q", expr)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// 2023-04-05 is a Wednesday
+	base := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		expected []time.Time
+	}{
+		{"*/15 * * * *", []time.Time{
+			time.Date(2023, 4, 5, 18, 0, 0, 0, time.UTC),
+			time.Date(2023, 4, 5, 18, 15, 0, 0, time.UTC),
+		}},
+		{"0 9 * * mon-fri", []time.Time{
+			time.Date(2023, 4, 6, 9, 0, 0, 0, time.UTC),
+			time.Date(2023, 4, 7, 9, 0, 0, 0, time.UTC),
+			time.Date(2023, 4, 10, 9, 0, 0, 0, time.UTC),
+		}},
+		{"30 */20 * * * *", []time.Time{
+			time.Date(2023, 4, 5, 18, 0, 30, 0, time.UTC),
+			time.Date(2023, 4, 5, 18, 20, 30, 0, time.UTC),
+		}},
+		{"0 0 1 * 7", []time.Time{ // first of the month or Sundays
+			time.Date(2023, 4, 9, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 4, 16, 0, 0, 0, 0, time.UTC),
+		}},
+		{"0 0 13 * ?", []time.Time{
+			time.Date(2023, 4, 13, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 5, 13, 0, 0, 0, 0, time.UTC),
+		}},
+		{"0 12 29 FEB *", []time.Time{
+			time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+			time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC),
+		}},
+		{"@weekly", []time.Time{
+			time.Date(2023, 4, 9, 0, 0, 0, 0, time.UTC),
+		}},
+		{"@hourly", []time.Time{
+			time.Date(2023, 4, 5, 18, 0, 0, 0, time.UTC),
+		}},
+		{"5/20 1,2 * * *", []time.Time{
+			time.Date(2023, 4, 6, 1, 5, 0, 0, time.UTC),
+			time.Date(2023, 4, 6, 1, 25, 0, 0, time.UTC),
+			time.Date(2023, 4, 6, 1, 45, 0, 0, time.UTC),
+			time.Date(2023, 4, 6, 2, 5, 0, 0, time.UTC),
+		}},
+	}
+
+	for _, test := range tests {
+		s, err := ParseCron(test.expr)
+		require.NoError(t, err, test.expr)
+
+		next := base
+		for _, expected := range test.expected {
+			next, err = s.Next(next)
+			assert.NoError(t, err)
+			assert.Equalf(t, expected, next, "next fire time of %q", test.expr)
+		}
+
+		prev := next
+		for i := len(test.expected) - 2; i >= 0; i-- {
+			prev, err = s.Prev(prev)
+			assert.NoError(t, err)
+			assert.Equalf(t, test.expected[i], prev, "previous fire time of %q", test.expr)
+		}
+	}
+}
+
+func TestScheduleDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		expr     string
+		base     time.Time
+		expected []string
+	}{
+		// clocks go forward from 02:00 to 03:00 on 2023-03-12
+		{"30 2 * * *", time.Date(2023, 3, 11, 12, 0, 0, 0, loc), []string{"2023-03-12 03:00 EDT", "2023-03-13 02:30 EDT"}},
+		{"*/30 * * * *", time.Date(2023, 3, 12, 1, 0, 0, 0, loc), []string{"2023-03-12 01:30 EST", "2023-03-12 03:00 EDT", "2023-03-12 03:30 EDT"}},
+		// clocks go back from 02:00 to 01:00 on 2023-11-05
+		{"30 1 * * *", time.Date(2023, 11, 4, 12, 0, 0, 0, loc), []string{"2023-11-05 01:30 EDT", "2023-11-06 01:30 EST"}},
+		{"*/30 * * * *", time.Date(2023, 11, 5, 0, 45, 0, 0, loc), []string{"2023-11-05 01:00 EDT", "2023-11-05 01:30 EDT", "2023-11-05 02:00 EST"}},
+	}
+
+	for _, test := range tests {
+		s, err := ParseCron(test.expr)
+		require.NoError(t, err)
+
+		var actual []string
+		next := test.base
+		for range test.expected {
+			next, err = s.Next(next)
+			require.NoError(t, err)
+			actual = append(actual, next.Format("2006-01-02 15:04 MST"))
+		}
+		assert.Equal(t, test.expected, actual, test.expr)
+
+		actual = actual[:0]
+		for range test.expected[1:] {
+			next, err = s.Prev(next)
+			require.NoError(t, err)
+			actual = append([]string{next.Format("2006-01-02 15:04 MST")}, actual...)
+		}
+		assert.Equal(t, test.expected[:len(test.expected)-1], actual, test.expr)
+	}
+
+	// Prev from the repeated hour still sees the first occurrence
+	s, err := ParseCron("45 * * * *")
+	require.NoError(t, err)
+	prev, err := s.Prev(time.Date(2023, 11, 5, 6, 10, 0, 0, time.UTC)) // 01:10 EST
+	require.NoError(t, err)
+	assert.Equal(t, "2023-11-05 01:45 EDT", prev.In(loc).Format("2006-01-02 15:04 MST"))
+}
+
+func TestScheduleNever(t *testing.T) {
+	s, err := ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+
+	_, err = s.Next(time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = s.Prev(time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestScheduleRare(t *testing.T) {
+	s, err := ParseCron("0 0 29 2 *")
+	require.NoError(t, err)
+
+	next, err := s.Next(time.Date(2096, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2104, 2, 29, 0, 0, 0, 0, time.UTC), next, "2100 is not a leap year")
+}
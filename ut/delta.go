@@ -0,0 +1,110 @@
+package ut
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var deltaMatch = regexp.MustCompile(`^([+-]?)(\d+)(business-days?|bd|days?|d|months?|mo|years?|y|hours?|h|min|minutes?|seconds?|s)$`)
+
+// maxDeltaSeconds is larger than any distance between MinTime and MaxTime, so
+// bigger deltas are out of range whatever they are applied to.
+var maxDeltaSeconds = MaxTime.Unix() - MinTime.Unix() + 1
+
+// ApplyDelta adds the given delta to t. A delta is a signed integer followed
+// by a unit, like "3day", "-2years", "1mo", "12h" or "+30min". Business days, like
+// "+5bd", skip weekends; HolidayTable.ApplyDelta skips holidays as well.
+// Results beyond MinTime and MaxTime return ErrOutOfRange.
+func ApplyDelta(t time.Time, delta string) (time.Time, error) {
+	return (*HolidayTable)(nil).ApplyDelta(t, delta)
+}
+
+// ApplyDelta adds the given delta to t, like the ApplyDelta function, with
+// business days skipping the holidays of the table too.
+func (h *HolidayTable) ApplyDelta(t time.Time, delta string) (time.Time, error) {
+	matches := deltaMatch.FindStringSubmatch(delta)
+	if len(matches) == 0 {
+		return t, valueError(ErrInvalidDelta, delta)
+	}
+
+	value, err := strconv.ParseInt(matches[2], 10, 64)
+	if err != nil {
+		return t, valueError(ErrOutOfRange, delta)
+	}
+	if matches[1] == "-" {
+		value = -value
+	}
+
+	if matches[3] == "bd" || strings.HasPrefix(matches[3], "business-day") {
+		if value > maxBusinessDays || value < -maxBusinessDays {
+			return t, valueError(ErrOutOfRange, delta)
+		}
+		if t, err = h.AddBusinessDays(t, int(value)); err != nil {
+			return t, err
+		}
+		if err := checkRange(t); err != nil {
+			return t, valueError(ErrOutOfRange, delta)
+		}
+		return t, nil
+	}
+
+	var unit int64 // seconds
+	switch matches[3] {
+	case "year", "years", "y":
+		unit = 366 * 86400
+	case "month", "months", "mo":
+		unit = 31 * 86400
+	case "day", "days", "d":
+		unit = 86400
+	case "hour", "hours", "h":
+		unit = 3600
+	case "min", "minute", "minutes":
+		unit = 60
+	case "second", "seconds", "s":
+		unit = 1
+	default:
+		return t, valueError(ErrInvalidDelta, delta)
+	}
+	if value > maxDeltaSeconds/unit || value < -maxDeltaSeconds/unit || int64(int(value)) != value {
+		return t, valueError(ErrOutOfRange, delta)
+	}
+
+	switch matches[3] {
+	case "year", "years", "y":
+		t = addMonths(t, 12*value)
+	case "month", "months", "mo":
+		t = addMonths(t, value)
+	case "day", "days", "d":
+		t = t.AddDate(0, 0, int(value))
+	default:
+		// time.Duration cannot hold more than 292 years, so add seconds
+		t = time.Unix(t.Unix()+value*unit, int64(t.Nanosecond())).In(t.Location())
+	}
+
+	if err := checkRange(t); err != nil {
+		return t, valueError(ErrOutOfRange, delta)
+	}
+
+	return t, nil
+}
+
+// addMonths adds n months to t, keeping the day of the month when the target
+// month has it and using its last day otherwise, like ISO 8601 durations:
+// January 31st plus a month is the end of February, not early March.
+func addMonths(t time.Time, n int64) time.Time {
+	y, m, d := t.Date()
+	months := int64(y)*12 + int64(m-1) + n
+	year := months / 12
+	if months%12 < 0 {
+		year--
+	}
+	month := time.Month(months-year*12) + 1
+
+	if last := time.Date(int(year), month+1, 0, 0, 0, 0, 0, time.UTC).Day(); d > last {
+		d = last
+	}
+	hour, min, sec := t.Clock()
+	return time.Date(int(year), month, d, hour, min, sec, t.Nanosecond(), t.Location())
+}
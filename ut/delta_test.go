@@ -0,0 +1,81 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplyDelta(t *testing.T) {
+	tests := []struct {
+		base     time.Time
+		delta    string
+		expected time.Time
+	}{
+		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "1year", time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "2year", time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "-2years", time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "-17y", time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "3day", time.Date(2017, 1, 5, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "14months", time.Date(2018, 3, 2, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "-1mo", time.Date(2016, 12, 2, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2023, 1, 31, 3, 4, 5, 0, time.UTC), "1mo", time.Date(2023, 2, 28, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2024, 3, 31, 3, 4, 5, 0, time.UTC), "-1mo", time.Date(2024, 2, 29, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2023, 1, 31, 3, 4, 5, 0, time.UTC), "-13mo", time.Date(2021, 12, 31, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2024, 2, 29, 3, 4, 5, 0, time.UTC), "1y", time.Date(2025, 2, 28, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "12hour", time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "1hour", time.Date(2017, 1, 2, 4, 4, 5, 0, time.UTC)},
+		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "1min", time.Date(2017, 1, 2, 3, 5, 5, 0, time.UTC)},
+		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "1s", time.Date(2017, 1, 2, 3, 4, 6, 0, time.UTC)},
+		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "+5bd", time.Date(2017, 1, 9, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "-1business-day", time.Date(2016, 12, 30, 3, 4, 5, 0, time.UTC)},
+		{time.Date(2017, 1, 7, 3, 4, 5, 0, time.UTC), "3business-days", time.Date(2017, 1, 11, 3, 4, 5, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		actual, err := ApplyDelta(test.base, test.delta)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, actual)
+	}
+}
+
+func TestApplyDeltaHolidays(t *testing.T) {
+	table, err := ParseHolidays(strings.NewReader("- 2017-01-03\n"), "")
+	require.NoError(t, err)
+
+	actual, err := table.ApplyDelta(time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "1bd")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2017, 1, 4, 3, 4, 5, 0, time.UTC), actual)
+}
+
+func TestApplyDeltaInvalid(t *testing.T) {
+	for _, delta := range []string{"", "3", "day", "3weeks", "++3day", "3b", "3business"} {
+		_, err := ApplyDelta(time.Now(), delta)
+		assert.ErrorIsf(t, err, ErrInvalidDelta, "expected error for delta %q", delta)
+	}
+}
+
+func TestApplyDeltaRange(t *testing.T) {
+	base := time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	actual, err := ApplyDelta(base, "1000000years")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(1002017, 1, 2, 3, 4, 5, 0, time.UTC), actual)
+
+	// beyond the 292 years of a time.Duration
+	actual, err = ApplyDelta(base, "-10000000h")
+	assert.NoError(t, err)
+	assert.Equal(t, base.Unix()-36000000000, actual.Unix())
+
+	for _, delta := range []string{"1000000000years", "-1000010000y", "400000000000days", "99999999999999999999s", "9223372036854775807h", "2000000bd"} {
+		_, err := ApplyDelta(base, delta)
+		assert.ErrorIsf(t, err, ErrOutOfRange, "expected error for delta %q", delta)
+	}
+
+	_, err = ApplyDelta(MaxTime, "1s")
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = ApplyDelta(MinTime, "-1s")
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
@@ -0,0 +1,822 @@
+package ut
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lsmoura/ut-cli/strftime"
+)
+
+// Dialect is a language of date format strings.
+type Dialect int
+
+const (
+	// DialectGo is the layout of Go's time package, like "2006-01-02".
+	DialectGo Dialect = iota
+	// DialectStrftime is C and Python's strftime, like "%Y-%m-%d".
+	DialectStrftime
+	// DialectJava is Java's DateTimeFormatter, like "yyyy-MM-dd".
+	DialectJava
+	// DialectMoment is Moment.js and Day.js, like "YYYY-MM-DD".
+	DialectMoment
+	// DialectDotNet is .NET custom date and time formats, like "yyyy-MM-dd".
+	DialectDotNet
+)
+
+var dialectNames = []struct {
+	name    string
+	aliases []string
+}{
+	DialectGo:       {"go", nil},
+	DialectStrftime: {"strftime", []string{"python", "c"}},
+	DialectJava:     {"java", nil},
+	DialectMoment:   {"moment", []string{"dayjs"}},
+	DialectDotNet:   {"dotnet", []string{".net", "csharp"}},
+}
+
+func (d Dialect) String() string {
+	return dialectNames[d].name
+}
+
+// DialectNames lists the names accepted by LookupDialect, aliases included.
+func DialectNames() []string {
+	var names []string
+	for _, d := range dialectNames {
+		names = append(names, d.name)
+		names = append(names, d.aliases...)
+	}
+	return names
+}
+
+// LookupDialect returns the format dialect with the given name.
+func LookupDialect(name string) (Dialect, error) {
+	for d, n := range dialectNames {
+		if strings.EqualFold(name, n.name) {
+			return Dialect(d), nil
+		}
+		for _, alias := range n.aliases {
+			if strings.EqualFold(name, alias) {
+				return Dialect(d), nil
+			}
+		}
+	}
+
+	return DialectGo, valueError(ErrUnknownDialect, name)
+}
+
+// formatField is a component of a date, in a given style.
+type formatField int
+
+const (
+	fieldLiteral formatField = iota
+	fieldYear
+	fieldYear2
+	fieldMonth
+	fieldMonthNoPad
+	fieldMonthShort
+	fieldMonthLong
+	fieldDay
+	fieldDayNoPad
+	fieldDaySpace
+	fieldYearDay
+	fieldYearDayNoPad
+	fieldWeekdayShort
+	fieldWeekdayLong
+	fieldWeekdayNumber // 0 is Sunday
+	fieldISOWeekday    // 1 is Monday, 7 is Sunday
+	fieldISOYear
+	fieldISOWeek
+	fieldISOWeekNoPad
+	fieldHour
+	fieldHourNoPad
+	fieldHour12
+	fieldHour12NoPad
+	fieldMinute
+	fieldMinuteNoPad
+	fieldSecond
+	fieldSecondNoPad
+	fieldFraction // digits of the second
+	fieldAMPM
+	fieldAMPMLower
+	fieldOffset       // -0700
+	fieldOffsetColon  // -07:00
+	fieldOffsetHours  // -07
+	fieldOffsetZ      // Z for UTC, -07:00 otherwise
+	fieldOffsetZShort // Z for UTC, -0700 otherwise
+	fieldZoneName
+	fieldUnixSeconds
+	fieldUnixMillis
+	fieldNative // a directive only its own dialect knows
+)
+
+var fieldDescriptions = map[formatField]string{
+	fieldLiteral:       "literal text",
+	fieldYear:          "year",
+	fieldYear2:         "2-digit year",
+	fieldMonth:         "month",
+	fieldMonthNoPad:    "unpadded month",
+	fieldMonthShort:    "abbreviated month name",
+	fieldMonthLong:     "month name",
+	fieldDay:           "day",
+	fieldDayNoPad:      "unpadded day",
+	fieldDaySpace:      "space-padded day",
+	fieldYearDay:       "day of the year",
+	fieldYearDayNoPad:  "unpadded day of the year",
+	fieldWeekdayShort:  "abbreviated weekday name",
+	fieldWeekdayLong:   "weekday name",
+	fieldWeekdayNumber: "weekday number from Sunday",
+	fieldISOWeekday:    "ISO weekday number",
+	fieldISOYear:       "ISO week-based year",
+	fieldISOWeek:       "ISO week",
+	fieldISOWeekNoPad:  "unpadded ISO week",
+	fieldHour:          "hour",
+	fieldHourNoPad:     "unpadded hour",
+	fieldHour12:        "12-hour clock hour",
+	fieldHour12NoPad:   "unpadded 12-hour clock hour",
+	fieldMinute:        "minute",
+	fieldMinuteNoPad:   "unpadded minute",
+	fieldSecond:        "second",
+	fieldSecondNoPad:   "unpadded second",
+	fieldFraction:      "fraction of second",
+	fieldAMPM:          "AM/PM",
+	fieldAMPMLower:     "am/pm",
+	fieldOffset:        "offset like -0700",
+	fieldOffsetColon:   "offset like -07:00",
+	fieldOffsetHours:   "offset like -07",
+	fieldOffsetZ:       "offset like Z or -07:00",
+	fieldOffsetZShort:  "offset like Z or -0700",
+	fieldZoneName:      "zone abbreviation",
+	fieldUnixSeconds:   "unix seconds",
+	fieldUnixMillis:    "unix milliseconds",
+}
+
+// formatToken is a piece of a format string: literal text or a field.
+type formatToken struct {
+	field   formatField
+	literal string // the literal text, or the field as written in the source
+	digits  int    // of fractions
+	trim    bool   // fractions without trailing zeros
+	native  Dialect
+}
+
+func (t formatToken) describe() string {
+	desc := fieldDescriptions[t.field]
+	switch t.field {
+	case fieldNative:
+		desc = t.native.String() + " only"
+	case fieldFraction:
+		desc = fmt.Sprintf("%d-digit %s", t.digits, desc)
+		if t.trim {
+			desc += " without trailing zeros"
+		}
+	}
+	return fmt.Sprintf("%q (%s)", t.literal, desc)
+}
+
+// ConvertFormat translates a format string between dialects. Tokens that
+// have no equivalent in the target dialect are reported in the error, an
+// ErrUnconvertibleFormat.
+func ConvertFormat(format string, from Dialect, to Dialect) (string, error) {
+	tokens, err := tokenizeFormat(format, from)
+	if err != nil {
+		return "", err
+	}
+
+	out, bad := renderFormat(tokens, to)
+	if len(bad) > 0 {
+		return out, fmt.Errorf("%w (no %s equivalent for %s)", valueError(ErrUnconvertibleFormat, format), to, strings.Join(bad, ", "))
+	}
+
+	return out, nil
+}
+
+// FormatLayout translates a format string in the given dialect to a layout
+// Format and ParseBase understand: a Go layout when possible, a strftime
+// format otherwise.
+func FormatLayout(format string, from Dialect) (string, error) {
+	if from == DialectGo || from == DialectStrftime {
+		if _, err := tokenizeFormat(format, from); err != nil {
+			return "", err
+		}
+		return format, nil
+	}
+
+	tokens, err := tokenizeFormat(format, from)
+	if err != nil {
+		return "", err
+	}
+	// Format tells the two apart by the "%" of strftime formats
+	layout, bad := renderFormat(tokens, DialectGo)
+	if len(bad) == 0 && !strings.Contains(layout, "%") {
+		return layout, nil
+	}
+	if s, sBad := renderFormat(tokens, DialectStrftime); len(sBad) == 0 && strings.Contains(s, "%") {
+		return s, nil
+	} else if len(sBad) > 0 {
+		bad = sBad
+	}
+
+	return "", fmt.Errorf("%w (no go or strftime equivalent for %s)", valueError(ErrUnconvertibleFormat, format), strings.Join(bad, ", "))
+}
+
+func tokenizeFormat(format string, d Dialect) ([]formatToken, error) {
+	var tokens []formatToken
+	var err error
+	switch d {
+	case DialectGo:
+		tokens = tokenizeGo(format)
+	case DialectStrftime:
+		tokens, err = tokenizeStrftime(format)
+	case DialectJava:
+		tokens, err = tokenizeJava(format)
+	case DialectMoment:
+		tokens = tokenizeMoment(format)
+	case DialectDotNet:
+		tokens, err = tokenizeDotNet(format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// merge adjacent literals
+	var merged []formatToken
+	for _, t := range tokens {
+		if n := len(merged); n > 0 && t.field == fieldLiteral && merged[n-1].field == fieldLiteral {
+			merged[n-1].literal += t.literal
+			continue
+		}
+		merged = append(merged, t)
+	}
+
+	return merged, nil
+}
+
+func renderFormat(tokens []formatToken, d Dialect) (string, []string) {
+	var b strings.Builder
+	var bad []string
+	for _, t := range tokens {
+		var s string
+		var ok bool
+		switch {
+		case t.field == fieldNative:
+			s, ok = t.literal, d == t.native
+		case d == DialectGo:
+			s, ok = renderGo(t, b.String())
+			if ok && t.field == fieldFraction {
+				// the separator is part of the fraction chunk
+				prefix := strings.TrimSuffix(b.String(), s[:1])
+				b.Reset()
+				b.WriteString(prefix)
+			}
+		case d == DialectStrftime:
+			s, ok = renderStrftime(t)
+		case d == DialectJava:
+			s, ok = renderJava(t)
+		case d == DialectMoment:
+			s, ok = renderMoment(t)
+		case d == DialectDotNet:
+			s, ok = renderDotNet(t)
+		}
+		if !ok {
+			bad = append(bad, t.describe())
+			s = t.literal
+		}
+		b.WriteString(s)
+	}
+
+	return b.String(), bad
+}
+
+// field returns a token for a field written as text.
+func field(f formatField, text string) formatToken {
+	return formatToken{field: f, literal: text}
+}
+
+func literal(text string) formatToken {
+	return formatToken{literal: text}
+}
+
+// goChunks are the fields of Go layouts, longest first where they share a
+// prefix, as time.Format reads them.
+var goChunks = []struct {
+	chunk string
+	field formatField
+}{
+	{"January", fieldMonthLong},
+	{"Jan", fieldMonthShort},
+	{"Monday", fieldWeekdayLong},
+	{"Mon", fieldWeekdayShort},
+	{"MST", fieldZoneName},
+	{"2006", fieldYear},
+	{"002", fieldYearDay},
+	{"01", fieldMonth},
+	{"02", fieldDay},
+	{"03", fieldHour12},
+	{"04", fieldMinute},
+	{"05", fieldSecond},
+	{"06", fieldYear2},
+	{"_2", fieldDaySpace},
+	{"15", fieldHour},
+	{"1", fieldMonthNoPad},
+	{"2", fieldDayNoPad},
+	{"3", fieldHour12NoPad},
+	{"4", fieldMinuteNoPad},
+	{"5", fieldSecondNoPad},
+	{"PM", fieldAMPM},
+	{"pm", fieldAMPMLower},
+	{"-07:00", fieldOffsetColon},
+	{"-0700", fieldOffset},
+	{"-07", fieldOffsetHours},
+	{"Z07:00", fieldOffsetZ},
+	{"Z0700", fieldOffsetZShort},
+}
+
+func tokenizeGo(layout string) []formatToken {
+	var tokens []formatToken
+	for i := 0; i < len(layout); {
+		if layout[i] == '.' || layout[i] == ',' {
+			j := i + 1
+			for j < len(layout) && layout[j] == layout[i+1] && (layout[j] == '0' || layout[j] == '9') {
+				j++
+			}
+			if digits := j - i - 1; digits > 0 && (j == len(layout) || layout[j] < '0' || layout[j] > '9') {
+				tokens = append(tokens, literal(layout[i:i+1]), formatToken{field: fieldFraction, literal: layout[i+1 : j], digits: digits, trim: layout[i+1] == '9'})
+				i = j
+				continue
+			}
+		}
+
+		matched := false
+		for _, c := range goChunks {
+			if !strings.HasPrefix(layout[i:], c.chunk) {
+				continue
+			}
+			// "Jan" followed by a lowercase letter is a word, not a month
+			if (c.chunk == "Jan" || c.chunk == "Mon") && i+3 < len(layout) && layout[i+3] >= 'a' && layout[i+3] <= 'z' {
+				continue
+			}
+			tokens = append(tokens, field(c.field, c.chunk))
+			i += len(c.chunk)
+			matched = true
+			break
+		}
+		if !matched {
+			tokens = append(tokens, literal(layout[i:i+1]))
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// renderGo renders a token as a Go layout chunk, given the layout so far.
+func renderGo(t formatToken, before string) (string, bool) {
+	switch t.field {
+	case fieldLiteral:
+		// Go layouts cannot escape literals that look like fields
+		for _, token := range tokenizeGo(t.literal) {
+			if token.field != fieldLiteral {
+				return "", false
+			}
+		}
+		return t.literal, true
+	case fieldFraction:
+		sep := ""
+		if strings.HasSuffix(before, ".") || strings.HasSuffix(before, ",") {
+			sep = before[len(before)-1:]
+		}
+		if sep == "" || t.digits > 9 {
+			return "", false
+		}
+		digit := "0"
+		if t.trim {
+			digit = "9"
+		}
+		return sep + strings.Repeat(digit, t.digits), true
+	}
+
+	for _, c := range goChunks {
+		if c.field == t.field {
+			return c.chunk, true
+		}
+	}
+
+	return "", false
+}
+
+var strftimeDirectives = map[string]formatField{
+	"%Y":  fieldYear,
+	"%y":  fieldYear2,
+	"%m":  fieldMonth,
+	"%-m": fieldMonthNoPad,
+	"%b":  fieldMonthShort,
+	"%B":  fieldMonthLong,
+	"%d":  fieldDay,
+	"%-d": fieldDayNoPad,
+	"%j":  fieldYearDay,
+	"%-j": fieldYearDayNoPad,
+	"%a":  fieldWeekdayShort,
+	"%A":  fieldWeekdayLong,
+	"%w":  fieldWeekdayNumber,
+	"%u":  fieldISOWeekday,
+	"%G":  fieldISOYear,
+	"%V":  fieldISOWeek,
+	"%-V": fieldISOWeekNoPad,
+	"%H":  fieldHour,
+	"%-H": fieldHourNoPad,
+	"%I":  fieldHour12,
+	"%-I": fieldHour12NoPad,
+	"%M":  fieldMinute,
+	"%-M": fieldMinuteNoPad,
+	"%S":  fieldSecond,
+	"%-S": fieldSecondNoPad,
+	"%p":  fieldAMPM,
+	"%z":  fieldOffset,
+	"%Z":  fieldZoneName,
+}
+
+// strftimeAliases are directives read like others, but never written.
+var strftimeAliases = map[string]formatField{
+	"%e": fieldDaySpace,
+	"%h": fieldMonthShort,
+	"%s": fieldUnixSeconds,
+}
+
+// strftimeExpansions are directives standing for several others.
+var strftimeExpansions = map[string]string{
+	"%x": "%m/%d/%y",
+	"%X": "%H:%M:%S",
+	"%D": "%m/%d/%y",
+	"%F": "%Y-%m-%d",
+	"%T": "%H:%M:%S",
+	"%R": "%H:%M",
+}
+
+// strftimeNative are directives of Strftime without an equivalent elsewhere.
+var strftimeNative = []string{"%U", "%-U", "%J", "%Ej", "%Eh", "%EC", "%Ey", "%EY"}
+
+func tokenizeStrftime(format string) ([]formatToken, error) {
+	var tokens []formatToken
+	pieces := strftime.StrTimeTokens(format)
+	if len(strings.Join(pieces, "")) != len(format) {
+		return nil, fmt.Errorf("%w (trailing %%)", valueError(ErrUnconvertibleFormat, format))
+	}
+
+	for _, piece := range pieces {
+		if expansion, ok := strftimeExpansions[piece]; ok {
+			expanded, _ := tokenizeStrftime(expansion)
+			tokens = append(tokens, expanded...)
+			continue
+		}
+
+		switch {
+		case !strings.HasPrefix(piece, "%"):
+			tokens = append(tokens, literal(piece))
+			continue
+		case piece == "%%":
+			tokens = append(tokens, literal("%"))
+			continue
+		case piece == "%f":
+			tokens = append(tokens, formatToken{field: fieldFraction, literal: piece, digits: 6})
+			continue
+		}
+
+		f, ok := strftimeDirectives[piece]
+		if !ok {
+			f, ok = strftimeAliases[piece]
+		}
+		if !ok {
+			for _, native := range strftimeNative {
+				if piece == native {
+					f, ok = fieldNative, true
+				}
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w (unsupported directive %q)", valueError(ErrUnconvertibleFormat, format), piece)
+		}
+		tokens = append(tokens, formatToken{field: f, literal: piece, native: DialectStrftime})
+	}
+
+	return tokens, nil
+}
+
+func renderStrftime(t formatToken) (string, bool) {
+	switch t.field {
+	case fieldLiteral:
+		return strings.ReplaceAll(t.literal, "%", "%%"), true
+	case fieldFraction:
+		return "%f", t.digits == 6 && !t.trim
+	}
+	for directive, f := range strftimeDirectives {
+		if f == t.field {
+			return directive, true
+		}
+	}
+	return "", false
+}
+
+// javaLetters maps runs of pattern letters of DateTimeFormatter to fields.
+var javaLetters = map[string]formatField{
+	"yyyy": fieldYear, "uuuu": fieldYear, "y": fieldYear, "u": fieldYear,
+	"yy": fieldYear2, "uu": fieldYear2,
+	"M": fieldMonthNoPad, "MM": fieldMonth, "MMM": fieldMonthShort, "MMMM": fieldMonthLong,
+	"L": fieldMonthNoPad, "LL": fieldMonth, "LLL": fieldMonthShort, "LLLL": fieldMonthLong,
+	"d": fieldDayNoPad, "dd": fieldDay,
+	"D": fieldYearDayNoPad, "DDD": fieldYearDay,
+	"E": fieldWeekdayShort, "EE": fieldWeekdayShort, "EEE": fieldWeekdayShort, "EEEE": fieldWeekdayLong,
+	"YYYY": fieldISOYear, "ww": fieldISOWeek, "w": fieldISOWeekNoPad,
+	"H": fieldHourNoPad, "HH": fieldHour,
+	"h": fieldHour12NoPad, "hh": fieldHour12,
+	"m": fieldMinuteNoPad, "mm": fieldMinute,
+	"s": fieldSecondNoPad, "ss": fieldSecond,
+	"a": fieldAMPM,
+	"Z": fieldOffset, "ZZ": fieldOffset, "ZZZ": fieldOffset, "xx": fieldOffset,
+	"xxx": fieldOffsetColon, "x": fieldOffsetHours,
+	"XXX": fieldOffsetZ, "ZZZZZ": fieldOffsetZ, "XX": fieldOffsetZShort,
+	"z": fieldZoneName, "zz": fieldZoneName, "zzz": fieldZoneName,
+}
+
+// javaOutput is the pattern written for each field, among the ones read.
+var javaOutput = map[formatField]string{
+	fieldYear: "yyyy", fieldYear2: "yy", fieldMonth: "MM", fieldMonthNoPad: "M", fieldMonthShort: "MMM",
+	fieldMonthLong: "MMMM", fieldDay: "dd", fieldDayNoPad: "d", fieldYearDay: "DDD", fieldYearDayNoPad: "D",
+	fieldWeekdayShort: "EEE", fieldWeekdayLong: "EEEE", fieldISOYear: "YYYY", fieldISOWeek: "ww", fieldISOWeekNoPad: "w",
+	fieldHour: "HH", fieldHourNoPad: "H", fieldHour12: "hh", fieldHour12NoPad: "h", fieldMinute: "mm",
+	fieldMinuteNoPad: "m", fieldSecond: "ss", fieldSecondNoPad: "s", fieldAMPM: "a", fieldOffset: "xx",
+	fieldOffsetColon: "xxx", fieldOffsetHours: "x", fieldOffsetZ: "XXX", fieldOffsetZShort: "XX", fieldZoneName: "zzz",
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func hasLetter(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if isLetter(s[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func tokenizeJava(pattern string) ([]formatToken, error) {
+	var tokens []formatToken
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == '\'':
+			if strings.HasPrefix(pattern[i:], "''") {
+				tokens = append(tokens, literal("'"))
+				i += 2
+				continue
+			}
+			var text strings.Builder
+			j := i + 1
+			for ; j < len(pattern); j++ {
+				if pattern[j] != '\'' {
+					text.WriteByte(pattern[j])
+					continue
+				}
+				if j+1 < len(pattern) && pattern[j+1] == '\'' {
+					text.WriteByte('\'')
+					j++
+					continue
+				}
+				break
+			}
+			if j >= len(pattern) {
+				return nil, fmt.Errorf("%w (unterminated quote)", valueError(ErrUnconvertibleFormat, pattern))
+			}
+			tokens = append(tokens, literal(text.String()))
+			i = j + 1
+		case strings.IndexByte("[]{}#", c) >= 0:
+			return nil, fmt.Errorf("%w (unsupported optional section or reserved %q)", valueError(ErrUnconvertibleFormat, pattern), c)
+		case isLetter(c):
+			j := i
+			for j < len(pattern) && pattern[j] == c {
+				j++
+			}
+			run := pattern[i:j]
+			i = j
+			if c == 'S' {
+				tokens = append(tokens, formatToken{field: fieldFraction, literal: run, digits: len(run)})
+				continue
+			}
+			f, ok := javaLetters[run]
+			if !ok {
+				return nil, fmt.Errorf("%w (unsupported pattern %q)", valueError(ErrUnconvertibleFormat, pattern), run)
+			}
+			tokens = append(tokens, field(f, run))
+		default:
+			tokens = append(tokens, literal(pattern[i:i+1]))
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+func renderJava(t formatToken) (string, bool) {
+	switch t.field {
+	case fieldLiteral:
+		if !hasLetter(t.literal) && !strings.ContainsAny(t.literal, "'[]{}#") {
+			return t.literal, true
+		}
+		if t.literal == "'" {
+			return "''", true
+		}
+		return "'" + strings.ReplaceAll(t.literal, "'", "''") + "'", true
+	case fieldFraction:
+		return strings.Repeat("S", t.digits), !t.trim && t.digits <= 9
+	}
+	s, ok := javaOutput[t.field]
+	return s, ok
+}
+
+// momentTokens are the tokens of Moment.js and Day.js formats, longest first
+// where they share a prefix.
+var momentTokens = []struct {
+	token string
+	field formatField
+}{
+	// ordinals, quarters and locale weeks have no equivalent elsewhere
+	{"Mo", fieldNative}, {"DDDo", fieldNative}, {"Do", fieldNative}, {"do", fieldNative}, {"Wo", fieldNative},
+	{"wo", fieldNative}, {"Qo", fieldNative}, {"Q", fieldNative}, {"ww", fieldNative}, {"w", fieldNative},
+	{"gggg", fieldNative}, {"gg", fieldNative}, {"GG", fieldNative}, {"e", fieldNative}, {"kk", fieldNative},
+	{"k", fieldNative},
+	{"YYYY", fieldYear}, {"YY", fieldYear2},
+	{"MMMM", fieldMonthLong}, {"MMM", fieldMonthShort}, {"MM", fieldMonth}, {"M", fieldMonthNoPad},
+	{"DDDD", fieldYearDay}, {"DDD", fieldYearDayNoPad}, {"DD", fieldDay}, {"D", fieldDayNoPad},
+	{"dddd", fieldWeekdayLong}, {"ddd", fieldWeekdayShort}, {"d", fieldWeekdayNumber}, {"E", fieldISOWeekday},
+	{"GGGG", fieldISOYear}, {"WW", fieldISOWeek}, {"W", fieldISOWeekNoPad},
+	{"HH", fieldHour}, {"H", fieldHourNoPad}, {"hh", fieldHour12}, {"h", fieldHour12NoPad},
+	{"mm", fieldMinute}, {"m", fieldMinuteNoPad}, {"ss", fieldSecond}, {"s", fieldSecondNoPad},
+	{"A", fieldAMPM}, {"a", fieldAMPMLower},
+	{"ZZ", fieldOffset}, {"Z", fieldOffsetColon}, {"zz", fieldZoneName}, {"z", fieldZoneName},
+	{"X", fieldUnixSeconds}, {"x", fieldUnixMillis},
+}
+
+func tokenizeMoment(format string) []formatToken {
+	var tokens []formatToken
+	for i := 0; i < len(format); {
+		if format[i] == '[' {
+			if end := strings.IndexByte(format[i:], ']'); end > 0 {
+				tokens = append(tokens, literal(format[i+1:i+end]))
+				i += end + 1
+				continue
+			}
+		}
+		if format[i] == 'S' {
+			j := i
+			for j < len(format) && format[j] == 'S' {
+				j++
+			}
+			tokens = append(tokens, formatToken{field: fieldFraction, literal: format[i:j], digits: j - i})
+			i = j
+			continue
+		}
+
+		matched := false
+		for _, t := range momentTokens {
+			if strings.HasPrefix(format[i:], t.token) {
+				tokens = append(tokens, formatToken{field: t.field, literal: t.token, native: DialectMoment})
+				i += len(t.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			tokens = append(tokens, literal(format[i:i+1]))
+			i++
+		}
+	}
+
+	return tokens
+}
+
+func renderMoment(t formatToken) (string, bool) {
+	switch t.field {
+	case fieldLiteral:
+		// brackets escape letters, and themselves as "[[]"
+		var b strings.Builder
+		for i := 0; i < len(t.literal); {
+			j := i
+			for j < len(t.literal) && (isLetter(t.literal[j]) || t.literal[j] == '[') {
+				j++
+			}
+			if j == i {
+				b.WriteByte(t.literal[i])
+				i++
+				continue
+			}
+			b.WriteString("[" + t.literal[i:j] + "]")
+			i = j
+		}
+		return b.String(), true
+	case fieldFraction:
+		return strings.Repeat("S", t.digits), !t.trim && t.digits <= 9
+	}
+	for _, m := range momentTokens {
+		if m.field == t.field {
+			return m.token, true
+		}
+	}
+	return "", false
+}
+
+// dotNetSpecifiers are the custom format specifiers of .NET, longest first
+// where they share a prefix.
+var dotNetSpecifiers = []struct {
+	specifier string
+	field     formatField
+}{
+	{"yyyy", fieldYear}, {"yy", fieldYear2},
+	{"MMMM", fieldMonthLong}, {"MMM", fieldMonthShort}, {"MM", fieldMonth}, {"M", fieldMonthNoPad},
+	{"dddd", fieldWeekdayLong}, {"ddd", fieldWeekdayShort}, {"dd", fieldDay}, {"d", fieldDayNoPad},
+	{"HH", fieldHour}, {"H", fieldHourNoPad}, {"hh", fieldHour12}, {"h", fieldHour12NoPad},
+	{"mm", fieldMinute}, {"m", fieldMinuteNoPad}, {"ss", fieldSecond}, {"s", fieldSecondNoPad},
+	{"tt", fieldAMPM},
+	{"zzz", fieldOffsetColon}, {"zz", fieldOffsetHours}, {"K", fieldOffsetZ},
+}
+
+func tokenizeDotNet(format string) ([]formatToken, error) {
+	var tokens []formatToken
+	for i := 0; i < len(format); {
+		c := format[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(format[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("%w (unterminated quote)", valueError(ErrUnconvertibleFormat, format))
+			}
+			tokens = append(tokens, literal(format[i+1:i+1+end]))
+			i += end + 2
+			continue
+		case c == '\\' && i+1 < len(format):
+			tokens = append(tokens, literal(format[i+1:i+2]))
+			i += 2
+			continue
+		case c == 'f' || c == 'F':
+			j := i
+			for j < len(format) && format[j] == c {
+				j++
+			}
+			if j-i > 7 {
+				return nil, fmt.Errorf("%w (more than 7 %c)", valueError(ErrUnconvertibleFormat, format), c)
+			}
+			tokens = append(tokens, formatToken{field: fieldFraction, literal: format[i:j], digits: j - i, trim: c == 'F'})
+			i = j
+			continue
+		case c == '%' && i+1 < len(format):
+			// "%d" is the single specifier d
+			i++
+			continue
+		}
+
+		matched := false
+		for _, s := range dotNetSpecifiers {
+			if strings.HasPrefix(format[i:], s.specifier) {
+				tokens = append(tokens, field(s.field, s.specifier))
+				i += len(s.specifier)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			if strings.IndexByte("gtyz", c) >= 0 {
+				return nil, fmt.Errorf("%w (unsupported specifier %q)", valueError(ErrUnconvertibleFormat, format), c)
+			}
+			tokens = append(tokens, literal(format[i:i+1]))
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+func renderDotNet(t formatToken) (string, bool) {
+	switch t.field {
+	case fieldLiteral:
+		var b strings.Builder
+		for i := 0; i < len(t.literal); i++ {
+			if isLetter(t.literal[i]) || strings.IndexByte("'\"\\%", t.literal[i]) >= 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(t.literal[i])
+		}
+		return b.String(), true
+	case fieldFraction:
+		letter := "f"
+		if t.trim {
+			letter = "F"
+		}
+		return strings.Repeat(letter, t.digits), t.digits <= 7
+	}
+	for _, s := range dotNetSpecifiers {
+		if s.field == t.field {
+			return s.specifier, true
+		}
+	}
+	return "", false
+}
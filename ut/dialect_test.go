@@ -0,0 +1,110 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestConvertFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		from   Dialect
+		to     Dialect
+		want   string
+	}{
+		{"%Y-%m-%d %H:%M:%S", DialectStrftime, DialectGo, "2006-01-02 15:04:05"},
+		{"%Y-%m-%dT%H:%M:%S.%f%z", DialectStrftime, DialectGo, "2006-01-02T15:04:05.000000-0700"},
+		{"%F %T", DialectStrftime, DialectJava, "yyyy-MM-dd HH:mm:ss"},
+		{"%e %b (%%)", DialectStrftime, DialectGo, "_2 Jan (%)"},
+		{"Today is %A", DialectStrftime, DialectMoment, "[Today] [is] dddd"},
+		{"Today is %A", DialectStrftime, DialectDotNet, `\T\o\d\a\y \i\s dddd`},
+		{"2006-01-02T15:04:05.000Z07:00", DialectGo, DialectJava, "yyyy-MM-dd'T'HH:mm:ss.SSSXXX"},
+		{"2006-01-02T15:04:05.000000-0700", DialectGo, DialectStrftime, "%Y-%m-%dT%H:%M:%S.%f%z"},
+		{"Mon Jan 02 15:04:05 2006", DialectGo, DialectStrftime, "%a %b %d %H:%M:%S %Y"},
+		{"2006-01-02 15:04:05.999", DialectGo, DialectDotNet, "yyyy-MM-dd HH:mm:ss.FFF"},
+		{"yyyy-MM-dd'T'HH:mm:ss.SSSXXX", DialectJava, DialectGo, "2006-01-02T15:04:05.000Z07:00"},
+		{"h:mm a, 'o''clock'", DialectJava, DialectMoment, "h:mm A, [o]'[clock]"},
+		{"dddd, MMMM D YYYY h:mm A", DialectMoment, DialectStrftime, "%A, %B %-d %Y %-I:%M %p"},
+		{"YYYY-MM-DD [at] HH:mm", DialectMoment, DialectGo, "2006-01-02 at 15:04"},
+		{"yyyy-MM-dd HH:mm:ss.fff zzz", DialectDotNet, DialectGo, "2006-01-02 15:04:05.000 -07:00"},
+		{"yyyy-MM-dd\\THH:mm:ssK", DialectDotNet, DialectJava, "yyyy-MM-dd'T'HH:mm:ssXXX"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from.String()+" "+tt.format, func(t *testing.T) {
+			got, err := ConvertFormat(tt.format, tt.from, tt.to)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertFormatUnconvertible(t *testing.T) {
+	tests := []struct {
+		format string
+		from   Dialect
+		to     Dialect
+		tokens string
+	}{
+		{"%Y week %U", DialectStrftime, DialectGo, `"%U" (strftime only)`},
+		{"x", DialectMoment, DialectJava, `"x" (unix milliseconds)`},
+		{"MMMM Do", DialectMoment, DialectJava, `"Do" (moment only)`},
+		{"yyyy-MM-dd HH:mm:ss.SSS", DialectJava, DialectStrftime, `"SSS" (3-digit fraction of second)`},
+		{"Monday at 3pm", DialectGo, DialectJava, `"pm" (am/pm)`},
+		{"yyyy 'Jan'", DialectJava, DialectGo, `" Jan" (literal text)`},
+		{"HH:mm:ss fff", DialectDotNet, DialectGo, `"fff" (3-digit fraction of second)`},
+		{"Mon Jan _2", DialectGo, DialectStrftime, `"_2" (space-padded day)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from.String()+" "+tt.format, func(t *testing.T) {
+			_, err := ConvertFormat(tt.format, tt.from, tt.to)
+			assert.ErrorIs(t, err, ErrUnconvertibleFormat)
+			assert.ErrorContains(t, err, tt.tokens)
+		})
+	}
+
+	for _, format := range []string{"%Y %", "%Y %k"} {
+		_, err := ConvertFormat(format, DialectStrftime, DialectGo)
+		assert.ErrorIs(t, err, ErrUnconvertibleFormat, format)
+	}
+	_, err := ConvertFormat("yyyy 'T", DialectJava, DialectGo)
+	assert.ErrorIs(t, err, ErrUnconvertibleFormat, "unterminated quote")
+}
+
+func TestFormatLayout(t *testing.T) {
+	tm := time.Date(2023, 4, 5, 17, 50, 44, 123456789, time.UTC)
+
+	tests := []struct {
+		format string
+		from   Dialect
+		want   string
+	}{
+		{"yyyy-MM-dd'T'HH:mm:ss.SSSXXX", DialectJava, "2023-04-05T17:50:44.123Z"},
+		{"dd MMM yyyy 'week' ww", DialectJava, "05 Apr 2023 week 14"},
+		{"YYYY-MM-DD HH:mm:ss", DialectMoment, "2023-04-05 17:50:44"},
+		{"ddd, DD MMM YYYY HH:mm:ss ZZ", DialectMoment, "Wed, 05 Apr 2023 17:50:44 +0000"},
+		{"%Y %U", DialectStrftime, "2023 14"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			layout, err := FormatLayout(tt.format, tt.from)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, Format(tm, layout))
+		})
+	}
+
+	_, err := FormatLayout("yyyy SS", DialectJava)
+	assert.ErrorIs(t, err, ErrUnconvertibleFormat)
+}
+
+func TestLookupDialect(t *testing.T) {
+	for name, want := range map[string]Dialect{"go": DialectGo, "Python": DialectStrftime, "dayjs": DialectMoment, ".NET": DialectDotNet, "java": DialectJava} {
+		d, err := LookupDialect(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, d, name)
+	}
+
+	_, err := LookupDialect("perl")
+	assert.ErrorIs(t, err, ErrUnknownDialect)
+}
@@ -0,0 +1,7 @@
+// Package ut implements the timestamp handling behind the ut command line
+// tool: parsing of zone offsets and epochs, delta arithmetic, truncation and
+// formatting.
+//
+// Errors returned by this package wrap one of the sentinel values declared in
+// errors.go, so callers can inspect them with errors.Is.
+package ut
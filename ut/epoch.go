@@ -0,0 +1,217 @@
+package ut
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Epoch is a family of timestamps: an origin and a unit. Values of the Unix,
+// NTP, GPS, Cocoa and TAI families count seconds and honor the precision; the
+// other families have a fixed unit and ignore it. NTP32 values are the 32-bit
+// seconds of NTP packets, which wrap every 136 years. GPS and TAI values count
+// leap seconds, using the table returned by LeapSeconds.
+type Epoch int
+
+const (
+	EpochUnix   Epoch = iota // seconds since 1970-01-01 UTC
+	EpochLDAP                // Windows FILETIME and Active Directory: 100ns intervals since 1601-01-01 UTC
+	EpochDotNet              // .NET ticks: 100ns intervals since 0001-01-01 UTC
+	EpochNTP                 // seconds since 1900-01-01 UTC, counted across NTP eras
+	EpochGPS                 // seconds since 1980-01-06 UTC, including leap seconds
+	EpochExcel               // days since 1899-12-30, the 1900 date system of spreadsheets
+	EpochCocoa               // CFAbsoluteTime: seconds since 2001-01-01 UTC
+	EpochWebKit              // Chrome and WebKit: microseconds since 1601-01-01 UTC
+	EpochTAI                 // seconds since 1970-01-01 TAI, as used by PTP
+	EpochNTP32               // 32-bit NTP seconds, era 0 from 1968 to 2036 then era 1 up to 2104
+)
+
+const (
+	ntpEra         int64 = 1 << 32 // seconds in an NTP era
+	gpsEpochUnix   int64 = 315964800
+	excelEpochUnix int64 = -2209161600
+)
+
+type epochSpec struct {
+	name    string
+	aliases []string
+	origin  int64 // unix seconds
+	unit    int64 // nanoseconds, 0 to use the precision
+	tai     int64 // offset from TAI of a scale counting leap seconds, 0 for UTC
+}
+
+var epochSpecs = map[Epoch]epochSpec{
+	EpochUnix:   {"unix", []string{"posix"}, 0, 0, 0},
+	EpochLDAP:   {"ldap", []string{"filetime", "windows", "ad", "nt"}, -11644473600, 100, 0},
+	EpochDotNet: {"dotnet", []string{"ticks", ".net"}, -62135596800, 100, 0},
+	EpochNTP:    {"ntp", nil, -2208988800, 0, 0},
+	EpochGPS:    {"gps", nil, gpsEpochUnix, 0, gpsTAIOffset},
+	EpochExcel:  {"excel", []string{"lotus", "spreadsheet"}, excelEpochUnix, 86400 * int64(time.Second), 0},
+	EpochCocoa:  {"cocoa", []string{"mac", "apple", "cfabsolutetime", "coredata"}, 978307200, 0, 0},
+	EpochWebKit: {"webkit", []string{"chrome"}, -11644473600, 1000, 0},
+	EpochTAI:    {"tai", []string{"ptp"}, 0, 0, 0},
+	EpochNTP32:  {"ntp32", []string{"sntp"}, -2208988800, int64(time.Second), 0},
+}
+
+func (e Epoch) String() string {
+	if spec, ok := epochSpecs[e]; ok {
+		return spec.name
+	}
+
+	return fmt.Sprintf("Epoch(%d)", int(e))
+}
+
+// epochOrder lists the epoch families as they are documented, variants next
+// to their family.
+var epochOrder = []Epoch{EpochUnix, EpochLDAP, EpochDotNet, EpochNTP, EpochNTP32, EpochGPS, EpochExcel, EpochCocoa, EpochWebKit, EpochTAI}
+
+// Epochs lists the epoch families.
+func Epochs() []Epoch {
+	return append([]Epoch(nil), epochOrder...)
+}
+
+// EpochNames lists the names accepted by LookupEpoch, aliases included.
+func EpochNames() []string {
+	var names []string
+	for e := EpochUnix; e <= EpochNTP32; e++ {
+		names = append(names, epochSpecs[e].name)
+		names = append(names, epochSpecs[e].aliases...)
+	}
+	return names
+}
+
+// LookupEpoch returns the epoch family with the given name. An empty name
+// means the unix epoch.
+func LookupEpoch(name string) (Epoch, error) {
+	if name == "" {
+		return EpochUnix, nil
+	}
+
+	lower := strings.ToLower(name)
+	for e, spec := range epochSpecs {
+		if spec.name == lower {
+			return e, nil
+		}
+		for _, alias := range spec.aliases {
+			if alias == lower {
+				return e, nil
+			}
+		}
+	}
+
+	return EpochUnix, valueError(ErrUnknownEpoch, name)
+}
+
+// LeapSeconds reports whether values of the epoch family count leap seconds.
+func (e Epoch) LeapSeconds() bool {
+	return e == EpochGPS || e == EpochTAI
+}
+
+func precisionUnit(p Precision) int64 {
+	switch p {
+	case Millisecond:
+		return int64(time.Millisecond)
+	case Microsecond:
+		return int64(time.Microsecond)
+	case Nanosecond:
+		return 1
+	}
+
+	return int64(time.Second)
+}
+
+// unit returns the duration of one unit of the epoch, in nanoseconds.
+func (e Epoch) unit(p Precision) int64 {
+	if u := epochSpecs[e].unit; u != 0 {
+		return u
+	}
+	return precisionUnit(p)
+}
+
+var (
+	billion = big.NewInt(int64(time.Second))
+
+	// rough bounds of the unix seconds of parsed values, so they can be
+	// handed to time.Unix safely before the exact check against MinTime and
+	// MaxTime
+	minSeconds = big.NewInt(MinTime.Unix() - 86400)
+	maxSeconds = big.NewInt(MaxTime.Unix() + 86400)
+)
+
+// Parse converts a value of the epoch family to a time. Values may be
+// negative, fractional and larger than an int64; they are converted exactly,
+// down to the nanosecond. Values beyond MinTime and MaxTime return
+// ErrOutOfRange.
+func (e Epoch) Parse(value string, p Precision) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	r, ok := new(big.Rat).SetString(value)
+	if !ok || strings.ContainsAny(value, "/eE") {
+		return time.Time{}, valueError(ErrInvalidTimestamp, value)
+	}
+
+	if e == EpochExcel && r.Cmp(big.NewRat(60, 1)) < 0 {
+		// Spreadsheets count 1900-02-29, which did not exist, so serial
+		// dates before March 1900 are one day off.
+		r.Add(r, big.NewRat(1, 1))
+	}
+
+	// nanoseconds since the origin, rounded down
+	r.Mul(r, new(big.Rat).SetInt64(e.unit(p)))
+	nanos := new(big.Int).Div(r.Num(), r.Denom())
+
+	seconds, nsec := new(big.Int).DivMod(nanos, billion, new(big.Int))
+	if e == EpochNTP32 {
+		// RFC 4330: values with the high bit clear are in era 1, after 2036
+		if seconds.Sign() < 0 || seconds.Cmp(big.NewInt(ntpEra)) >= 0 {
+			return time.Time{}, rangeError(value)
+		}
+		if seconds.Cmp(big.NewInt(ntpEra/2)) < 0 {
+			seconds.Add(seconds, big.NewInt(ntpEra))
+		}
+	}
+	seconds.Add(seconds, big.NewInt(epochSpecs[e].origin))
+	if seconds.Cmp(minSeconds) < 0 || seconds.Cmp(maxSeconds) > 0 {
+		return time.Time{}, rangeError(value)
+	}
+
+	sec := seconds.Int64()
+	if e.LeapSeconds() {
+		sec = LeapSeconds().fromTAI(sec + epochSpecs[e].tai)
+	}
+
+	t := time.Unix(sec, nsec.Int64())
+	if t.Before(MinTime) || t.After(MaxTime) {
+		return time.Time{}, rangeError(value)
+	}
+
+	return t, nil
+}
+
+// Format returns t as a value of the epoch family. Integer families are
+// rounded down, Excel serial dates are printed with a fractional part when
+// needed.
+func (e Epoch) Format(t time.Time, p Precision) string {
+	sec := t.Unix() - epochSpecs[e].origin
+	if e.LeapSeconds() {
+		sec += LeapSeconds().TAIOffset(t) - epochSpecs[e].tai
+	}
+
+	nanos := new(big.Int).Mul(big.NewInt(sec), billion)
+	nanos.Add(nanos, big.NewInt(int64(t.Nanosecond())))
+
+	if e == EpochExcel {
+		if t.Unix() < -2203891200 { // 1900-03-01
+			nanos.Sub(nanos, big.NewInt(86400*int64(time.Second)))
+		}
+		return decimalString(new(big.Rat).SetFrac(nanos, big.NewInt(e.unit(p))))
+	}
+
+	// big.Int implements Euclidean division, which rounds down for positive divisors
+	q := new(big.Int).Div(nanos, big.NewInt(e.unit(p)))
+	if e == EpochNTP32 {
+		q.Mod(q, big.NewInt(ntpEra))
+	}
+	return q.String()
+}
@@ -0,0 +1,214 @@
+package ut
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookupEpoch(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Epoch
+	}{
+		{"", EpochUnix},
+		{"unix", EpochUnix},
+		{"LDAP", EpochLDAP},
+		{"filetime", EpochLDAP},
+		{"ticks", EpochDotNet},
+		{"ntp", EpochNTP},
+		{"gps", EpochGPS},
+		{"excel", EpochExcel},
+		{"cocoa", EpochCocoa},
+		{"mac", EpochCocoa},
+		{"chrome", EpochWebKit},
+		{"tai", EpochTAI},
+		{"ptp", EpochTAI},
+		{"ntp32", EpochNTP32},
+		{"sntp", EpochNTP32},
+	}
+
+	for _, test := range tests {
+		e, err := LookupEpoch(test.name)
+		require.NoErrorf(t, err, "looking up %q", test.name)
+		assert.Equal(t, test.expected, e)
+	}
+
+	_, err := LookupEpoch("mayan")
+	assert.ErrorIs(t, err, ErrUnknownEpoch)
+	assert.Contains(t, EpochNames(), "webkit")
+	assert.Len(t, Epochs(), len(epochSpecs))
+}
+
+func TestEpochReferencePoints(t *testing.T) {
+	ref := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	tests := []struct {
+		epoch     Epoch
+		precision Precision
+		time      time.Time
+		value     string
+	}{
+		{EpochUnix, Second, ref, "1680717044"},
+		{EpochUnix, Millisecond, ref, "1680717044000"},
+		{EpochLDAP, Second, time.Unix(0, 0), "116444736000000000"},
+		{EpochLDAP, Second, ref, "133251906440000000"},
+		{EpochLDAP, Second, time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC), "0"},
+		{EpochDotNet, Second, time.Unix(0, 0), "621355968000000000"},
+		{EpochDotNet, Second, ref, "638163138440000000"},
+		{EpochDotNet, Second, time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), "0"},
+		{EpochNTP, Second, time.Unix(0, 0), "2208988800"},
+		{EpochNTP, Second, ref, "3889705844"},
+		{EpochNTP, Second, time.Date(2036, 2, 7, 6, 28, 16, 0, time.UTC), "4294967296"}, // first second of era 1
+		{EpochNTP32, Second, ref, "3889705844"},
+		{EpochNTP32, Millisecond, ref, "3889705844"},
+		{EpochNTP32, Second, time.Date(1968, 1, 20, 3, 14, 8, 0, time.UTC), "2147483648"}, // first second of the window
+		{EpochNTP32, Second, time.Date(2036, 2, 7, 6, 28, 16, 0, time.UTC), "0"},          // first second of era 1
+		{EpochNTP32, Second, time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC), "123010304"},
+		{EpochGPS, Second, time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC), "0"},
+		{EpochGPS, Second, time.Date(2011, 9, 14, 1, 46, 25, 0, time.UTC), "1000000000"},
+		{EpochGPS, Second, ref, "1364752262"},
+		{EpochGPS, Millisecond, ref, "1364752262000"},
+		{EpochExcel, Second, time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC), "45021"},
+		{EpochExcel, Second, time.Date(2023, 4, 5, 12, 0, 0, 0, time.UTC), "45021.5"},
+		{EpochExcel, Second, time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), "1"},
+		{EpochExcel, Second, time.Date(1900, 2, 28, 0, 0, 0, 0, time.UTC), "59"},
+		{EpochExcel, Second, time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC), "61"},
+		{EpochCocoa, Second, time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC), "0"},
+		{EpochCocoa, Second, ref, "702409844"},
+		{EpochWebKit, Second, ref, "13325190644000000"},
+		{EpochTAI, Second, time.Unix(0, 0), "10"},
+		{EpochTAI, Second, time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), "1483228837"},
+		{EpochTAI, Second, time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC), "1483228835"},
+		{EpochTAI, Millisecond, ref, "1680717081000"},
+	}
+
+	for _, test := range tests {
+		assert.Equalf(t, test.value, test.epoch.Format(test.time, test.precision), "formatting %s as %s", test.time, test.epoch)
+
+		parsed, err := test.epoch.Parse(test.value, test.precision)
+		require.NoErrorf(t, err, "parsing %s value %q", test.epoch, test.value)
+		assert.Truef(t, test.time.Equal(parsed), "parsing %s value %q: expected %s, got %s", test.epoch, test.value, test.time, parsed.UTC())
+	}
+}
+
+func TestEpochParseFractional(t *testing.T) {
+	parsed, err := EpochCocoa.Parse("702409844.25", Second)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 4, 5, 17, 50, 44, 250000000, time.UTC), parsed.UTC())
+
+	parsed, err = EpochExcel.Parse("45021.74356481481", Second)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC), parsed.UTC().Round(time.Millisecond))
+
+	parsed, err = EpochUnix.Parse("-1.5", Second)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(1969, 12, 31, 23, 59, 58, 500000000, time.UTC), parsed.UTC())
+}
+
+func TestEpochParseInvalid(t *testing.T) {
+	for _, value := range []string{"", "abc", "1/2", "1e9", "12:00"} {
+		_, err := EpochUnix.Parse(value, Second)
+		assert.ErrorIsf(t, err, ErrInvalidTimestamp, "parsing %q", value)
+	}
+
+	_, err := EpochUnix.Parse("99999999999999999999", Second)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+
+	for _, value := range []string{"-1", "4294967296"} {
+		_, err := EpochNTP32.Parse(value, Second)
+		assert.ErrorIsf(t, err, ErrOutOfRange, "parsing 32-bit NTP value %q", value)
+	}
+}
+
+func TestEpochFormatRoundsDown(t *testing.T) {
+	before := time.Date(1969, 12, 31, 23, 59, 59, 500000000, time.UTC)
+
+	assert.Equal(t, "-1", EpochUnix.Format(before, Second))
+	assert.Equal(t, "-500", EpochUnix.Format(before, Millisecond))
+}
+
+func FuzzEpochParse(f *testing.F) {
+	for _, seed := range []string{
+		"0", "-1", "1680717044", "1.5", "-0.000000001",
+		"9223372036854775807", "-9223372036854775808", "9223372036854775808",
+		"31556889832780799", "31556889832780800", "-31557014135596800", "-31557014135596801",
+		"99999999999999999999999999999999", "1e3", "1/2", "",
+	} {
+		f.Add(seed, uint8(EpochUnix), uint8(Second))
+		f.Add(seed, uint8(EpochGPS), uint8(Nanosecond))
+		f.Add(seed, uint8(EpochDotNet), uint8(Second))
+	}
+
+	f.Fuzz(func(t *testing.T, value string, epoch uint8, precision uint8) {
+		e := Epoch(epoch % uint8(EpochNTP32+1))
+		p := Precision(precision % uint8(Nanosecond+1))
+
+		parsed, err := e.Parse(value, p)
+		if err != nil {
+			if !errors.Is(err, ErrOutOfRange) && !errors.Is(err, ErrInvalidTimestamp) {
+				t.Fatalf("parsing %s value %q: unexpected error %v", e, value, err)
+			}
+			return
+		}
+		if parsed.Before(MinTime) || parsed.After(MaxTime) {
+			t.Fatalf("parsing %s value %q: %s is out of range", e, value, parsed.UTC())
+		}
+
+		// integer values of the integer families survive a round trip, except
+		// the leap seconds, which map to the following second
+		want, ok := new(big.Int).SetString(strings.TrimPrefix(strings.TrimSpace(value), "+"), 10)
+		if !ok || e == EpochExcel || e.LeapSeconds() {
+			return
+		}
+		if got := e.Format(parsed, p); got != want.String() {
+			t.Fatalf("round trip of %s value %q: got %s", e, value, got)
+		}
+	})
+}
+
+func FuzzToEpoch(f *testing.F) {
+	f.Add(int64(0), int64(0), uint8(Second))
+	f.Add(int64(-1), int64(999999999), uint8(Millisecond))
+	f.Add(int64(9223372036), int64(854775807), uint8(Nanosecond))
+	f.Add(int64(-9223372037), int64(145224192), uint8(Nanosecond))
+	f.Add(MaxTime.Unix(), int64(999999999), uint8(Second))
+	f.Add(MinTime.Unix(), int64(0), uint8(Microsecond))
+
+	f.Fuzz(func(t *testing.T, sec int64, nsec int64, precision uint8) {
+		if sec < MinTime.Unix() || sec > MaxTime.Unix() || nsec < 0 || nsec >= int64(time.Second) {
+			return
+		}
+		tm := time.Unix(sec, nsec)
+		p := Precision(precision % uint8(Nanosecond+1))
+
+		value, err := ToEpoch(tm, p)
+		first, last := p.Range()
+		if tm.Before(first) || tm.After(last) {
+			if !errors.Is(err, ErrOutOfRange) {
+				t.Fatalf("converting %s to %s: expected ErrOutOfRange, got %v", tm.UTC(), p, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("converting %s to %s: %v", tm.UTC(), p, err)
+		}
+
+		if formatted := EpochUnix.Format(tm, p); formatted != strconv.FormatInt(value, 10) {
+			t.Fatalf("converting %s to %s: ToEpoch gives %d, Format %s", tm.UTC(), p, value, formatted)
+		}
+
+		back, err := FromEpoch(value, p)
+		if err != nil {
+			t.Fatalf("converting %d %ss back: %v", value, p, err)
+		}
+		if d := tm.Sub(back); d < 0 || d >= time.Duration(precisionUnit(p)) {
+			t.Fatalf("converting %s to %s and back: got %s", tm.UTC(), p, back.UTC())
+		}
+	})
+}
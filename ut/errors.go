@@ -0,0 +1,67 @@
+package ut
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidTimestamp is returned when an epoch value cannot be parsed.
+	ErrInvalidTimestamp = errors.New("invalid timestamp")
+	// ErrOutOfRange is returned when a value cannot be represented.
+	ErrOutOfRange = errors.New("value out of range")
+	// ErrUnknownPrecision is returned for precision names that are not recognized.
+	ErrUnknownPrecision = errors.New("unknown precision")
+	// ErrInvalidDelta is returned when a delta expression cannot be parsed.
+	ErrInvalidDelta = errors.New("invalid delta")
+	// ErrUnknownTruncate is returned for unknown truncate options.
+	ErrUnknownTruncate = errors.New("unknown truncate option")
+	// ErrUnknownZone is returned when a zone name or offset cannot be resolved.
+	ErrUnknownZone = errors.New("unknown time zone")
+	// ErrUnknownEpoch is returned for epoch family names that are not recognized.
+	ErrUnknownEpoch = errors.New("unknown epoch")
+	// ErrUnknownIDKind is returned for identifier kinds that are not recognized.
+	ErrUnknownIDKind = errors.New("unknown ID kind")
+	// ErrInvalidID is returned when an identifier does not match its kind.
+	ErrInvalidID = errors.New("invalid ID")
+	// ErrInvalidBase is returned when a base time cannot be parsed with the given layout.
+	ErrInvalidBase = errors.New("invalid base time")
+	// ErrUnknownCalendar is returned for calendar names that are not recognized.
+	ErrUnknownCalendar = errors.New("unknown calendar")
+	// ErrInvalidLeapSeconds is returned when a leap second table cannot be parsed.
+	ErrInvalidLeapSeconds = errors.New("invalid leap second table")
+	// ErrInvalidCron is returned when a cron expression cannot be parsed.
+	ErrInvalidCron = errors.New("invalid cron expression")
+	// ErrInvalidInterval is returned when an ISO 8601 interval or duration cannot be parsed.
+	ErrInvalidInterval = errors.New("invalid interval")
+	// ErrInvalidBucketSize is returned when a bucket size cannot be parsed.
+	ErrInvalidBucketSize = errors.New("invalid bucket size")
+	// ErrUnknownLogFormat is returned for log format names that are not recognized.
+	ErrUnknownLogFormat = errors.New("unknown log format")
+	// ErrInvalidHolidays is returned when a holiday file cannot be parsed.
+	ErrInvalidHolidays = errors.New("invalid holiday file")
+	// ErrUnknownDialect is returned for format dialect names that are not recognized.
+	ErrUnknownDialect = errors.New("unknown format dialect")
+	// ErrUnconvertibleFormat is returned when a format string cannot be translated to another dialect.
+	ErrUnconvertibleFormat = errors.New("unconvertible format")
+	// ErrInvalidFormatPresets is returned when a format preset file cannot be parsed.
+	ErrInvalidFormatPresets = errors.New("invalid format preset file")
+)
+
+// ValueError reports the input value that caused one of the sentinel errors.
+type ValueError struct {
+	Err   error
+	Value string
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("%s: %q", e.Err, e.Value)
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+func valueError(err error, value string) error {
+	return &ValueError{Err: err, Value: value}
+}
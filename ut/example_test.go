@@ -0,0 +1,87 @@
+package ut_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lsmoura/ut-cli/ut"
+)
+
+func ExampleParseEpoch() {
+	t, err := ut.ParseEpoch("1588059756238", ut.Millisecond)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(t.UTC())
+	// Output: 2020-04-28 07:42:36.238 +0000 UTC
+}
+
+func ExampleToEpoch() {
+	t := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	seconds, _ := ut.ToEpoch(t, ut.Second)
+	millis, _ := ut.ToEpoch(t, ut.Millisecond)
+	fmt.Println(seconds)
+	fmt.Println(millis)
+
+	// nanoseconds only fit in an int64 until 2262
+	_, err := ut.ToEpoch(time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC), ut.Nanosecond)
+	fmt.Println(errors.Is(err, ut.ErrOutOfRange))
+	// Output:
+	// 1680717044
+	// 1680717044000
+	// true
+}
+
+func ExampleApplyDelta() {
+	t := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	t, err := ut.ApplyDelta(t, "-3days")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(t)
+	// Output: 2023-04-02 17:50:44 +0000 UTC
+}
+
+func ExampleLoadZone() {
+	loc, err := ut.LoadZone("-3:00")
+	if err != nil {
+		panic(err)
+	}
+
+	t := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+	fmt.Println(t.In(loc))
+	// Output: 2023-04-05 14:50:44 -0300 (-300)
+}
+
+func ExampleTruncate_Truncate() {
+	t := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	t, _ = ut.TruncateHour.Truncate(t)
+	fmt.Println(t)
+	// Output: 2023-04-05 17:00:00 +0000 UTC
+}
+
+func ExampleFormat() {
+	t := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	fmt.Println(ut.Format(t, "%Y-%m-%d %H:%M"))
+	fmt.Println(ut.Format(t, time.Kitchen))
+	// Output:
+	// 2023-04-05 17:50
+	// 5:50PM
+}
+
+func Example_errors() {
+	_, err := ut.ParsePrecision("fortnight")
+
+	fmt.Println(errors.Is(err, ut.ErrUnknownPrecision))
+	fmt.Println(err)
+	// Output:
+	// true
+	// unknown precision: "fortnight"
+}
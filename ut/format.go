@@ -0,0 +1,116 @@
+package ut
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lsmoura/ut-cli/strftime"
+)
+
+// Format formats t using the given layout. Layouts containing a percent sign
+// are treated as strftime formats, preset names as their layout, everything
+// else as a Go time layout. An empty layout uses the default time.Time
+// representation.
+func Format(t time.Time, layout string) string {
+	if layout == "" {
+		return fmt.Sprintf("%s", t)
+	}
+	if p, ok := LookupFormatPreset(layout); ok {
+		layout = p.Layout
+		if p.UTC {
+			t = t.UTC()
+		}
+	}
+
+	if strings.Contains(layout, "%") {
+		return strftime.Strftime(t, layout)
+	}
+
+	return t.Format(layout)
+}
+
+// LayoutFromStrftime converts a time format string with percent values to a go time format string
+func LayoutFromStrftime(format string) string {
+	pieces := strftime.StrTimeTokens(format)
+
+	var goFormat string
+	for _, piece := range pieces {
+		switch piece {
+		case "%Y":
+			goFormat += "2006"
+		case "%m":
+			goFormat += "01"
+		case "%d":
+			goFormat += "02"
+		case "%H":
+			goFormat += "15"
+		case "%M":
+			goFormat += "04"
+		case "%S":
+			goFormat += "05"
+		case "%z":
+			goFormat += "Z07:00"
+		case "%Z":
+			goFormat += "MST"
+		case "%%":
+			goFormat += "%"
+		default:
+			goFormat += piece
+		}
+	}
+
+	return goFormat
+}
+
+// ParseBase resolves a base time relative to now. It understands the keywords
+// "now", "today", "yesterday" and "tomorrow"; any other value is parsed with
+// the given layout, which defaults to RFC3339 and may be a strftime format or
+// a preset name.
+//
+// Strftime formats accept leap seconds, like 23:59:60 UTC, when the leap
+// second table lists them. A time.Time cannot hold a leap second, so it is
+// resolved to the instant right after it, the way unix time counts it.
+func ParseBase(value string, layout string, now time.Time) (time.Time, error) {
+	switch value {
+	case "now", "today", "":
+		return now, nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), nil
+	}
+
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	if p, ok := LookupFormatPreset(layout); ok {
+		layout = p.Layout
+	}
+
+	var t time.Time
+	var err error
+	if strings.Contains(layout, "%") {
+		t, err = strftime.Strptime(value, layout)
+
+		var leapErr *strftime.LeapSecondError
+		if errors.As(err, &leapErr) {
+			if !LeapSeconds().IsLeapSecond(leapErr.Time) {
+				return time.Time{}, fmt.Errorf("%w (no leap second at %s60 UTC)", valueError(ErrInvalidBase, value), leapErr.Time.UTC().Add(-time.Second).Format("2006-01-02 15:04:"))
+			}
+			t, err = leapErr.Time, nil
+		}
+	} else {
+		t, err = time.Parse(layout, value)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w (expected layout %q)", valueError(ErrInvalidBase, value), layout)
+	}
+
+	if t.IsZero() {
+		return now, nil
+	}
+
+	return t, nil
+}
@@ -0,0 +1,63 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	ref := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	assert.Equal(t, "2023-04-05 17:50:44 +0000 UTC", Format(ref, ""))
+	assert.Equal(t, "2023-04-05", Format(ref, "%Y-%m-%d"))
+	assert.Equal(t, "2023-04-05T17:50:44Z", Format(ref, time.RFC3339))
+}
+
+func TestLayoutFromStrftime(t *testing.T) {
+	assert.Equal(t, "2006-01-02 15:04:05", LayoutFromStrftime("%Y-%m-%d %H:%M:%S"))
+	assert.Equal(t, "100%", LayoutFromStrftime("100%%"))
+}
+
+func TestParseBase(t *testing.T) {
+	now := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	tests := []struct {
+		base     string
+		layout   string
+		expected time.Time
+	}{
+		{"", "", now},
+		{"now", "", now},
+		{"yesterday", "", now.AddDate(0, 0, -1)},
+		{"tomorrow", "", now.AddDate(0, 0, 1)},
+		{"2023-01-02T03:04:05Z", "", time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"2023-01-02", "%Y-%m-%d", time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		actual, err := ParseBase(test.base, test.layout, now)
+		require.NoErrorf(t, err, "parsing %q", test.base)
+		assert.True(t, test.expected.Equal(actual), "parsing %q: expected %s, got %s", test.base, test.expected, actual)
+	}
+
+	_, err := ParseBase("not a date", "", now)
+	assert.ErrorIs(t, err, ErrInvalidBase)
+}
+
+func TestParseBaseLeapSecond(t *testing.T) {
+	now := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	actual, err := ParseBase("2016-12-31 23:59:60", "%Y-%m-%d %H:%M:%S", now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1483228800), actual.Unix())
+
+	actual, err = ParseBase("2017-01-01T08:59:60+0900", "%Y-%m-%dT%H:%M:%S%z", now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1483228800), actual.Unix())
+
+	_, err = ParseBase("2016-12-30 23:59:60", "%Y-%m-%d %H:%M:%S", now)
+	assert.ErrorIs(t, err, ErrInvalidBase)
+	assert.ErrorContains(t, err, "no leap second at 2016-12-30 23:59:60 UTC")
+}
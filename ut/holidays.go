@@ -0,0 +1,298 @@
+package ut
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxBusinessDays bounds business-day deltas, which are counted day by day.
+const maxBusinessDays = 1000000
+
+// HolidayTable holds the days on which business is closed, on top of
+// Saturdays and Sundays. A nil table has no holidays.
+type HolidayTable struct {
+	dates  map[date]string // single days
+	yearly map[date]string // days repeating every year, with year 0
+}
+
+// date is a day of the civil calendar.
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// ParseHolidays reads holidays from an iCalendar (ICS) file or from a YAML
+// list. YAML files may hold a list per region, like
+//
+//	us:
+//	  - 2024-07-04
+//	  - date: 12-25
+//	    name: Christmas Day
+//
+// in which case region selects the list; it may be empty when there is a
+// single one. Dates without a year repeat every year.
+func ParseHolidays(r io.Reader, region string) (*HolidayTable, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("BEGIN:VCALENDAR")) {
+		if region != "" {
+			return nil, fmt.Errorf("%w: regions are only supported in YAML files", ErrInvalidHolidays)
+		}
+		return parseICS(data)
+	}
+
+	return parseHolidayYAML(data, region)
+}
+
+// holidayEntry is an item of a YAML holiday list: a date, or a date and a name.
+type holidayEntry struct {
+	Date string `yaml:"date"`
+	Name string `yaml:"name"`
+}
+
+func (e *holidayEntry) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		e.Date = node.Value
+		return nil
+	}
+
+	type plain holidayEntry
+	return node.Decode((*plain)(e))
+}
+
+func parseHolidayYAML(data []byte, region string) (*HolidayTable, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidHolidays, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("%w: empty file", ErrInvalidHolidays)
+	}
+
+	var entries []holidayEntry
+	switch root := doc.Content[0]; root.Kind {
+	case yaml.SequenceNode:
+		if region != "" {
+			return nil, fmt.Errorf("%w: no regions in file, found a single list", ErrInvalidHolidays)
+		}
+		if err := root.Decode(&entries); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidHolidays, err)
+		}
+	case yaml.MappingNode:
+		var regions map[string][]holidayEntry
+		if err := root.Decode(&regions); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidHolidays, err)
+		}
+
+		var names []string
+		for name := range regions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if region == "" && len(names) == 1 {
+			region = names[0]
+		}
+		list, ok := regions[region]
+		if !ok {
+			if region == "" {
+				return nil, fmt.Errorf("%w: no region given (regions are %s)", ErrInvalidHolidays, strings.Join(names, ", "))
+			}
+			return nil, fmt.Errorf("%w: unknown region %q (regions are %s)", ErrInvalidHolidays, region, strings.Join(names, ", "))
+		}
+		entries = list
+	default:
+		return nil, fmt.Errorf("%w: expected a list of dates or a map of regions", ErrInvalidHolidays)
+	}
+
+	table := &HolidayTable{dates: map[date]string{}, yearly: map[date]string{}}
+	for _, e := range entries {
+		if t, err := time.Parse("2006-01-02", e.Date); err == nil {
+			table.dates[civilDate(t)] = e.Name
+		} else if t, err := time.Parse("01-02", e.Date); err == nil {
+			table.yearly[date{0, t.Month(), t.Day()}] = e.Name
+		} else {
+			return nil, fmt.Errorf("%w: invalid date %q (expected YYYY-MM-DD or MM-DD)", ErrInvalidHolidays, e.Date)
+		}
+	}
+
+	return table, nil
+}
+
+// parseICS reads the all-day events of an iCalendar file. Events last until
+// their DTEND, exclusive, and may repeat every year with "RRULE:FREQ=YEARLY".
+func parseICS(data []byte) (*HolidayTable, error) {
+	table := &HolidayTable{dates: map[date]string{}, yearly: map[date]string{}}
+
+	// unfold continuation lines, which start with a space or a tab
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var inEvent, yearly bool
+	var start, end time.Time
+	var summary string
+	for n, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ";") // drop parameters, like VALUE=DATE
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent, yearly = true, false
+			start, end, summary = time.Time{}, time.Time{}, ""
+		case !inEvent:
+			continue
+		case name == "DTSTART" || name == "DTEND":
+			if len(value) < 8 {
+				return nil, fmt.Errorf("%w: line %d: %q", ErrInvalidHolidays, n+1, line)
+			}
+			t, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %q", ErrInvalidHolidays, n+1, line)
+			}
+			if name == "DTSTART" {
+				start = t
+			} else {
+				end = t
+			}
+		case name == "SUMMARY":
+			summary = strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(value)
+		case name == "RRULE":
+			if value != "FREQ=YEARLY" && value != "FREQ=YEARLY;INTERVAL=1" {
+				return nil, fmt.Errorf("%w: line %d: unsupported recurrence %q", ErrInvalidHolidays, n+1, value)
+			}
+			yearly = true
+		case name == "END" && value == "VEVENT":
+			inEvent = false
+			if start.IsZero() {
+				return nil, fmt.Errorf("%w: line %d: event without DTSTART", ErrInvalidHolidays, n+1)
+			}
+			if !end.After(start) {
+				end = start.AddDate(0, 0, 1)
+			}
+			if end.Sub(start) > 366*24*time.Hour {
+				return nil, fmt.Errorf("%w: line %d: event longer than a year", ErrInvalidHolidays, n+1)
+			}
+			for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+				if yearly {
+					table.yearly[date{0, d.Month(), d.Day()}] = summary
+				} else {
+					table.dates[civilDate(d)] = summary
+				}
+			}
+		}
+	}
+
+	return table, nil
+}
+
+func civilDate(t time.Time) date {
+	y, m, d := t.Date()
+	return date{y, m, d}
+}
+
+// Holiday returns the name of the holiday on the date of t, if it is one.
+func (h *HolidayTable) Holiday(t time.Time) (string, bool) {
+	if h == nil {
+		return "", false
+	}
+
+	d := civilDate(t)
+	if name, ok := h.dates[d]; ok {
+		return name, true
+	}
+	name, ok := h.yearly[date{0, d.month, d.day}]
+	return name, ok
+}
+
+// IsBusinessDay reports whether the date of t is neither a weekend nor a holiday.
+func (h *HolidayTable) IsBusinessDay(t time.Time) bool {
+	if wd := t.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return false
+	}
+	_, holiday := h.Holiday(t)
+	return !holiday
+}
+
+// AddBusinessDays moves t by n business days, forwards or backwards, keeping
+// the time of day. Starting from a weekend or a holiday, +1 is the next
+// business day and -1 the previous one.
+func (h *HolidayTable) AddBusinessDays(t time.Time, n int) (time.Time, error) {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	days := 0
+	for n > 0 {
+		var err error
+		var skipped int
+		if day, skipped, err = h.nextBusinessDay(day.AddDate(0, 0, step), step); err != nil {
+			return t, err
+		}
+		days += step * (skipped + 1)
+		n--
+	}
+
+	return t.AddDate(0, 0, days), nil
+}
+
+// NextBusinessDay returns t when its date is a business day, or the start
+// of the first business day after it.
+func (h *HolidayTable) NextBusinessDay(t time.Time) (time.Time, error) {
+	if h.IsBusinessDay(t) {
+		return t, nil
+	}
+
+	y, m, d := t.Date()
+	_, skipped, err := h.nextBusinessDay(time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC), 1)
+	if err != nil {
+		return t, err
+	}
+
+	return time.Date(y, m, d+1+skipped, 0, 0, 0, 0, t.Location()), nil
+}
+
+// nextBusinessDay returns the first business day from day on, in the
+// direction of step, and the number of days skipped to reach it.
+func (h *HolidayTable) nextBusinessDay(day time.Time, step int) (time.Time, int, error) {
+	// more holidays in a row than the table holds means every day is one
+	limit := 7
+	if h != nil {
+		limit += len(h.dates) + 2*len(h.yearly)
+	}
+
+	for skipped := 0; skipped < limit; skipped++ {
+		if h.IsBusinessDay(day) {
+			return day, skipped, nil
+		}
+		day = day.AddDate(0, 0, step)
+	}
+
+	return day, 0, fmt.Errorf("%w: no business days left in the holiday table", ErrOutOfRange)
+}
@@ -0,0 +1,151 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testHolidaysYAML = `
+us:
+  - 2024-07-04
+  - date: 12-25
+    name: Christmas Day
+br:
+  - date: 2024-11-15
+    name: Republic Day
+`
+
+const testHolidaysICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20241224\r\n" +
+	"DTEND;VALUE=DATE:20241227\r\n" +
+	"SUMMARY:Christmas\\, Boxing Day\r\n" +
+	"  and Eve\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20200101\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"SUMMARY:New Year\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseHolidaysYAML(t *testing.T) {
+	table, err := ParseHolidays(strings.NewReader(testHolidaysYAML), "us")
+	require.NoError(t, err)
+
+	name, ok := table.Holiday(time.Date(2031, 12, 25, 10, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "Christmas Day", name)
+	_, ok = table.Holiday(time.Date(2024, 7, 4, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	_, ok = table.Holiday(time.Date(2025, 7, 4, 0, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+	_, ok = table.Holiday(time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC))
+	assert.False(t, ok, "holiday of another region")
+
+	table, err = ParseHolidays(strings.NewReader("- 2024-01-01\n- 05-01\n"), "")
+	require.NoError(t, err)
+	_, ok = table.Holiday(time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+}
+
+func TestParseHolidaysICS(t *testing.T) {
+	table, err := ParseHolidays(strings.NewReader(testHolidaysICS), "")
+	require.NoError(t, err)
+
+	name, ok := table.Holiday(time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "Christmas, Boxing Day and Eve", name)
+	_, ok = table.Holiday(time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC))
+	assert.False(t, ok, "DTEND is exclusive")
+
+	name, ok = table.Holiday(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "New Year", name)
+}
+
+func TestParseHolidaysInvalid(t *testing.T) {
+	tests := []struct {
+		data   string
+		region string
+	}{
+		{testHolidaysYAML, ""},
+		{testHolidaysYAML, "fr"},
+		{"- 2024-01-01\n", "us"},
+		{"- 2024-13-01\n", ""},
+		{"- [1, 2]\n", ""},
+		{"us: 3\n", "us"},
+		{"", ""},
+		{testHolidaysICS, "us"},
+		{strings.Replace(testHolidaysICS, "FREQ=YEARLY", "FREQ=MONTHLY", 1), ""},
+		{strings.Replace(testHolidaysICS, "20241224", "2024", 1), ""},
+	}
+
+	for _, test := range tests {
+		_, err := ParseHolidays(strings.NewReader(test.data), test.region)
+		assert.ErrorIsf(t, err, ErrInvalidHolidays, "expected error for %q in region %q", test.data, test.region)
+	}
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	table, err := ParseHolidays(strings.NewReader(testHolidaysICS), "")
+	require.NoError(t, err)
+
+	tests := []struct {
+		holidays *HolidayTable
+		base     time.Time
+		days     int
+		expected time.Time
+	}{
+		// 2024-12-20 is a Friday
+		{nil, time.Date(2024, 12, 20, 9, 30, 0, 0, time.UTC), 1, time.Date(2024, 12, 23, 9, 30, 0, 0, time.UTC)},
+		{nil, time.Date(2024, 12, 20, 9, 30, 0, 0, time.UTC), 5, time.Date(2024, 12, 27, 9, 30, 0, 0, time.UTC)},
+		{nil, time.Date(2024, 12, 20, 9, 30, 0, 0, time.UTC), 0, time.Date(2024, 12, 20, 9, 30, 0, 0, time.UTC)},
+		{nil, time.Date(2024, 12, 21, 9, 30, 0, 0, time.UTC), 1, time.Date(2024, 12, 23, 9, 30, 0, 0, time.UTC)},
+		{nil, time.Date(2024, 12, 21, 9, 30, 0, 0, time.UTC), -1, time.Date(2024, 12, 20, 9, 30, 0, 0, time.UTC)},
+		{nil, time.Date(2024, 12, 23, 9, 30, 0, 0, time.UTC), -1, time.Date(2024, 12, 20, 9, 30, 0, 0, time.UTC)},
+		{table, time.Date(2024, 12, 20, 9, 30, 0, 0, time.UTC), 2, time.Date(2024, 12, 27, 9, 30, 0, 0, time.UTC)},
+		{table, time.Date(2024, 12, 27, 9, 30, 0, 0, time.UTC), 3, time.Date(2025, 1, 2, 9, 30, 0, 0, time.UTC)},
+		{table, time.Date(2025, 1, 2, 9, 30, 0, 0, time.UTC), -4, time.Date(2024, 12, 23, 9, 30, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		actual, err := test.holidays.AddBusinessDays(test.base, test.days)
+		assert.NoError(t, err)
+		assert.Equalf(t, test.expected, actual, "%s %+d business days", test.base, test.days)
+	}
+}
+
+func TestNextBusinessDay(t *testing.T) {
+	table, err := ParseHolidays(strings.NewReader(testHolidaysICS), "")
+	require.NoError(t, err)
+
+	friday := time.Date(2024, 12, 20, 9, 30, 0, 0, time.UTC)
+	actual, err := table.NextBusinessDay(friday)
+	assert.NoError(t, err)
+	assert.Equal(t, friday, actual)
+
+	actual, err = table.NextBusinessDay(time.Date(2024, 12, 24, 9, 30, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC), actual)
+
+	actual, err = table.NextBusinessDay(time.Date(2025, 1, 1, 9, 30, 0, 0, time.FixedZone("", 3600)))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.FixedZone("", 3600)), actual)
+}
+
+func TestNextBusinessDayNone(t *testing.T) {
+	var data strings.Builder
+	for d := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); d.Year() == 2024; d = d.AddDate(0, 0, 1) {
+		data.WriteString(d.Format("- 01-02\n"))
+	}
+	table, err := ParseHolidays(strings.NewReader(data.String()), "")
+	require.NoError(t, err)
+
+	_, err = table.NextBusinessDay(time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
@@ -0,0 +1,375 @@
+package ut
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IDKind is a family of identifiers embedding a timestamp.
+type IDKind int
+
+const (
+	IDAuto      IDKind = iota
+	IDTwitter          // Twitter snowflake: milliseconds since 2010-11-04 above the lowest 22 bits
+	IDDiscord          // Discord snowflake: milliseconds since 2015-01-01 above the lowest 22 bits
+	IDInstagram        // Instagram sharded ID: milliseconds since 2011-08-24 above the lowest 23 bits
+	IDUUIDv1           // UUID version 1: 100ns intervals since 1582-10-15, low bits first
+	IDUUIDv6           // UUID version 6: 100ns intervals since 1582-10-15, high bits first
+	IDUUIDv7           // UUID version 7: unix milliseconds in the first 48 bits
+	IDULID             // ULID: unix milliseconds in the first 48 bits, Crockford base32
+	IDKSUID            // KSUID: seconds since 2014-05-13 in the first 32 bits, base62
+	IDObjectID         // MongoDB ObjectId: unix seconds in the first 32 bits, hex
+)
+
+const (
+	twitterEpochMs   int64 = 1288834974657
+	discordEpochMs   int64 = 1420070400000
+	instagramEpochMs int64 = 1314220021721
+	ksuidEpoch       int64 = 1400000000
+	// gregorianOffset is the number of 100ns intervals between 1582-10-15 and 1970-01-01
+	gregorianOffset int64 = 122192928000000000
+)
+
+var idKindNames = map[IDKind][]string{
+	IDAuto:      {"auto"},
+	IDTwitter:   {"twitter", "snowflake", "x"},
+	IDDiscord:   {"discord"},
+	IDInstagram: {"instagram"},
+	IDUUIDv1:    {"uuidv1", "uuid1"},
+	IDUUIDv6:    {"uuidv6", "uuid6"},
+	IDUUIDv7:    {"uuidv7", "uuid7"},
+	IDULID:      {"ulid"},
+	IDKSUID:     {"ksuid"},
+	IDObjectID:  {"objectid", "mongo", "bson"},
+}
+
+func (k IDKind) String() string {
+	if names, ok := idKindNames[k]; ok {
+		return names[0]
+	}
+
+	return fmt.Sprintf("IDKind(%d)", int(k))
+}
+
+// IDKindNames lists the names accepted by LookupIDKind, aliases included.
+func IDKindNames() []string {
+	var names []string
+	for k := IDAuto; k <= IDObjectID; k++ {
+		names = append(names, idKindNames[k]...)
+	}
+	return names
+}
+
+// LookupIDKind returns the ID kind with the given name. An empty name means
+// auto-detection.
+func LookupIDKind(name string) (IDKind, error) {
+	if name == "" {
+		return IDAuto, nil
+	}
+
+	lower := strings.ToLower(name)
+	for k, names := range idKindNames {
+		for _, n := range names {
+			if n == lower {
+				return k, nil
+			}
+		}
+	}
+
+	return IDAuto, valueError(ErrUnknownIDKind, name)
+}
+
+var (
+	uuidMatch     = regexp.MustCompile(`^(?i)\{?[0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12}\}?$`)
+	objectIDMatch = regexp.MustCompile(`^(?i)[0-9a-f]{24}$`)
+	ulidMatch     = regexp.MustCompile(`^(?i)[0-7][0-9a-hjkmnp-tv-z]{25}$`)
+	ksuidMatch    = regexp.MustCompile(`^[0-9A-Za-z]{27}$`)
+	numericMatch  = regexp.MustCompile(`^\d{1,20}$`)
+)
+
+// DetectIDKind guesses the kind of an identifier from its shape. Numeric IDs
+// are reported as Twitter snowflakes, since Discord and Instagram IDs cannot
+// be told apart from them.
+func DetectIDKind(value string) (IDKind, error) {
+	value = strings.TrimSpace(value)
+
+	switch {
+	case uuidMatch.MatchString(value):
+		b, err := uuidBytes(value)
+		if err != nil {
+			return IDAuto, err
+		}
+		switch b[6] >> 4 {
+		case 1:
+			return IDUUIDv1, nil
+		case 6:
+			return IDUUIDv6, nil
+		case 7:
+			return IDUUIDv7, nil
+		}
+		return IDAuto, fmt.Errorf("%w (UUID version %d has no timestamp)", valueError(ErrInvalidID, value), b[6]>>4)
+	case objectIDMatch.MatchString(value):
+		return IDObjectID, nil
+	case ulidMatch.MatchString(value):
+		return IDULID, nil
+	case ksuidMatch.MatchString(value):
+		return IDKSUID, nil
+	case numericMatch.MatchString(value):
+		return IDTwitter, nil
+	}
+
+	return IDAuto, valueError(ErrInvalidID, value)
+}
+
+// DecodeID extracts the instant embedded in an identifier. With IDAuto the
+// kind is detected from the value; the kind used is returned.
+func DecodeID(value string, kind IDKind) (time.Time, IDKind, error) {
+	value = strings.TrimSpace(value)
+
+	if kind == IDAuto {
+		var err error
+		if kind, err = DetectIDKind(value); err != nil {
+			return time.Time{}, kind, err
+		}
+	}
+
+	invalid := valueError(ErrInvalidID, value)
+
+	switch kind {
+	case IDTwitter, IDDiscord, IDInstagram:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return time.Time{}, kind, invalid
+		}
+		shift, epoch, _ := snowflakeLayout(kind)
+		return time.UnixMilli(int64(n>>shift) + epoch), kind, nil
+	case IDUUIDv1, IDUUIDv6, IDUUIDv7:
+		if !uuidMatch.MatchString(value) {
+			return time.Time{}, kind, invalid
+		}
+		b, err := uuidBytes(value)
+		if err != nil {
+			return time.Time{}, kind, err
+		}
+		if kind == IDUUIDv7 {
+			return time.UnixMilli(int64(uint48(b[:6]))), kind, nil
+		}
+		var ts int64
+		if kind == IDUUIDv1 {
+			ts = int64(binary.BigEndian.Uint16(b[6:8])&0x0fff)<<48 |
+				int64(binary.BigEndian.Uint16(b[4:6]))<<32 |
+				int64(binary.BigEndian.Uint32(b[0:4]))
+		} else {
+			ts = int64(binary.BigEndian.Uint32(b[0:4]))<<28 |
+				int64(binary.BigEndian.Uint16(b[4:6]))<<12 |
+				int64(binary.BigEndian.Uint16(b[6:8])&0x0fff)
+		}
+		ts -= gregorianOffset
+		return time.Unix(ts/10000000, ts%10000000*100), kind, nil
+	case IDULID:
+		if !ulidMatch.MatchString(value) {
+			return time.Time{}, kind, invalid
+		}
+		b := decodeCrockford(value)
+		return time.UnixMilli(int64(uint48(b[:6]))), kind, nil
+	case IDKSUID:
+		if !ksuidMatch.MatchString(value) {
+			return time.Time{}, kind, invalid
+		}
+		b, ok := decodeBase62(value, 20)
+		if !ok {
+			return time.Time{}, kind, invalid
+		}
+		return time.Unix(int64(binary.BigEndian.Uint32(b[:4]))+ksuidEpoch, 0), kind, nil
+	case IDObjectID:
+		if !objectIDMatch.MatchString(value) {
+			return time.Time{}, kind, invalid
+		}
+		b, _ := hex.DecodeString(value)
+		return time.Unix(int64(binary.BigEndian.Uint32(b[:4])), 0), kind, nil
+	}
+
+	return time.Time{}, kind, valueError(ErrUnknownIDKind, kind.String())
+}
+
+// GenerateID returns an identifier of the given kind embedding t. All the
+// bits that are not part of the timestamp are set to zero, or to one when
+// upper is set, so the result is the lowest (or highest) identifier possible
+// for that instant, which is what range queries need.
+func GenerateID(kind IDKind, t time.Time, upper bool) (string, error) {
+	fill := byte(0)
+	if upper {
+		fill = 0xff
+	}
+	rest := func(n int) []byte {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = fill
+		}
+		return b
+	}
+	outOfRange := func() error {
+		return valueError(ErrOutOfRange, t.UTC().Format(time.RFC3339Nano))
+	}
+	if t.Unix() < -1<<39 || t.Unix() > 1<<39 {
+		// far beyond the range of every kind, and big enough to overflow the
+		// computations below
+		return "", outOfRange()
+	}
+
+	switch kind {
+	case IDTwitter, IDDiscord, IDInstagram:
+		shift, epoch, bits := snowflakeLayout(kind)
+		ms := t.UnixMilli() - epoch
+		if ms < 0 || ms >= 1<<bits {
+			return "", outOfRange()
+		}
+		n := uint64(ms) << shift
+		if upper {
+			n |= 1<<shift - 1
+		}
+		return strconv.FormatUint(n, 10), nil
+	case IDUUIDv1, IDUUIDv6:
+		ts := t.Unix()*10000000 + int64(t.Nanosecond()/100) + gregorianOffset
+		if ts < 0 || ts >= 1<<60 {
+			return "", outOfRange()
+		}
+		b := rest(16)
+		if kind == IDUUIDv1 {
+			binary.BigEndian.PutUint32(b[0:4], uint32(ts))
+			binary.BigEndian.PutUint16(b[4:6], uint16(ts>>32))
+			binary.BigEndian.PutUint16(b[6:8], uint16(ts>>48)|0x1000)
+		} else {
+			binary.BigEndian.PutUint32(b[0:4], uint32(ts>>28))
+			binary.BigEndian.PutUint16(b[4:6], uint16(ts>>12))
+			binary.BigEndian.PutUint16(b[6:8], uint16(ts&0x0fff)|0x6000)
+		}
+		b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+		return formatUUID(b), nil
+	case IDUUIDv7, IDULID:
+		ms := t.UnixMilli()
+		if ms < 0 || ms >= 1<<48 {
+			return "", outOfRange()
+		}
+		b := rest(16)
+		putUint48(b[:6], uint64(ms))
+		if kind == IDULID {
+			return encodeCrockford(b), nil
+		}
+		b[6] = b[6]&0x0f | 0x70
+		b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+		return formatUUID(b), nil
+	case IDKSUID:
+		s := t.Unix() - ksuidEpoch
+		if s < 0 || s >= 1<<32 {
+			return "", outOfRange()
+		}
+		b := rest(20)
+		binary.BigEndian.PutUint32(b[:4], uint32(s))
+		return encodeBase62(b, 27), nil
+	case IDObjectID:
+		s := t.Unix()
+		if s < 0 || s >= 1<<32 {
+			return "", outOfRange()
+		}
+		b := rest(12)
+		binary.BigEndian.PutUint32(b[:4], uint32(s))
+		return hex.EncodeToString(b), nil
+	}
+
+	return "", valueError(ErrUnknownIDKind, kind.String())
+}
+
+// snowflakeLayout returns the position of the timestamp, its epoch in unix
+// milliseconds and its width in bits.
+func snowflakeLayout(kind IDKind) (uint, int64, uint) {
+	switch kind {
+	case IDDiscord:
+		return 22, discordEpochMs, 42
+	case IDInstagram:
+		return 23, instagramEpochMs, 41
+	}
+	// Twitter IDs are signed, the top bit is always zero
+	return 22, twitterEpochMs, 41
+}
+
+func uuidBytes(value string) ([]byte, error) {
+	clean := strings.NewReplacer("-", "", "{", "", "}", "").Replace(value)
+	b, err := hex.DecodeString(clean)
+	if err != nil || len(b) != 16 {
+		return nil, valueError(ErrInvalidID, value)
+	}
+	return b, nil
+}
+
+func formatUUID(b []byte) string {
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+func uint48(b []byte) uint64 {
+	return uint64(b[0])<<40 | uint64(b[1])<<32 | uint64(b[2])<<24 | uint64(b[3])<<16 | uint64(b[4])<<8 | uint64(b[5])
+}
+
+func putUint48(b []byte, v uint64) {
+	for i := 0; i < 6; i++ {
+		b[i] = byte(v >> (40 - 8*i))
+	}
+}
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// decodeCrockford decodes a 26 character ULID into its 16 bytes.
+func decodeCrockford(value string) []byte {
+	n := new(big.Int)
+	for _, c := range strings.ToUpper(value) {
+		n.Lsh(n, 5)
+		n.Or(n, big.NewInt(int64(strings.IndexRune(crockfordAlphabet, c))))
+	}
+	return n.FillBytes(make([]byte, 16))
+}
+
+// encodeCrockford encodes 16 bytes as a 26 character ULID.
+func encodeCrockford(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	out := make([]byte, 26)
+	mask := big.NewInt(31)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockfordAlphabet[new(big.Int).And(n, mask).Int64()]
+		n.Rsh(n, 5)
+	}
+	return string(out)
+}
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+func decodeBase62(value string, size int) ([]byte, bool) {
+	n := new(big.Int)
+	base := big.NewInt(62)
+	for _, c := range value {
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(strings.IndexRune(base62Alphabet, c))))
+	}
+	if (n.BitLen()+7)/8 > size {
+		return nil, false
+	}
+	return n.FillBytes(make([]byte, size)), true
+}
+
+func encodeBase62(b []byte, width int) string {
+	n := new(big.Int).SetBytes(b)
+	base := big.NewInt(62)
+	out := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		mod := new(big.Int)
+		n.DivMod(n, base, mod)
+		out[i] = base62Alphabet[mod.Int64()]
+	}
+	return string(out)
+}
@@ -0,0 +1,94 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestDecodeID(t *testing.T) {
+	tests := []struct {
+		value    string
+		kind     IDKind
+		detected IDKind
+		expected time.Time
+	}{
+		{"1445078208190291968", IDAuto, IDTwitter, time.Date(2021, 10, 4, 17, 27, 47, 744000000, time.UTC)},
+		{"175928847299117063", IDDiscord, IDDiscord, time.Date(2016, 4, 30, 11, 18, 25, 796000000, time.UTC)},
+		{"C232AB00-9414-11EC-B3C8-9F6BDECED846", IDAuto, IDUUIDv1, time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)},
+		{"1EC9414C-232A-6B00-B3C8-9F6BDECED846", IDAuto, IDUUIDv6, time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)},
+		{"017F22E2-79B0-7CC3-98C4-DC0C0C07398F", IDAuto, IDUUIDv7, time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)},
+		{"{017f22e279b07cc398c4dc0c0c07398f}", IDAuto, IDUUIDv7, time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAV", IDAuto, IDULID, time.Date(2016, 7, 30, 23, 54, 10, 259000000, time.UTC)},
+		{"0ujtsYcgvSTl8PAuAdqWYSMnLOv", IDAuto, IDKSUID, time.Date(2017, 10, 10, 4, 0, 47, 0, time.UTC)},
+		{"507f1f77bcf86cd799439011", IDAuto, IDObjectID, time.Date(2012, 10, 17, 21, 13, 27, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		actual, kind, err := DecodeID(test.value, test.kind)
+		require.NoErrorf(t, err, "decoding %q", test.value)
+		assert.Equalf(t, test.detected, kind, "decoding %q", test.value)
+		assert.Truef(t, test.expected.Equal(actual), "decoding %q: expected %s, got %s", test.value, test.expected, actual.UTC())
+	}
+}
+
+func TestDecodeIDInvalid(t *testing.T) {
+	_, _, err := DecodeID("550e8400-e29b-41d4-a716-446655440000", IDAuto) // version 4
+	assert.ErrorIs(t, err, ErrInvalidID)
+
+	_, _, err = DecodeID("not an id", IDAuto)
+	assert.ErrorIs(t, err, ErrInvalidID)
+
+	_, _, err = DecodeID("507f1f77bcf86cd799439011", IDULID)
+	assert.ErrorIs(t, err, ErrInvalidID)
+
+	_, err = LookupIDKind("guid")
+	assert.ErrorIs(t, err, ErrUnknownIDKind)
+}
+
+func TestGenerateID(t *testing.T) {
+	ref := time.Date(2023, 4, 5, 17, 50, 44, 123000000, time.UTC)
+
+	for k := IDTwitter; k <= IDObjectID; k++ {
+		for _, upper := range []bool{false, true} {
+			id, err := GenerateID(k, ref, upper)
+			require.NoErrorf(t, err, "generating %s", k)
+
+			decoded, kind, err := DecodeID(id, k)
+			require.NoErrorf(t, err, "decoding %s %q", k, id)
+			assert.Equal(t, k, kind)
+
+			precision := time.Millisecond
+			switch k {
+			case IDKSUID, IDObjectID:
+				precision = time.Second
+			}
+			assert.Truef(t, ref.Truncate(precision).Equal(decoded), "%s %q: expected %s, got %s", k, id, ref, decoded.UTC())
+
+			if k >= IDUUIDv1 && k <= IDObjectID {
+				detected, err := DetectIDKind(id)
+				require.NoError(t, err)
+				assert.Equalf(t, k, detected, "detecting %q", id)
+			}
+		}
+	}
+
+	lower, err := GenerateID(IDULID, ref, false)
+	require.NoError(t, err)
+	upper, err := GenerateID(IDULID, ref, true)
+	require.NoError(t, err)
+	assert.Equal(t, "01GX98NPCV0000000000000000", lower)
+	assert.Equal(t, "01GX98NPCVZZZZZZZZZZZZZZZZ", upper)
+
+	id, err := GenerateID(IDObjectID, ref, false)
+	require.NoError(t, err)
+	assert.Equal(t, "642db4f40000000000000000", id)
+
+	id, err = GenerateID(IDUUIDv7, ref, false)
+	require.NoError(t, err)
+	assert.Equal(t, "0187528a-d99b-7000-8000-000000000000", id)
+
+	_, err = GenerateID(IDDiscord, time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), false)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
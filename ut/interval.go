@@ -0,0 +1,306 @@
+package ut
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var periodMatch = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// isoLayouts are the forms of ISO 8601 times accepted in intervals when no
+// layout is given. Times without an offset are UTC, like in ParseBase.
+var isoLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"20060102T150405Z0700",
+	"20060102T1504Z0700",
+	"20060102T150405",
+	"20060102",
+}
+
+// Period is an ISO 8601 duration, like P1Y2M10DT2H30M. It is applied with
+// ApplyDelta, one component after the other from years down to seconds, so
+// months and years keep the day, clamped to the end of shorter months, and
+// the time of day.
+type Period struct {
+	Years, Months, Weeks, Days, Hours, Minutes, Seconds int64
+}
+
+// ParsePeriod parses an ISO 8601 duration. Fractions are not supported.
+func ParsePeriod(value string) (Period, error) {
+	matches := periodMatch.FindStringSubmatch(value)
+	if len(matches) == 0 || value == "P" || strings.HasSuffix(value, "T") {
+		return Period{}, valueError(ErrInvalidInterval, value)
+	}
+
+	var p Period
+	for i, field := range []*int64{&p.Years, &p.Months, &p.Weeks, &p.Days, &p.Hours, &p.Minutes, &p.Seconds} {
+		if matches[i+1] == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(matches[i+1], 10, 64)
+		if err != nil {
+			return Period{}, valueError(ErrOutOfRange, value)
+		}
+		*field = v
+	}
+
+	return p, nil
+}
+
+// String returns the ISO 8601 form of the period.
+func (p Period) String() string {
+	var b strings.Builder
+	b.WriteString("P")
+	for _, c := range []struct {
+		v    int64
+		unit string
+	}{{p.Years, "Y"}, {p.Months, "M"}, {p.Weeks, "W"}, {p.Days, "D"}} {
+		if c.v != 0 {
+			fmt.Fprintf(&b, "%d%s", c.v, c.unit)
+		}
+	}
+	if p.Hours != 0 || p.Minutes != 0 || p.Seconds != 0 {
+		b.WriteString("T")
+		for _, c := range []struct {
+			v    int64
+			unit string
+		}{{p.Hours, "H"}, {p.Minutes, "M"}, {p.Seconds, "S"}} {
+			if c.v != 0 {
+				fmt.Fprintf(&b, "%d%s", c.v, c.unit)
+			}
+		}
+	}
+	if b.Len() == 1 {
+		b.WriteString("T0S")
+	}
+
+	return b.String()
+}
+
+// AddTo adds the period times times to t; negative times subtract it.
+func (p Period) AddTo(t time.Time, times int64) (time.Time, error) {
+	if p.Weeks > math.MaxInt64/7 {
+		return t, valueError(ErrOutOfRange, p.String())
+	}
+
+	components := []struct {
+		v    int64
+		unit string
+	}{
+		{p.Years, "y"},
+		{p.Months, "mo"},
+		{p.Weeks * 7, "d"},
+		{p.Days, "d"},
+		{p.Hours, "h"},
+		{p.Minutes, "min"},
+		{p.Seconds, "s"},
+	}
+	if times < 0 {
+		// subtract the smallest units first, undoing an addition
+		for i, j := 0, len(components)-1; i < j; i, j = i+1, j-1 {
+			components[i], components[j] = components[j], components[i]
+		}
+	}
+
+	for _, c := range components {
+		if c.v == 0 || times == 0 {
+			continue
+		}
+		if c.v > math.MaxInt64/abs(times) {
+			return t, valueError(ErrOutOfRange, p.String())
+		}
+
+		var err error
+		if t, err = ApplyDelta(t, fmt.Sprintf("%d%s", c.v*times, c.unit)); err != nil {
+			return t, err
+		}
+	}
+
+	return t, nil
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Interval is an ISO 8601 time interval, possibly repeating.
+type Interval struct {
+	Start, End time.Time
+	// Repeat is the number of occurrences of a repeating interval, -1 when
+	// it repeats forever and 0 when it does not repeat.
+	Repeat int
+
+	period    Period
+	hasPeriod bool
+	fromEnd   bool // given as duration/end
+}
+
+// ParseInterval parses an ISO 8601 interval: "start/end", "start/duration"
+// or "duration/end", optionally preceded by "Rn/" for n repetitions or "R/"
+// to repeat forever. Times are ISO 8601 or, when layout is given, parsed like
+// ParseBase, which also accepts keywords like "now".
+func ParseInterval(value string, layout string, now time.Time) (Interval, error) {
+	var i Interval
+
+	parts := strings.Split(value, "/")
+	if strings.HasPrefix(parts[0], "R") {
+		switch n := parts[0][1:]; n {
+		case "", "-1":
+			i.Repeat = -1
+		default:
+			repeat, err := strconv.Atoi(n)
+			if err != nil || repeat < 1 {
+				return i, fmt.Errorf("%w (invalid number of repetitions)", valueError(ErrInvalidInterval, value))
+			}
+			i.Repeat = repeat
+		}
+		parts = parts[1:]
+	}
+	if len(parts) != 2 {
+		return i, fmt.Errorf("%w (expected start/end, start/duration or duration/end)", valueError(ErrInvalidInterval, value))
+	}
+
+	startIsPeriod, endIsPeriod := strings.HasPrefix(parts[0], "P"), strings.HasPrefix(parts[1], "P")
+	var err error
+	switch {
+	case startIsPeriod && endIsPeriod:
+		return i, fmt.Errorf("%w (a duration needs a start or an end)", valueError(ErrInvalidInterval, value))
+	case endIsPeriod:
+		if i.Start, err = parseIntervalTime(parts[0], layout, now); err != nil {
+			return i, err
+		}
+		if i.period, err = ParsePeriod(parts[1]); err != nil {
+			return i, err
+		}
+		i.hasPeriod = true
+		i.End, err = i.period.AddTo(i.Start, 1)
+	case startIsPeriod:
+		if i.End, err = parseIntervalTime(parts[1], layout, now); err != nil {
+			return i, err
+		}
+		if i.period, err = ParsePeriod(parts[0]); err != nil {
+			return i, err
+		}
+		i.hasPeriod, i.fromEnd = true, true
+		i.Start, err = i.period.AddTo(i.End, -1)
+	default:
+		if i.Start, err = parseIntervalTime(parts[0], layout, now); err != nil {
+			return i, err
+		}
+		if i.End, err = parseIntervalTime(parts[1], layout, now); err != nil {
+			return i, err
+		}
+	}
+	if err != nil {
+		return i, err
+	}
+
+	if i.End.Before(i.Start) {
+		return i, fmt.Errorf("%w (the end is before the start)", valueError(ErrInvalidInterval, value))
+	}
+	if i.Repeat != 0 && !i.End.After(i.Start) {
+		return i, fmt.Errorf("%w (a repeating interval cannot be empty)", valueError(ErrInvalidInterval, value))
+	}
+
+	return i, nil
+}
+
+// parseIntervalTime parses a time of an interval, in one of the ISO 8601
+// layouts or, when given, the layout.
+func parseIntervalTime(value string, layout string, now time.Time) (time.Time, error) {
+	if layout == "" {
+		for _, l := range isoLayouts {
+			if t, err := time.Parse(l, value); err == nil {
+				return t, nil
+			}
+		}
+	}
+
+	return ParseBase(value, layout, now)
+}
+
+// String returns the ISO 8601 form of the interval, keeping the duration it
+// was given with, if any.
+func (i Interval) String() string {
+	var parts []string
+	switch i.Repeat {
+	case 0:
+	case -1:
+		parts = append(parts, "R")
+	default:
+		parts = append(parts, "R"+strconv.Itoa(i.Repeat))
+	}
+
+	switch {
+	case i.hasPeriod && i.fromEnd:
+		parts = append(parts, i.period.String(), i.End.Format(time.RFC3339Nano))
+	case i.hasPeriod:
+		parts = append(parts, i.Start.Format(time.RFC3339Nano), i.period.String())
+	default:
+		parts = append(parts, i.Start.Format(time.RFC3339Nano), i.End.Format(time.RFC3339Nano))
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// Occurrences expands a repeating interval into the single intervals it is
+// made of, in chronological order, up to limit of them when limit is
+// positive. Intervals repeating forever need a limit. Intervals given as
+// duration/end repeat backwards, ending at End.
+func (i Interval) Occurrences(limit int) ([]Interval, error) {
+	n := i.Repeat
+	switch {
+	case n == 0:
+		n = 1
+	case n < 0 && limit <= 0:
+		return nil, fmt.Errorf("%w: the interval repeats forever", ErrOutOfRange)
+	}
+	if limit > 0 && (n < 0 || n > limit) {
+		n = limit
+	}
+
+	var occurrences []Interval
+	for k := 0; k < n; k++ {
+		var o Interval
+		var err error
+		switch {
+		case i.hasPeriod && i.fromEnd:
+			if o.End, err = i.period.AddTo(i.End, -int64(k)); err == nil {
+				o.Start, err = i.period.AddTo(i.End, -int64(k+1))
+			}
+		case i.hasPeriod:
+			if o.Start, err = i.period.AddTo(i.Start, int64(k)); err == nil {
+				o.End, err = i.period.AddTo(i.Start, int64(k+1))
+			}
+		default:
+			d := i.End.Sub(i.Start)
+			if !i.Start.Add(d).Equal(i.End) || (k > 0 && d > math.MaxInt64/time.Duration(k+1)) {
+				return nil, fmt.Errorf("%w: the interval is longer than 292 years", ErrOutOfRange)
+			}
+			o.Start, o.End = i.Start.Add(time.Duration(k)*d), i.Start.Add(time.Duration(k+1)*d)
+		}
+		if err != nil {
+			return nil, err
+		}
+		occurrences = append(occurrences, o)
+	}
+
+	if i.fromEnd {
+		for a, b := 0, len(occurrences)-1; a < b; a, b = a+1, b-1 {
+			occurrences[a], occurrences[b] = occurrences[b], occurrences[a]
+		}
+	}
+
+	return occurrences, nil
+}
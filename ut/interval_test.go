@@ -0,0 +1,140 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Period
+	}{
+		{"P1D", Period{Days: 1}},
+		{"PT36H", Period{Hours: 36}},
+		{"P2W", Period{Weeks: 2}},
+		{"P1Y2M10DT2H30M15S", Period{Years: 1, Months: 2, Days: 10, Hours: 2, Minutes: 30, Seconds: 15}},
+		{"P1M", Period{Months: 1}},
+		{"PT1M", Period{Minutes: 1}},
+	}
+
+	for _, test := range tests {
+		actual, err := ParsePeriod(test.value)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, actual)
+		assert.Equal(t, test.value, actual.String())
+	}
+
+	for _, value := range []string{"", "P", "PT", "P1DT", "1D", "P1.5D", "PT1D", "P1H"} {
+		_, err := ParsePeriod(value)
+		assert.ErrorIsf(t, err, ErrInvalidInterval, "expected error for %q", value)
+	}
+}
+
+func TestPeriodAddTo(t *testing.T) {
+	base := time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC)
+	p := Period{Months: 1, Days: 1, Hours: 1}
+
+	actual, err := p.AddTo(base, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 3, 1, 13, 0, 0, 0, time.UTC), actual, "January 31st plus a month is February 28th")
+
+	actual, err = Period{Weeks: 1}.AddTo(base, -2)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 1, 17, 12, 0, 0, 0, time.UTC), actual)
+
+	_, err = Period{Seconds: 1 << 62}.AddTo(base, 4)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestParseInterval(t *testing.T) {
+	now := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+	day := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		layout string
+		start  time.Time
+		end    time.Time
+		repeat int
+	}{
+		{"2023-01-01T00:00Z/2023-01-02T00:00Z", "", day, day.AddDate(0, 0, 1), 0},
+		{"2023-01-01/P1D", "", day, day.AddDate(0, 0, 1), 0},
+		{"PT12H/2023-01-01T00:00:00+02:00", "", day.Add(-14 * time.Hour), day.Add(-2 * time.Hour), 0},
+		{"20230101T000000Z/P1M", "", day, day.AddDate(0, 1, 0), 0},
+		{"R5/2023-01-01T00:00Z/P1D", "", day, day.AddDate(0, 0, 1), 5},
+		{"R/2023-01-01/PT1H", "", day, day.Add(time.Hour), -1},
+		{"now/PT1H", "", now, now.Add(time.Hour), 0},
+	}
+
+	for _, test := range tests {
+		actual, err := ParseInterval(test.value, test.layout, now)
+		require.NoError(t, err, test.value)
+		assert.True(t, test.start.Equal(actual.Start), test.value)
+		assert.True(t, test.end.Equal(actual.End), test.value)
+		assert.Equal(t, test.repeat, actual.Repeat, test.value)
+	}
+
+	actual, err := ParseInterval("2023.01.01/P1D", "%Y.%m.%d", now)
+	require.NoError(t, err)
+	assert.Equal(t, day, actual.Start)
+}
+
+func TestParseIntervalInvalid(t *testing.T) {
+	for _, value := range []string{"", "2023-01-01", "P1D/P2D", "2023-01-02/2023-01-01", "R0/2023-01-01/P1D", "Rx/2023-01-01/P1D", "R/2023-01-01/2023-01-01", "2023-01-01/P1D/P2D"} {
+		_, err := ParseInterval(value, "", time.Now())
+		assert.ErrorIsf(t, err, ErrInvalidInterval, "expected error for %q", value)
+	}
+
+	_, err := ParseInterval("2023-01-01/garbage", "", time.Now())
+	assert.ErrorIs(t, err, ErrInvalidBase)
+
+	// slashes always separate the parts of the interval
+	_, err = ParseInterval("01/01/2023/P1D", "%m/%d/%Y", time.Now())
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+}
+
+func TestIntervalOccurrences(t *testing.T) {
+	now := time.Now()
+
+	i, err := ParseInterval("R3/2023-01-31T00:00Z/P1M", "", now)
+	require.NoError(t, err)
+	occurrences, err := i.Occurrences(0)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 3)
+	assert.Equal(t, "2023-01-31T00:00:00Z/2023-02-28T00:00:00Z", occurrences[0].String(), "days past the end of the month are clamped")
+	assert.Equal(t, "2023-02-28T00:00:00Z/2023-03-31T00:00:00Z", occurrences[1].String())
+	assert.Equal(t, "2023-03-31T00:00:00Z/2023-04-30T00:00:00Z", occurrences[2].String(), "months count from the start, not from the previous repetition")
+
+	i, err = ParseInterval("R3/P1D/2023-01-10T00:00Z", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, "R3/P1D/2023-01-10T00:00:00Z", i.String())
+	occurrences, err = i.Occurrences(2)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 2)
+	assert.Equal(t, "2023-01-08T00:00:00Z/2023-01-09T00:00:00Z", occurrences[0].String())
+	assert.Equal(t, "2023-01-09T00:00:00Z/2023-01-10T00:00:00Z", occurrences[1].String())
+
+	i, err = ParseInterval("R2/2023-01-01T00:00:00Z/2023-01-01T06:00:00Z", "", now)
+	require.NoError(t, err)
+	occurrences, err = i.Occurrences(0)
+	require.NoError(t, err)
+	require.Len(t, occurrences, 2)
+	assert.Equal(t, "2023-01-01T06:00:00Z/2023-01-01T12:00:00Z", occurrences[1].String())
+
+	i, err = ParseInterval("R/2023-01-01/PT1H", "", now)
+	require.NoError(t, err)
+	_, err = i.Occurrences(0)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	occurrences, err = i.Occurrences(4)
+	require.NoError(t, err)
+	assert.Len(t, occurrences, 4)
+
+	i, err = ParseInterval("2023-01-01/P1D", "", now)
+	require.NoError(t, err)
+	occurrences, err = i.Occurrences(10)
+	require.NoError(t, err)
+	assert.Equal(t, []Interval{{Start: i.Start, End: i.End}}, occurrences)
+}
@@ -0,0 +1,188 @@
+package ut
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ntpEpochUnix is 1900-01-01 UTC, the origin of the timestamps in leap-seconds.list.
+const ntpEpochUnix int64 = -2208988800
+
+// gpsTAIOffset is the constant difference between TAI and GPS time.
+const gpsTAIOffset int64 = 19
+
+//go:embed leap-seconds.list
+var embeddedLeapSeconds []byte
+
+// LeapTable holds the offsets between TAI and UTC, as published in the
+// leap-seconds.list file of the IERS.
+type LeapTable struct {
+	// Updated is the date of the last update of the list, if known.
+	Updated time.Time
+	// Expires is the date after which the list may miss leap seconds.
+	Expires time.Time
+
+	leaps []leap
+}
+
+// leap is an entry of the table: the offset TAI-UTC applying from start on.
+type leap struct {
+	start  int64 // unix seconds
+	offset int64 // seconds
+}
+
+// ParseLeapSeconds reads a table in the format of leap-seconds.list: lines
+// holding an NTP timestamp and the offset TAI-UTC, and comments starting
+// with "#", of which "#$" gives the last update and "#@" the expiration.
+func ParseLeapSeconds(r io.Reader) (*LeapTable, error) {
+	table := &LeapTable{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "#$") || strings.HasPrefix(line, "#@") {
+			ntp, err := strconv.ParseInt(strings.TrimSpace(line[2:]), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %q", ErrInvalidLeapSeconds, n, line)
+			}
+			if line[1] == '$' {
+				table.Updated = time.Unix(ntp+ntpEpochUnix, 0).UTC()
+			} else {
+				table.Expires = time.Unix(ntp+ntpEpochUnix, 0).UTC()
+			}
+			continue
+		}
+
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%w: line %d: %q", ErrInvalidLeapSeconds, n, scanner.Text())
+		}
+
+		ntp, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %q", ErrInvalidLeapSeconds, n, scanner.Text())
+		}
+		offset, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %q", ErrInvalidLeapSeconds, n, scanner.Text())
+		}
+		table.leaps = append(table.leaps, leap{start: ntp + ntpEpochUnix, offset: offset})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(table.leaps) == 0 {
+		return nil, fmt.Errorf("%w: no entries", ErrInvalidLeapSeconds)
+	}
+	sort.Slice(table.leaps, func(i, j int) bool { return table.leaps[i].start < table.leaps[j].start })
+
+	return table, nil
+}
+
+var (
+	embeddedTable     *LeapTable
+	embeddedTableOnce sync.Once
+
+	leapTableMu sync.RWMutex
+	leapTable   *LeapTable
+)
+
+// LeapSeconds returns the table used by the conversions between time scales.
+// Unless replaced with SetLeapSeconds, it is the table embedded in the package.
+func LeapSeconds() *LeapTable {
+	leapTableMu.RLock()
+	table := leapTable
+	leapTableMu.RUnlock()
+	if table != nil {
+		return table
+	}
+
+	embeddedTableOnce.Do(func() {
+		var err error
+		if embeddedTable, err = ParseLeapSeconds(bytes.NewReader(embeddedLeapSeconds)); err != nil {
+			panic(err)
+		}
+	})
+
+	return embeddedTable
+}
+
+// SetLeapSeconds replaces the table used by the conversions between time
+// scales, for instance with a newer leap-seconds.list. A nil table restores
+// the embedded one.
+func SetLeapSeconds(table *LeapTable) {
+	leapTableMu.Lock()
+	leapTable = table
+	leapTableMu.Unlock()
+}
+
+// Expired reports whether the table may miss leap seconds announced after it
+// was published.
+func (t *LeapTable) Expired(now time.Time) bool {
+	return !t.Expires.IsZero() && now.After(t.Expires)
+}
+
+// TAIOffset returns TAI-UTC, in seconds, at the given instant. Before 1972 the
+// offset of the first entry is used; the fractional offsets of earlier years
+// are not modeled.
+func (t *LeapTable) TAIOffset(at time.Time) int64 {
+	return t.offset(at.Unix())
+}
+
+func (t *LeapTable) offset(unix int64) int64 {
+	i := sort.Search(len(t.leaps), func(i int) bool { return t.leaps[i].start > unix })
+	if i == 0 {
+		return t.leaps[0].offset
+	}
+	return t.leaps[i-1].offset
+}
+
+// IsLeapSecond reports whether a leap second was inserted right before the
+// given instant, that is whether the second before it was labeled 23:59:60.
+func (t *LeapTable) IsLeapSecond(at time.Time) bool {
+	if at.Nanosecond() != 0 {
+		return false
+	}
+
+	unix := at.Unix()
+	for i := 1; i < len(t.leaps); i++ {
+		if t.leaps[i].start == unix {
+			return t.leaps[i].offset > t.leaps[i-1].offset
+		}
+	}
+
+	return false
+}
+
+// fromTAI converts seconds counted on the TAI scale since 1970-01-01 to unix
+// seconds. The seconds inserted as leap seconds have no unix time of their
+// own and map to the second that follows them, as POSIX does.
+func (t *LeapTable) fromTAI(tai int64) int64 {
+	for i := len(t.leaps) - 1; i > 0; i-- {
+		if tai >= t.leaps[i].start+t.leaps[i].offset {
+			return tai - t.leaps[i].offset
+		}
+		if tai >= t.leaps[i].start+t.leaps[i-1].offset {
+			// inside a leap second
+			return t.leaps[i].start
+		}
+	}
+
+	return tai - t.leaps[0].offset
+}
@@ -0,0 +1,64 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmbeddedLeapSeconds(t *testing.T) {
+	table := LeapSeconds()
+
+	assert.False(t, table.Expires.IsZero())
+	assert.True(t, table.Expires.After(table.Updated))
+
+	assert.Equal(t, int64(10), table.TAIOffset(time.Unix(0, 0)))
+	assert.Equal(t, int64(10), table.TAIOffset(time.Date(1972, 6, 30, 23, 59, 59, 0, time.UTC)))
+	assert.Equal(t, int64(11), table.TAIOffset(time.Date(1972, 7, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, int64(36), table.TAIOffset(time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC)))
+	assert.Equal(t, int64(37), table.TAIOffset(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert.True(t, table.IsLeapSecond(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, table.IsLeapSecond(time.Date(2015, 7, 1, 9, 0, 0, 0, time.FixedZone("JST", 9*3600))))
+	assert.False(t, table.IsLeapSecond(time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, table.IsLeapSecond(time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestLeapTableFromTAI(t *testing.T) {
+	table := LeapSeconds()
+	leap := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	assert.Equal(t, leap-1, table.fromTAI(leap+35))
+	assert.Equal(t, leap, table.fromTAI(leap+36), "the leap second maps to the second after it")
+	assert.Equal(t, leap, table.fromTAI(leap+37))
+	assert.Equal(t, leap+1, table.fromTAI(leap+38))
+	assert.Equal(t, int64(0), table.fromTAI(10))
+}
+
+func TestParseLeapSeconds(t *testing.T) {
+	list := `# a list with a made-up leap second
+#$	3960921600
+#@	4007404800
+3692217600	37	# 1 Jan 2017
+3976214400	38	# 1 Jan 2026
+`
+	table, err := ParseLeapSeconds(strings.NewReader(list))
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC), table.Updated)
+	assert.Equal(t, time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC), table.Expires)
+	assert.True(t, table.Expired(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, table.IsLeapSecond(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	SetLeapSeconds(table)
+	defer SetLeapSeconds(nil)
+	assert.Equal(t, "1767225638", EpochTAI.Format(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Second))
+	assert.Equal(t, "1767225636", EpochTAI.Format(time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC), Second))
+
+	for _, invalid := range []string{"", "# only comments\n", "3692217600\n", "3692217600 x\n", "#@ soon\n3692217600 37\n"} {
+		_, err := ParseLeapSeconds(strings.NewReader(invalid))
+		assert.ErrorIsf(t, err, ErrInvalidLeapSeconds, "parsing %q", invalid)
+	}
+}
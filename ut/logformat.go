@@ -0,0 +1,231 @@
+package ut
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LogFormat recognizes the timestamps written by a common log format.
+type LogFormat struct {
+	Name        string
+	Description string
+	// Example is a timestamp in the format, as found in logs.
+	Example string
+
+	find  *regexp.Regexp // finds the timestamp in a line, in its first group
+	parse func(value string, ref time.Time) (time.Time, error)
+}
+
+// logFormats are the log formats, in the order tried when the format is
+// "auto": the most specific first.
+var logFormats = []LogFormat{
+	{
+		Name:        "journald",
+		Description: "journald __REALTIME_TIMESTAMP, microseconds since the epoch",
+		Example:     "__REALTIME_TIMESTAMP=1680717044123456",
+		find:        regexp.MustCompile(`__REALTIME_TIMESTAMP"?\s*[=:]\s*"?(\d+)`),
+		parse: func(value string, _ time.Time) (time.Time, error) {
+			return ParseEpoch(value, Microsecond)
+		},
+	},
+	{
+		Name:        "clf",
+		Description: "Apache and nginx common log format",
+		Example:     "[05/Apr/2023:17:50:44 +0000]",
+		find:        regexp.MustCompile(`\[?(\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4})\]?`),
+		parse:       layoutParser("02/Jan/2006:15:04:05 -0700"),
+	},
+	{
+		Name:        "rfc5424",
+		Description: "syslog RFC 5424, an RFC 3339 time",
+		Example:     "2023-04-05T17:50:44.123456Z",
+		find:        regexp.MustCompile(`(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d{1,9})?(?:Z|[+-]\d{2}:\d{2}))`),
+		parse:       layoutParser(time.RFC3339Nano),
+	},
+	{
+		Name:        "go",
+		Description: "Go time.Time.String(), printed by parse without --format",
+		Example:     "2023-04-05 17:50:44.123 +0000 UTC",
+		find:        regexp.MustCompile(`(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}(?:\.\d{1,9})? [+-]\d{4} [A-Za-z0-9+-]+)`),
+		parse: func(value string, ref time.Time) (time.Time, error) {
+			// drop the monotonic clock reading, like "m=+0.000012"
+			if i := strings.Index(value, " m="); i >= 0 {
+				value = value[:i]
+			}
+			return layoutParser("2006-01-02 15:04:05.999999999 -0700 MST")(value, ref)
+		},
+	},
+	{
+		Name:        "java",
+		Description: "Java java.util.Date.toString() and the default SimpleDateFormat pattern",
+		Example:     "Wed Apr 05 17:50:44 UTC 2023",
+		find:        regexp.MustCompile(`([A-Z][a-z]{2} [A-Z][a-z]{2} \d{2} \d{2}:\d{2}:\d{2} [A-Za-z0-9+:-]+ \d{4}|\d{1,2}/\d{1,2}/\d{2},? \d{1,2}:\d{2} [AP]M)`),
+		parse:       parseJava,
+	},
+	{
+		Name:        "klog",
+		Description: "Kubernetes klog header, without a year",
+		Example:     "I0405 17:50:44.123456",
+		find:        regexp.MustCompile(`(?:^|\s)[IWEF](\d{4} \d{2}:\d{2}:\d{2}\.\d{6})\b`),
+		parse: func(value string, ref time.Time) (time.Time, error) {
+			if len(value) > 0 && strings.IndexByte("IWEF", value[0]) >= 0 {
+				value = value[1:]
+			}
+			return parseWithoutYear("0102 15:04:05.000000", value, ref)
+		},
+	},
+	{
+		Name:        "syslog",
+		Description: "syslog RFC 3164, without a year",
+		Example:     "Apr  5 17:50:44",
+		find:        regexp.MustCompile(`([A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2})`),
+		parse: func(value string, ref time.Time) (time.Time, error) {
+			return parseWithoutYear(time.Stamp, value, ref)
+		},
+	},
+}
+
+// layoutParser returns a parser for times in the given Go layout.
+func layoutParser(layout string) func(string, time.Time) (time.Time, error) {
+	return func(value string, _ time.Time) (time.Time, error) {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			return t, valueError(ErrInvalidTimestamp, value)
+		}
+		return t, nil
+	}
+}
+
+// parseWithoutYear parses a time without a year nor a zone, like syslog's,
+// on the wall clock of ref's location. It takes the year of ref, or the one
+// before when that would put it more than a day after ref, so December logs
+// read in January fall in December.
+func parseWithoutYear(layout string, value string, ref time.Time) (time.Time, error) {
+	t, err := time.Parse(layout, strings.TrimSpace(value))
+	if err != nil {
+		return t, valueError(ErrInvalidTimestamp, value)
+	}
+
+	inYear := func(year int) time.Time {
+		return time.Date(year, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), ref.Location())
+	}
+	year := ref.Year()
+	if inYear(year).After(ref.AddDate(0, 0, 1)) {
+		year--
+	}
+	// February 29th only exists in leap years
+	for y := year; y > year-8; y-- {
+		if c := inYear(y); c.Day() == t.Day() {
+			return c, nil
+		}
+	}
+
+	return t, valueError(ErrInvalidTimestamp, value)
+}
+
+// parseJava parses the output of java.util.Date.toString(), whose zone is an
+// abbreviation, or of the default SimpleDateFormat pattern, M/d/yy h:mm a, on
+// the wall clock of ref's location.
+func parseJava(value string, ref time.Time) (time.Time, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 6 {
+		t, err := time.Parse("Mon Jan 02 15:04:05 2006", strings.Join(append(fields[:4:4], fields[5]), " "))
+		if err != nil {
+			return t, valueError(ErrInvalidTimestamp, value)
+		}
+
+		loc, ok := abbreviatedZone(fields[4], time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, ref.Location()))
+		if !ok {
+			if loc, err = LoadZone(fields[4]); err != nil {
+				return t, err
+			}
+		}
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc), nil
+	}
+
+	for _, layout := range []string{"1/2/06, 3:04 PM", "1/2/06 3:04 PM"} {
+		if t, err := time.ParseInLocation(layout, value, ref.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, valueError(ErrInvalidTimestamp, value)
+}
+
+// LogFormats returns the log formats, in the order LogFormatAuto tries them.
+func LogFormats() []LogFormat {
+	return append([]LogFormat(nil), logFormats...)
+}
+
+// LogFormatAuto is the name of the log format trying every other one.
+const LogFormatAuto = "auto"
+
+// LogFormatNames lists the names accepted by LookupLogFormat.
+func LogFormatNames() []string {
+	names := []string{LogFormatAuto}
+	for _, f := range logFormats {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// LookupLogFormat returns the log format with the given name. The "auto"
+// format tries each format in turn.
+func LookupLogFormat(name string) (LogFormat, error) {
+	if strings.EqualFold(name, LogFormatAuto) {
+		return LogFormat{Name: LogFormatAuto, Description: "any of the log formats"}, nil
+	}
+	for _, f := range logFormats {
+		if strings.EqualFold(name, f.Name) {
+			return f, nil
+		}
+	}
+
+	return LogFormat{}, valueError(ErrUnknownLogFormat, name)
+}
+
+// Parse parses a timestamp in the format. Times without a year take the one
+// of ref; times without a zone are read in ref's location.
+func (f LogFormat) Parse(value string, ref time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if f.Name == LogFormatAuto {
+		// only the whole value: bare numbers are not journald timestamps
+		for _, format := range logFormats {
+			matches := format.find.FindStringSubmatch(value)
+			if len(matches) == 0 || strings.TrimSpace(matches[0]) != value {
+				continue
+			}
+			if t, err := format.parse(matches[1], ref); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, valueError(ErrInvalidTimestamp, value)
+	}
+
+	if matches := f.find.FindStringSubmatch(value); len(matches) > 0 && strings.TrimSpace(matches[0]) == value {
+		value = matches[1]
+	}
+	return f.parse(value, ref)
+}
+
+// Find finds the first timestamp in the format in a line of a log, and
+// returns its text and its time.
+func (f LogFormat) Find(line string, ref time.Time) (string, time.Time, bool) {
+	if f.Name == LogFormatAuto {
+		for _, format := range logFormats {
+			if value, t, ok := format.Find(line, ref); ok {
+				return value, t, true
+			}
+		}
+		return "", time.Time{}, false
+	}
+
+	for _, matches := range f.find.FindAllStringSubmatch(line, -1) {
+		if t, err := f.parse(matches[1], ref); err == nil {
+			return matches[1], t, true
+		}
+	}
+
+	return "", time.Time{}, false
+}
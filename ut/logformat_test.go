@@ -0,0 +1,123 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestLogFormatParse(t *testing.T) {
+	ref := time.Date(2023, 4, 6, 12, 0, 0, 0, time.UTC)
+	want := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	tests := []struct {
+		format string
+		value  string
+		want   time.Time
+	}{
+		{"syslog", "Apr  5 17:50:44", want},
+		{"syslog", "Dec 31 23:00:00", time.Date(2022, 12, 31, 23, 0, 0, 0, time.UTC)},
+		{"rfc5424", "2023-04-05T19:50:44.123456+02:00", want.Add(123456 * time.Microsecond)},
+		{"clf", "[05/Apr/2023:12:50:44 -0500]", want},
+		{"clf", "05/Apr/2023:17:50:44 +0000", want},
+		{"journald", "__REALTIME_TIMESTAMP=1680717044000001", want.Add(time.Microsecond)},
+		{"journald", "1680717044000001", want.Add(time.Microsecond)},
+		{"klog", "I0405 17:50:44.123456", want.Add(123456 * time.Microsecond)},
+		{"klog", "0405 17:50:44.000000", want},
+		{"java", "Wed Apr 05 17:50:44 UTC 2023", want},
+		{"java", "Wed Apr 05 19:50:44 JST 2023", want.Add(-7 * time.Hour)},
+		{"java", "Wed Apr 05 10:50:44 PDT 2023", want},
+		{"java", "Wed Apr 05 19:50:44 CEST 2023", want},
+		{"java", "Wed Jan 04 19:50:44 CET 2023", time.Date(2023, 1, 4, 18, 50, 44, 0, time.UTC)},
+		{"java", "Wed Apr 05 18:50:44 BST 2023", want},
+		{"java", "4/5/23, 5:50 PM", want.Add(-44 * time.Second)},
+		{"go", "2023-04-05 17:50:44 +0000 UTC", want},
+		{"go", "2023-04-05 10:50:44.5 -0700 PDT m=+0.000012", want.Add(500 * time.Millisecond)},
+		{"auto", "[05/Apr/2023:17:50:44 +0000]", want},
+		{"auto", "2023-04-05 17:50:44 +0000 UTC", want},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.value, func(t *testing.T) {
+			f, err := LookupLogFormat(tt.format)
+			require.NoError(t, err)
+			got, err := f.Parse(tt.value, ref)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), got)
+		})
+	}
+
+	for _, tt := range []struct{ format, value string }{
+		{"syslog", "2023-04-05T17:50:44Z"},
+		{"clf", "05/Apr/2023:17:50:44"},
+		{"klog", "I0405 17:50:44"},
+		{"auto", "1680717044"},
+		{"java", "Wed Apr 05 17:50:44 XYZ 2023"},
+	} {
+		f, err := LookupLogFormat(tt.format)
+		require.NoError(t, err)
+		_, err = f.Parse(tt.value, ref)
+		assert.Error(t, err, tt.value)
+	}
+}
+
+func TestLogFormatParseJavaZone(t *testing.T) {
+	dublin, err := time.LoadLocation("Europe/Dublin")
+	require.NoError(t, err)
+	f, err := LookupLogFormat("java")
+	require.NoError(t, err)
+
+	// IST is Irish Standard Time in Dublin, India Standard Time elsewhere
+	got, err := f.Parse("Wed Apr 05 18:50:44 IST 2023", time.Date(2023, 4, 6, 12, 0, 0, 0, dublin))
+	require.NoError(t, err)
+	assert.True(t, time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC).Equal(got), got)
+
+	got, err = f.Parse("Wed Apr 05 23:20:44 IST 2023", time.Date(2023, 4, 6, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.True(t, time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC).Equal(got), got)
+}
+
+func TestLogFormatFind(t *testing.T) {
+	ref := time.Date(2023, 4, 6, 12, 0, 0, 0, time.UTC)
+	want := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	tests := []struct {
+		format string
+		line   string
+		value  string
+	}{
+		{"syslog", "Apr  5 17:50:44 host sshd[42]: Accepted key", "Apr  5 17:50:44"},
+		{"rfc5424", "<34>1 2023-04-05T17:50:44Z host app - - msg", "2023-04-05T17:50:44Z"},
+		{"clf", `127.0.0.1 - - [05/Apr/2023:17:50:44 +0000] "GET / HTTP/1.1" 200 2326`, "05/Apr/2023:17:50:44 +0000"},
+		{"journald", `{"__REALTIME_TIMESTAMP" : "1680717044000000", "MESSAGE": "hi"}`, "1680717044000000"},
+		{"klog", "I0405 17:50:44.000000    1 main.go:42] started", "0405 17:50:44.000000"},
+		{"java", "at Wed Apr 05 17:50:44 GMT 2023: started", "Wed Apr 05 17:50:44 GMT 2023"},
+		{"go", "started at 2023-04-05 17:50:44 +0000 UTC m=+0.01", "2023-04-05 17:50:44 +0000 UTC"},
+		{"auto", `127.0.0.1 - - [05/Apr/2023:17:50:44 +0000] "GET / HTTP/1.1" 200`, "05/Apr/2023:17:50:44 +0000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			f, err := LookupLogFormat(tt.format)
+			require.NoError(t, err)
+			value, got, ok := f.Find(tt.line, ref)
+			require.True(t, ok)
+			assert.Equal(t, tt.value, value)
+			assert.True(t, want.Equal(got), got)
+		})
+	}
+
+	f, err := LookupLogFormat("clf")
+	require.NoError(t, err)
+	_, _, ok := f.Find("no timestamp here", ref)
+	assert.False(t, ok)
+}
+
+func TestLookupLogFormat(t *testing.T) {
+	f, err := LookupLogFormat("CLF")
+	require.NoError(t, err)
+	assert.Equal(t, "clf", f.Name)
+
+	_, err = LookupLogFormat("apache")
+	assert.ErrorIs(t, err, ErrUnknownLogFormat)
+	assert.Equal(t, len(LogFormats())+1, len(LogFormatNames()))
+}
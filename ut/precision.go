@@ -0,0 +1,172 @@
+package ut
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Precision is the unit of an epoch value.
+type Precision int
+
+const (
+	Second Precision = iota
+	Millisecond
+	Microsecond
+	Nanosecond
+)
+
+func (p Precision) String() string {
+	switch p {
+	case Second:
+		return "second"
+	case Millisecond:
+		return "millisecond"
+	case Microsecond:
+		return "microsecond"
+	case Nanosecond:
+		return "nanosecond"
+	}
+
+	return fmt.Sprintf("Precision(%d)", int(p))
+}
+
+// PrecisionNames lists the canonical precision names accepted by ParsePrecision.
+func PrecisionNames() []string {
+	return []string{"second", "millisecond", "microsecond", "nanosecond", "milli", "micro", "nano", "s", "ms", "us", "ns"}
+}
+
+// ParsePrecision converts a precision name to a Precision. An empty name
+// means seconds.
+func ParsePrecision(value string) (Precision, error) {
+	switch value {
+	case "", "second", "s":
+		return Second, nil
+	case "millisecond", "milli", "ms":
+		return Millisecond, nil
+	case "microsecond", "micro", "us", "μs", "µs": // includes U+03BC (Greek letter mu) and U+00B5 (micro symbol)
+		return Microsecond, nil
+	case "nanosecond", "nano", "ns":
+		return Nanosecond, nil
+	}
+
+	return Second, valueError(ErrUnknownPrecision, value)
+}
+
+// InferPrecision guesses the precision of an epoch value from its number of
+// digits, assuming a date between 1973 and 5138: up to 11 digits are seconds,
+// up to 14 milliseconds, up to 17 microseconds and more are nanoseconds.
+func InferPrecision(value string) Precision {
+	value = strings.TrimLeft(strings.TrimSpace(value), "+-")
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		value = value[:i]
+	}
+
+	switch digits := len(value); {
+	case digits <= 11:
+		return Second
+	case digits <= 14:
+		return Millisecond
+	case digits <= 17:
+		return Microsecond
+	}
+
+	return Nanosecond
+}
+
+// MinTime and MaxTime bound the instants handled by the package, a billion
+// years around the common era. Epoch values of every family are converted
+// exactly within these bounds, using big integers when they do not fit in an
+// int64; values beyond them are rejected with ErrOutOfRange.
+var (
+	MinTime = time.Date(-999999999, 1, 1, 0, 0, 0, 0, time.UTC)
+	MaxTime = time.Date(999999999, 12, 31, 23, 59, 59, 999999999, time.UTC)
+)
+
+// checkRange returns ErrOutOfRange when t is outside MinTime and MaxTime.
+func checkRange(t time.Time) error {
+	if t.Before(MinTime) || t.After(MaxTime) {
+		return rangeError(t.UTC().String())
+	}
+	return nil
+}
+
+// rangeError reports a value beyond MinTime and MaxTime.
+func rangeError(value string) error {
+	return fmt.Errorf("%w (supported years are %d to %d)", valueError(ErrOutOfRange, value), MinTime.Year(), MaxTime.Year())
+}
+
+// Range returns the first and last instants whose epoch value in the
+// precision fits in an int64, within MinTime and MaxTime. These are the bounds
+// of FromEpoch and ToEpoch:
+//
+//	second       years -999999999 to 999999999
+//	millisecond  years -292275055 to 292278994
+//	microsecond  years -290308 to 294247
+//	nanosecond   1677-09-21 to 2262-04-11
+func (p Precision) Range() (time.Time, time.Time) {
+	switch p {
+	case Millisecond:
+		return time.UnixMilli(math.MinInt64).UTC(), time.UnixMilli(math.MaxInt64).UTC()
+	case Microsecond:
+		return time.UnixMicro(math.MinInt64).UTC(), time.UnixMicro(math.MaxInt64).UTC()
+	case Nanosecond:
+		return time.Unix(0, math.MinInt64).UTC(), time.Unix(0, math.MaxInt64).UTC()
+	}
+
+	return MinTime, MaxTime
+}
+
+// FromEpoch returns the local time corresponding to the given epoch value.
+// Values in seconds beyond MinTime and MaxTime return ErrOutOfRange.
+func FromEpoch(value int64, p Precision) (time.Time, error) {
+	switch p {
+	case Millisecond:
+		return time.UnixMilli(value), nil
+	case Microsecond:
+		return time.UnixMicro(value), nil
+	case Nanosecond:
+		return time.Unix(0, value), nil
+	}
+
+	if value < MinTime.Unix() || value > MaxTime.Unix() {
+		return time.Time{}, rangeError(strconv.FormatInt(value, 10))
+	}
+	return time.Unix(value, 0), nil
+}
+
+// ToEpoch returns t as an epoch value in the given precision, rounded down.
+// Instants outside the range of the precision return ErrOutOfRange; use
+// EpochUnix.Format for a value of any size.
+func ToEpoch(t time.Time, p Precision) (int64, error) {
+	if first, last := p.Range(); t.Before(first) || t.After(last) {
+		return 0, fmt.Errorf("%w (%ss cover %s to %s)", valueError(ErrOutOfRange, t.UTC().String()), p, first.Format(time.RFC3339Nano), last.Format(time.RFC3339Nano))
+	}
+
+	switch p {
+	case Millisecond:
+		return t.UnixMilli(), nil
+	case Microsecond:
+		return t.UnixMicro(), nil
+	case Nanosecond:
+		return t.UnixNano(), nil
+	}
+
+	return t.Unix(), nil
+}
+
+var integerMatch = regexp.MustCompile(`^[+-]?\d+$`)
+
+// ParseEpoch parses a decimal epoch value in the given precision. Values of
+// any size are accepted, as long as they are between MinTime and MaxTime.
+func ParseEpoch(value string, p Precision) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if !integerMatch.MatchString(value) {
+		return time.Time{}, valueError(ErrInvalidTimestamp, value)
+	}
+
+	return EpochUnix.Parse(value, p)
+}
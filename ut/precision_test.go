@@ -0,0 +1,148 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParsePrecision(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Precision
+	}{
+		{"", Second},
+		{"s", Second},
+		{"ms", Millisecond},
+		{"milli", Millisecond},
+		{"µs", Microsecond},
+		{"μs", Microsecond},
+		{"ns", Nanosecond},
+	}
+
+	for _, test := range tests {
+		p, err := ParsePrecision(test.input)
+		require.NoErrorf(t, err, "parsing %q", test.input)
+		assert.Equal(t, test.expected, p)
+	}
+
+	_, err := ParsePrecision("fortnight")
+	assert.ErrorIs(t, err, ErrUnknownPrecision)
+}
+
+func TestEpochRoundTrip(t *testing.T) {
+	ref := time.Date(2020, 4, 28, 7, 42, 36, 238123456, time.UTC)
+
+	tests := []struct {
+		precision Precision
+		value     int64
+		truncated time.Duration
+	}{
+		{Second, 1588059756, time.Second},
+		{Millisecond, 1588059756238, time.Millisecond},
+		{Microsecond, 1588059756238123, time.Microsecond},
+		{Nanosecond, 1588059756238123456, time.Nanosecond},
+	}
+
+	for _, test := range tests {
+		value, err := ToEpoch(ref, test.precision)
+		require.NoError(t, err)
+		assert.Equal(t, test.value, value)
+
+		parsed, err := FromEpoch(test.value, test.precision)
+		require.NoError(t, err)
+		assert.True(t, ref.Truncate(test.truncated).Equal(parsed))
+	}
+}
+
+func TestParseEpoch(t *testing.T) {
+	v, err := ParseEpoch("1680704033", Second)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1680704033), v.Unix())
+
+	_, err = ParseEpoch("abc", Second)
+	assert.ErrorIs(t, err, ErrInvalidTimestamp)
+}
+
+func TestPrecisionRange(t *testing.T) {
+	for p := Second; p <= Nanosecond; p++ {
+		first, last := p.Range()
+
+		_, err := ToEpoch(first, p)
+		assert.NoErrorf(t, err, "first %s", p)
+		_, err = ToEpoch(last, p)
+		assert.NoErrorf(t, err, "last %s", p)
+
+		_, err = ToEpoch(first.Add(-time.Nanosecond), p)
+		assert.ErrorIsf(t, err, ErrOutOfRange, "before first %s", p)
+		_, err = ToEpoch(last.Add(time.Nanosecond), p)
+		assert.ErrorIsf(t, err, ErrOutOfRange, "after last %s", p)
+	}
+
+	first, last := Nanosecond.Range()
+	assert.Equal(t, time.Date(1677, 9, 21, 0, 12, 43, 145224192, time.UTC), first)
+	assert.Equal(t, time.Date(2262, 4, 11, 23, 47, 16, 854775807, time.UTC), last)
+
+	_, err := FromEpoch(math.MaxInt64, Second)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = FromEpoch(math.MinInt64, Second)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+
+	v, err := FromEpoch(math.MinInt64, Nanosecond)
+	require.NoError(t, err)
+	assert.True(t, first.Equal(v))
+}
+
+func TestParseEpochRange(t *testing.T) {
+	tests := []struct {
+		value     string
+		precision Precision
+		expected  time.Time
+	}{
+		{"-1", Second, time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{"-62135596800", Second, time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"-62167219200", Second, time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"253402300800", Second, time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"10413792000000000000", Nanosecond, time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"-11676096000000000000", Nanosecond, time.Date(1600, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"-9223372036854775809", Nanosecond, time.Date(1677, 9, 21, 0, 12, 43, 145224191, time.UTC)},
+		{"31556889832780799", Second, time.Date(999999999, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{"-31557014135596800", Second, MinTime},
+		{"31556889832780799999999999", Nanosecond, time.Date(999999999, 12, 31, 23, 59, 59, 999999999, time.UTC)},
+	}
+
+	for _, test := range tests {
+		actual, err := ParseEpoch(test.value, test.precision)
+		require.NoErrorf(t, err, "parsing %q", test.value)
+		assert.Truef(t, test.expected.Equal(actual), "parsing %q: expected %s, got %s", test.value, test.expected, actual.UTC())
+	}
+
+	for _, value := range []string{"31556889832780800", "-31557014135596801", "99999999999999999999999999999999", "31556889832780800000000000"} {
+		_, err := ParseEpoch(value, Second)
+		assert.ErrorIsf(t, err, ErrOutOfRange, "parsing %q", value)
+	}
+
+	_, err := ParseEpoch("1.5", Second)
+	assert.ErrorIs(t, err, ErrInvalidTimestamp)
+}
+
+func TestInferPrecision(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Precision
+	}{
+		{"0", Second},
+		{"1680717044", Second},
+		{"-1680717044.5", Second},
+		{"1680717044123", Millisecond},
+		{"1680717044123456", Microsecond},
+		{"1680717044123456789", Nanosecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, InferPrecision(tt.value))
+		})
+	}
+}
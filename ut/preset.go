@@ -0,0 +1,139 @@
+package ut
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FormatPreset is a named layout, accepted wherever a layout is.
+type FormatPreset struct {
+	Name   string
+	Layout string // a Go layout or a strftime format
+	// UTC formats times in UTC, whatever their zone, like HTTP dates.
+	UTC bool
+}
+
+// formatPresets are the built-in presets.
+var formatPresets = []FormatPreset{
+	{Name: "rfc3339", Layout: time.RFC3339},
+	{Name: "rfc3339nano", Layout: time.RFC3339Nano},
+	{Name: "rfc1123", Layout: time.RFC1123},
+	{Name: "rfc822", Layout: time.RFC822},
+	{Name: "rfc850", Layout: time.RFC850},
+	{Name: "kitchen", Layout: time.Kitchen},
+	{Name: "iso8601-basic", Layout: "20060102T150405Z0700"},
+	{Name: "http-date", Layout: "Mon, 02 Jan 2006 15:04:05 GMT", UTC: true},
+	{Name: "sql", Layout: "2006-01-02 15:04:05"},
+	{Name: "ansic", Layout: time.ANSIC},
+	{Name: "unixdate", Layout: time.UnixDate},
+	{Name: "rubydate", Layout: time.RubyDate},
+	{Name: "stamp", Layout: time.Stamp},
+	{Name: "email", Layout: time.RFC1123Z},
+}
+
+var (
+	userPresetsMu sync.RWMutex
+	userPresets   []FormatPreset
+)
+
+// FormatPresets returns the built-in presets followed by the ones installed
+// with SetFormatPresets.
+func FormatPresets() []FormatPreset {
+	userPresetsMu.RLock()
+	defer userPresetsMu.RUnlock()
+	return append(append([]FormatPreset(nil), formatPresets...), userPresets...)
+}
+
+// SetFormatPresets installs user-defined presets, which take precedence over
+// the built-in ones of the same name.
+func SetFormatPresets(presets []FormatPreset) {
+	userPresetsMu.Lock()
+	userPresets = presets
+	userPresetsMu.Unlock()
+}
+
+// LookupFormatPreset returns the preset with the given name, ignoring case.
+func LookupFormatPreset(name string) (FormatPreset, bool) {
+	userPresetsMu.RLock()
+	defer userPresetsMu.RUnlock()
+	for _, presets := range [][]FormatPreset{userPresets, formatPresets} {
+		for _, p := range presets {
+			if strings.EqualFold(name, p.Name) {
+				return p, true
+			}
+		}
+	}
+
+	return FormatPreset{}, false
+}
+
+// presetEntry is a value of a YAML preset file: a layout, or a layout with
+// its dialect and whether to format in UTC.
+type presetEntry struct {
+	Layout  string `yaml:"layout"`
+	Dialect string `yaml:"dialect"`
+	UTC     bool   `yaml:"utc"`
+}
+
+func (e *presetEntry) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		e.Layout = node.Value
+		return nil
+	}
+
+	type plain presetEntry
+	return node.Decode((*plain)(e))
+}
+
+// ParseFormatPresets reads presets from a YAML map of names to layouts, like
+//
+//	deploy: 2006-01-02 15:04:05.000 MST
+//	report:
+//	  layout: dd MMM yyyy
+//	  dialect: java
+//	  utc: true
+//
+// Layouts are Go layouts or strftime formats, unless a dialect is given.
+func ParseFormatPresets(r io.Reader) ([]FormatPreset, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[string]presetEntry
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormatPresets, err)
+	}
+
+	var names []string
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var presets []FormatPreset
+	for _, name := range names {
+		e := entries[name]
+		if e.Layout == "" {
+			return nil, fmt.Errorf("%w: preset %q has no layout", ErrInvalidFormatPresets, name)
+		}
+		layout := e.Layout
+		if e.Dialect != "" {
+			dialect, err := LookupDialect(e.Dialect)
+			if err != nil {
+				return nil, fmt.Errorf("%w: preset %q: %s", ErrInvalidFormatPresets, name, err)
+			}
+			if layout, err = FormatLayout(layout, dialect); err != nil {
+				return nil, fmt.Errorf("%w: preset %q: %s", ErrInvalidFormatPresets, name, err)
+			}
+		}
+		presets = append(presets, FormatPreset{Name: name, Layout: layout, UTC: e.UTC})
+	}
+
+	return presets, nil
+}
@@ -0,0 +1,62 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatPresets(t *testing.T) {
+	tm := time.Date(2023, 4, 5, 17, 50, 44, 123000000, time.FixedZone("", 2*3600))
+
+	tests := []struct {
+		preset string
+		want   string
+	}{
+		{"rfc3339", "2023-04-05T17:50:44+02:00"},
+		{"rfc3339nano", "2023-04-05T17:50:44.123+02:00"},
+		{"kitchen", "5:50PM"},
+		{"iso8601-basic", "20230405T175044+0200"},
+		{"http-date", "Wed, 05 Apr 2023 15:50:44 GMT"},
+		{"sql", "2023-04-05 17:50:44"},
+		{"email", "Wed, 05 Apr 2023 17:50:44 +0200"},
+		{"SQL", "2023-04-05 17:50:44"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.preset, func(t *testing.T) {
+			assert.Equal(t, tt.want, Format(tm, tt.preset))
+		})
+	}
+
+	got, err := ParseBase("Wed, 05 Apr 2023 15:50:44 GMT", "http-date", time.Time{})
+	require.NoError(t, err)
+	assert.True(t, tm.Truncate(time.Second).Equal(got), got)
+
+	got, err = ParseBase("2023-04-05 15:50:44", "sql", time.Time{})
+	require.NoError(t, err)
+	assert.True(t, tm.Truncate(time.Second).Equal(got), got)
+}
+
+func TestParseFormatPresets(t *testing.T) {
+	defer SetFormatPresets(nil)
+
+	presets, err := ParseFormatPresets(strings.NewReader("sql: \"2006-01-02\"\nreport:\n  layout: dd MMM yyyy\n  dialect: java\n  utc: true\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []FormatPreset{
+		{Name: "report", Layout: "02 Jan 2006", UTC: true},
+		{Name: "sql", Layout: "2006-01-02"},
+	}, presets)
+
+	SetFormatPresets(presets)
+	tm := time.Date(2023, 4, 5, 23, 0, 0, 0, time.FixedZone("", -2*3600))
+	assert.Equal(t, "2023-04-05", Format(tm, "sql"), "user presets take precedence")
+	assert.Equal(t, "06 Apr 2023", Format(tm, "report"))
+	assert.Len(t, FormatPresets(), len(formatPresets)+2)
+
+	for _, doc := range []string{"- 2006", "report: {dialect: java}", "report: {layout: yyyy, dialect: perl}", "report: {layout: yyyy SS, dialect: java}"} {
+		_, err := ParseFormatPresets(strings.NewReader(doc))
+		assert.ErrorIs(t, err, ErrInvalidFormatPresets, doc)
+	}
+}
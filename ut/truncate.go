@@ -0,0 +1,49 @@
+package ut
+
+import (
+	"time"
+)
+
+// Truncate names the unit a time is rounded down to.
+type Truncate string
+
+const (
+	TruncateNone   Truncate = ""
+	TruncateDay    Truncate = "day"
+	TruncateHour   Truncate = "hour"
+	TruncateMinute Truncate = "minute"
+	TruncateSecond Truncate = "second"
+)
+
+// TruncateNames lists the accepted truncate option names.
+func TruncateNames() []string {
+	return []string{string(TruncateDay), string(TruncateHour), string(TruncateMinute), string(TruncateSecond)}
+}
+
+// ParseTruncate validates the given truncate option name.
+func ParseTruncate(value string) (Truncate, error) {
+	switch v := Truncate(value); v {
+	case TruncateNone, TruncateDay, TruncateHour, TruncateMinute, TruncateSecond:
+		return v, nil
+	}
+
+	return TruncateNone, valueError(ErrUnknownTruncate, value)
+}
+
+// Truncate rounds t down to the unit. Days are truncated on UTC boundaries.
+func (opt Truncate) Truncate(t time.Time) (time.Time, error) {
+	switch opt {
+	case TruncateDay:
+		return t.Truncate(time.Hour * 24), nil
+	case TruncateHour:
+		return t.Truncate(time.Hour), nil
+	case TruncateMinute:
+		return t.Truncate(time.Minute), nil
+	case TruncateSecond:
+		return t.Truncate(time.Second), nil
+	case TruncateNone:
+		return t, nil
+	}
+
+	return t, valueError(ErrUnknownTruncate, string(opt))
+}
@@ -0,0 +1,35 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestTruncate(t *testing.T) {
+	ref := time.Date(2023, 4, 5, 17, 50, 44, 123, time.UTC)
+
+	tests := []struct {
+		option   Truncate
+		expected time.Time
+	}{
+		{TruncateNone, ref},
+		{TruncateSecond, time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)},
+		{TruncateMinute, time.Date(2023, 4, 5, 17, 50, 0, 0, time.UTC)},
+		{TruncateHour, time.Date(2023, 4, 5, 17, 0, 0, 0, time.UTC)},
+		{TruncateDay, time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		actual, err := test.option.Truncate(ref)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, actual)
+	}
+
+	_, err := Truncate("week").Truncate(ref)
+	assert.ErrorIs(t, err, ErrUnknownTruncate)
+
+	_, err = ParseTruncate("week")
+	assert.ErrorIs(t, err, ErrUnknownTruncate)
+}
@@ -0,0 +1,131 @@
+package ut
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	_ "time/tzdata"
+)
+
+var offsetToTZ = map[string]string{
+	"JST": "Asia/Tokyo",
+}
+
+// zoneAbbreviations are the offsets of the abbreviations printed by common
+// tools, like java.util.Date, in place of an offset. Ambiguous ones, like
+// CST, are read as the North American zone.
+var zoneAbbreviations = map[string]int{
+	"UTC": 0, "GMT": 0, "WET": 0, "WEST": 1 * 3600, "BST": 1 * 3600,
+	"CET": 1 * 3600, "CEST": 2 * 3600, "EET": 2 * 3600, "EEST": 3 * 3600, "MSK": 3 * 3600,
+	"IST": 5*3600 + 1800, "SGT": 8 * 3600, "HKT": 8 * 3600, "AWST": 8 * 3600,
+	"JST": 9 * 3600, "KST": 9 * 3600, "ACST": 9*3600 + 1800, "ACDT": 10*3600 + 1800,
+	"AEST": 10 * 3600, "AEDT": 11 * 3600, "NZST": 12 * 3600, "NZDT": 13 * 3600,
+	"HST": -10 * 3600, "AKST": -9 * 3600, "AKDT": -8 * 3600,
+	"PST": -8 * 3600, "PDT": -7 * 3600, "MST": -7 * 3600, "MDT": -6 * 3600,
+	"CST": -6 * 3600, "CDT": -5 * 3600, "EST": -5 * 3600, "EDT": -4 * 3600,
+	"AST": -4 * 3600, "ADT": -3 * 3600, "NST": -3*3600 - 1800, "NDT": -2*3600 - 1800,
+}
+
+// abbreviatedZone returns the location of the wall time of t whose zone is
+// abbreviated as name: the location of t when the abbreviation is its own at
+// that time, or a fixed zone with the offset of a known abbreviation.
+func abbreviatedZone(name string, t time.Time) (*time.Location, bool) {
+	local := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if abbr, _ := local.Zone(); abbr == name {
+		return t.Location(), true
+	}
+	if offset, ok := zoneAbbreviations[name]; ok {
+		return time.FixedZone(name, offset), true
+	}
+
+	return nil, false
+}
+
+var smallOffsetMatch = regexp.MustCompile(`^(\d{1}):(\d{2})$`)
+var timeOffsetMatch = regexp.MustCompile(`^([+-]?)(\d{1,2}):(\d{2})$`)
+var timeHundredOffsetMatch = regexp.MustCompile(`^([+-]?)(\d{3,4})$`)
+
+// SanitizeOffset normalizes an offset in the form [+-]H:MM to [-]HH:MM.
+// Any other value is returned unchanged.
+func SanitizeOffset(offset string) string {
+	if offset == "" {
+		return offset
+	}
+
+	var negative bool
+	if timeOffsetMatch.MatchString(offset) {
+		if offset[0] == '+' || offset[0] == '-' {
+			negative = offset[0] == '-'
+			offset = offset[1:]
+		}
+		if smallOffsetMatch.MatchString(offset) {
+			offset = "0" + offset
+		}
+		if negative {
+			offset = "-" + offset
+		}
+	}
+
+	return offset
+}
+
+// fixedOffset returns a fixed zone for the given offset, named after the
+// offset value, e.g. "(+900)" or "(-300)".
+func fixedOffset(hours, minutes int) *time.Location {
+	seconds := hours*3600 + minutes*60
+
+	offsetName := fmt.Sprintf("%d", hours*100+minutes)
+	if hours > 0 {
+		offsetName = "+" + offsetName
+	}
+	offsetName = "(" + offsetName + ")"
+
+	return time.FixedZone(offsetName, seconds)
+}
+
+// LoadZone resolves the given value to a location. It accepts offsets in the
+// forms [+-]H:MM, [+-]HH:MM and [+-]HHMM, a few well known abbreviations
+// (like JST) and any IANA time zone name.
+func LoadZone(offset string) (*time.Location, error) {
+	offset = SanitizeOffset(offset)
+
+	if matches := timeOffsetMatch.FindStringSubmatch(offset); len(matches) > 0 {
+		hours, _ := strconv.Atoi(matches[2])
+		minutes, _ := strconv.Atoi(matches[3])
+
+		if matches[1] == "-" {
+			hours = -hours
+			minutes = -minutes
+		}
+
+		return fixedOffset(hours, minutes), nil
+	}
+
+	if matches := timeHundredOffsetMatch.FindStringSubmatch(offset); len(matches) > 0 {
+		value, _ := strconv.Atoi(matches[2])
+
+		minutes := value % 100
+		hours := value / 100
+
+		if matches[1] == "-" {
+			hours = -hours
+			minutes = -minutes
+		}
+
+		return fixedOffset(hours, minutes), nil
+	}
+
+	name := offset
+	if mapped, ok := offsetToTZ[name]; ok {
+		name = mapped
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, valueError(ErrUnknownZone, offset)
+	}
+
+	return loc, nil
+}
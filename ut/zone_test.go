@@ -0,0 +1,56 @@
+package ut
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSanitizeOffset(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"9:00", "09:00"},
+		{"+9:00", "09:00"},
+		{"-3:00", "-03:00"},
+		{"-03:00", "-03:00"},
+		{"0900", "0900"},
+		{"Asia/Tokyo", "Asia/Tokyo"},
+	}
+
+	for _, test := range tests {
+		assert.Equalf(t, test.expected, SanitizeOffset(test.input), "sanitizing %q", test.input)
+	}
+}
+
+func TestLoadZone(t *testing.T) {
+	tests := []struct {
+		input  string
+		name   string
+		offset int
+	}{
+		{"9:00", "(+900)", 9 * 3600},
+		{"-0300", "(-300)", -3 * 3600},
+		{"+0530", "(+530)", 5*3600 + 30*60},
+		{"JST", "Asia/Tokyo", 9 * 3600},
+		{"UTC", "UTC", 0},
+	}
+
+	for _, test := range tests {
+		loc, err := LoadZone(test.input)
+		require.NoErrorf(t, err, "loading %q", test.input)
+		assert.Equal(t, test.name, loc.String())
+	}
+
+	_, err := LoadZone("Mars/Olympus_Mons")
+	assert.ErrorIs(t, err, ErrUnknownZone)
+}
+
+func TestZoneNames(t *testing.T) {
+	names := ZoneNames()
+	assert.Contains(t, names, "Asia/Tokyo")
+	assert.Contains(t, names, "JST")
+	assert.NotContains(t, names, "zone.tab")
+}
@@ -0,0 +1,90 @@
+package ut
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// zoneDirs are the places zoneinfo databases are commonly installed.
+var zoneDirs = []string{
+	"/usr/share/zoneinfo/",
+	"/usr/share/lib/zoneinfo/",
+	"/usr/lib/locale/TZ/",
+	"/etc/zoneinfo/",
+}
+
+// commonZones is used when no zoneinfo database can be found on the system.
+var commonZones = []string{
+	"UTC",
+	"Africa/Cairo", "Africa/Johannesburg", "Africa/Lagos", "Africa/Nairobi",
+	"America/Anchorage", "America/Argentina/Buenos_Aires", "America/Bogota", "America/Chicago",
+	"America/Denver", "America/Halifax", "America/Los_Angeles", "America/Mexico_City",
+	"America/New_York", "America/Phoenix", "America/Sao_Paulo", "America/St_Johns",
+	"America/Toronto", "America/Vancouver",
+	"Asia/Bangkok", "Asia/Dubai", "Asia/Hong_Kong", "Asia/Jakarta", "Asia/Jerusalem",
+	"Asia/Kolkata", "Asia/Manila", "Asia/Seoul", "Asia/Shanghai", "Asia/Singapore",
+	"Asia/Taipei", "Asia/Tehran", "Asia/Tokyo",
+	"Atlantic/Reykjavik",
+	"Australia/Adelaide", "Australia/Brisbane", "Australia/Melbourne", "Australia/Perth", "Australia/Sydney",
+	"Europe/Amsterdam", "Europe/Athens", "Europe/Berlin", "Europe/Dublin", "Europe/Istanbul",
+	"Europe/Lisbon", "Europe/London", "Europe/Madrid", "Europe/Moscow", "Europe/Paris",
+	"Europe/Rome", "Europe/Stockholm", "Europe/Warsaw", "Europe/Zurich",
+	"Pacific/Auckland", "Pacific/Honolulu",
+}
+
+var (
+	zoneNamesOnce sync.Once
+	zoneNames     []string
+)
+
+// ZoneNames returns the sorted list of known IANA zone names, read from the
+// system zoneinfo database when available. Abbreviations accepted by LoadZone
+// are included as well.
+func ZoneNames() []string {
+	zoneNamesOnce.Do(func() {
+		seen := map[string]bool{}
+		for _, dir := range zoneDirs {
+			_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return nil
+				}
+				name := strings.TrimPrefix(path, dir)
+				if isZoneName(name) {
+					seen[name] = true
+				}
+				return nil
+			})
+			if len(seen) > 0 {
+				break
+			}
+		}
+		if len(seen) == 0 {
+			for _, name := range commonZones {
+				seen[name] = true
+			}
+		}
+		for abbr := range offsetToTZ {
+			seen[abbr] = true
+		}
+
+		for name := range seen {
+			zoneNames = append(zoneNames, name)
+		}
+		sort.Strings(zoneNames)
+	})
+
+	return zoneNames
+}
+
+// isZoneName filters out the data files shipped next to the zone files.
+func isZoneName(name string) bool {
+	if name == "" || !unicode.IsUpper(rune(name[0])) || strings.Contains(name, ".") || name == "SECURITY" {
+		return false
+	}
+
+	return !strings.HasPrefix(name, "posix/") && !strings.HasPrefix(name, "right/")
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/lsmoura/ut-cli/ut"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultWatchInterval = time.Second
+
+// parseInterval parses a watch interval, either as a Go duration ("500ms",
+// "1m") or as a number of seconds.
+func parseInterval(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, numErr := strconv.ParseFloat(value, 64)
+		if numErr != nil {
+			return 0, usageError("invalid interval: %q", value)
+		}
+		d = time.Duration(seconds * float64(time.Second))
+	}
+
+	if d <= 0 {
+		return 0, usageError("invalid interval: %q", value)
+	}
+
+	return d, nil
+}
+
+// looksLikeInterval reports whether value is meant as an interval, valid or not.
+func looksLikeInterval(value string) bool {
+	if _, err := time.ParseDuration(value); err == nil {
+		return true
+	}
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+// truncateUnit returns the duration of the truncate option, or 0 when not truncating.
+func truncateUnit(opt TruncateOption) time.Duration {
+	switch opt {
+	case TruncateOptionDay:
+		return 24 * time.Hour
+	case TruncateOptionHour:
+		return time.Hour
+	case TruncateOptionMinute:
+		return time.Minute
+	case TruncateOptionSecond:
+		return time.Second
+	}
+
+	return 0
+}
+
+// watchInterval returns the tick interval of the watch mode. Without an
+// explicit interval, ticks follow the truncate unit.
+func watchInterval(o GenerateOptions) (time.Duration, error) {
+	if o.watch != "" {
+		return parseInterval(o.watch)
+	}
+
+	if unit := truncateUnit(o.truncate); unit > 0 {
+		return unit, nil
+	}
+
+	return defaultWatchInterval, nil
+}
+
+// nextTick returns the first multiple of interval after now. Multiples are
+// counted from the unix epoch, so ticks land on the same boundaries as
+// truncation does.
+func nextTick(now time.Time, interval time.Duration) time.Time {
+	return now.Truncate(interval).Add(interval)
+}
+
+// watchLine renders the line printed on every tick: the epoch followed by the
+// formatted time in each of the requested zones.
+func watchLine(o GenerateOptions, now time.Time, epoch ut.Epoch, precision ut.Precision) (string, error) {
+	t, err := generateTime(o, now)
+	if err != nil {
+		return "", err
+	}
+
+	if tmpl, ok, err := o.options.Template(); err != nil {
+		return "", err
+	} else if ok {
+		return renderTemplate(tmpl, o.base, t, now)
+	}
+
+	format, _ := o.options.Format()
+	fields := []string{epoch.Format(t, precision)}
+	if len(o.zones) == 0 {
+		fields = append(fields, ut.Format(t, format))
+	}
+	for _, zone := range o.zones {
+		zt, err := inZone(t, false, zone)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, ut.Format(zt, format))
+	}
+
+	return strings.Join(fields, "  "), nil
+}
+
+// waitFor waits for d to pass, reporting false when ctx is done first.
+func waitFor(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// watch prints the generated timestamp on every tick until ctx is done. When
+// inPlace is set, the line is redrawn instead of printing a new one. The clock
+// is read with now and waited on with wait, time.Now and waitFor outside of
+// tests.
+func watch(ctx context.Context, w io.Writer, o GenerateOptions, inPlace bool, now func() time.Time, wait func(context.Context, time.Duration) bool) error {
+	switch o.base {
+	case "", "now", "today":
+	default:
+		return usageError("--watch always starts from the current time, --base %q cannot be used", o.base)
+	}
+
+	interval, err := watchInterval(o)
+	if err != nil {
+		return err
+	}
+
+	epoch, precision, err := o.options.EpochPrecision()
+	if err != nil {
+		return err
+	}
+
+	t := now().Truncate(interval) // aligned like the following ticks
+	for {
+		line, err := watchLine(o, t, epoch, precision)
+		if err != nil {
+			return err
+		}
+
+		if inPlace {
+			_, err = fmt.Fprintf(w, "\r%s\x1b[K", line)
+		} else {
+			_, err = fmt.Fprintln(w, line)
+		}
+		if err != nil {
+			return ioError(err)
+		}
+
+		start := now()
+		next := nextTick(start, interval)
+		if !wait(ctx, next.Sub(start)) {
+			if inPlace {
+				fmt.Fprintln(w)
+			}
+			return nil
+		}
+		t = next
+	}
+}
+
+// watchStdout runs watch on stdout until interrupted, redrawing in place when
+// stdout is a terminal.
+func watchStdout(o GenerateOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return watch(ctx, os.Stdout, o, isTerminal(os.Stdout), time.Now, waitFor)
+}
+
+// isTerminal reports whether f is a character device, like a terminal.
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && (stat.Mode()&os.ModeCharDevice) != 0
+}
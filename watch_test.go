@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"github.com/lsmoura/ut-cli/ut"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"1s", time.Second},
+		{"500ms", 500 * time.Millisecond},
+		{"2", 2 * time.Second},
+		{"0.25", 250 * time.Millisecond},
+	}
+
+	for _, test := range tests {
+		d, err := parseInterval(test.input)
+		require.NoErrorf(t, err, "parsing %q", test.input)
+		assert.Equal(t, test.expected, d)
+	}
+
+	for _, input := range []string{"0s", "-1s", "soon"} {
+		_, err := parseInterval(input)
+		assert.Errorf(t, err, "expected error for %q", input)
+	}
+}
+
+func TestWatchInterval(t *testing.T) {
+	d, err := watchInterval(GenerateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, d)
+
+	d, err = watchInterval(GenerateOptions{truncate: TruncateOptionMinute})
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, d)
+
+	d, err = watchInterval(GenerateOptions{truncate: TruncateOptionMinute, watch: "5s"})
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, d)
+}
+
+func TestNextTick(t *testing.T) {
+	now := time.Date(2023, 4, 5, 17, 50, 44, 300, time.UTC)
+
+	assert.Equal(t, time.Date(2023, 4, 5, 17, 50, 45, 0, time.UTC), nextTick(now, time.Second))
+	assert.Equal(t, time.Date(2023, 4, 5, 17, 51, 0, 0, time.UTC), nextTick(now, time.Minute))
+	assert.Equal(t, time.Date(2023, 4, 5, 17, 51, 0, 0, time.UTC), nextTick(now, 20*time.Second))
+}
+
+func TestWatchLine(t *testing.T) {
+	now := time.Date(2023, 4, 5, 17, 50, 44, 0, time.UTC)
+
+	line, err := watchLine(GenerateOptions{options: Options{utc: true}}, now, ut.EpochUnix, ut.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "1680717044  2023-04-05 17:50:44 +0000 UTC", line)
+
+	o := GenerateOptions{
+		options:  Options{format: "%H:%M"},
+		truncate: TruncateOptionHour,
+		zones:    []string{"UTC", "Asia/Tokyo"},
+	}
+	line, err = watchLine(o, now, ut.EpochUnix, ut.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "1680714000  17:00  02:00", line)
+
+	_, err = watchLine(GenerateOptions{zones: []string{"Nowhere"}}, now, ut.EpochUnix, ut.Second)
+	assert.Error(t, err)
+}
+
+func TestWatch(t *testing.T) {
+	clock := time.Date(2023, 4, 5, 17, 50, 44, 123456789, time.UTC)
+	now := func() time.Time { return clock }
+	waits := 0
+	wait := func(ctx context.Context, d time.Duration) bool {
+		// timers fire a little late, and the context is done on the fourth wait
+		clock = clock.Add(d + 3*time.Millisecond)
+		waits++
+		return waits < 4
+	}
+
+	var buf strings.Builder
+	o := GenerateOptions{options: Options{utc: true, precision: "ms"}, watch: "50ms"}
+	require.NoError(t, watch(context.Background(), &buf, o, false, now, wait))
+
+	var epochs []string
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		epochs = append(epochs, strings.Fields(line)[0])
+	}
+	assert.Equal(t, []string{"1680717044100", "1680717044150", "1680717044200", "1680717044250"}, epochs, "ticks, the first one included, are aligned to the interval")
+
+	buf.Reset()
+	waits = 3
+	require.NoError(t, watch(context.Background(), &buf, o, true, now, wait))
+	assert.Equal(t, "\r1680717044300  2023-04-05 17:50:44.3 +0000 UTC\x1b[K\n", buf.String())
+
+	assert.Error(t, watch(context.Background(), &buf, GenerateOptions{base: "yesterday"}, false, now, wait))
+}
+
+func TestWaitFor(t *testing.T) {
+	assert.True(t, waitFor(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, waitFor(ctx, time.Hour))
+}
+
+func TestGenerateOptionsParseWatch(t *testing.T) {
+	var options GenerateOptions
+	remainingArgs, err := options.Parse(strings.Split("gen --watch 5s -z UTC -z Asia/Tokyo", " ")...)
+	require.NoError(t, err)
+
+	assert.True(t, options.Watching())
+	assert.Equal(t, "5s", options.watch)
+	assert.Equal(t, []string{"UTC", "Asia/Tokyo"}, options.zones)
+	assert.Len(t, remainingArgs, 0)
+
+	options = GenerateOptions{}
+	remainingArgs, err = options.Parse(strings.Split("gen -w help", " ")...)
+	require.NoError(t, err)
+	assert.True(t, options.Watching())
+	assert.Equal(t, "", options.watch)
+	assert.Equal(t, []string{"help"}, remainingArgs)
+}